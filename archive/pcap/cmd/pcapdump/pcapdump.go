@@ -0,0 +1,152 @@
+// pcapdump prints a one-line summary of each packet in pcap and pcapng files.
+//
+//      Usage: pcapdump [OPTION]... FILE...
+//      Print a summary of each packet in the provided pcap files.
+//
+//        -f="": Filter expression (e.g. "tcp and port 80").
+//
+//      The filter expression uses a subset of the tcpdump syntax: host, net,
+//      port, proto, tcp, udp, icmp, icmp6, ip, ip6, arp, combined using and,
+//      or, not and parentheses.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/mewmew/playground/archive/pcap"
+	"github.com/mewmew/playground/archive/pcap/filter"
+	"github.com/mewmew/playground/archive/pcap/layers"
+)
+
+// flagFilter corresponds to the filter expression.
+var flagFilter string
+
+func init() {
+	flag.StringVar(&flagFilter, "f", "", `Filter expression (e.g. "tcp and port 80").`)
+	flag.Usage = usage
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: pcapdump [OPTION]... FILE...")
+	fmt.Fprintln(os.Stderr, "Print a summary of each packet in the provided pcap files.")
+	fmt.Fprintln(os.Stderr)
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "The filter expression uses a subset of the tcpdump syntax: host, net,")
+	fmt.Fprintln(os.Stderr, "port, proto, tcp, udp, icmp, icmp6, ip, ip6, arp, combined using and,")
+	fmt.Fprintln(os.Stderr, "or, not and parentheses.")
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	f, err := filter.Compile(flagFilter)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, filePath := range flag.Args() {
+		err := dump(filePath, f)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
+
+// dump prints a summary of each packet in the provided pcap file which matches
+// the filter.
+func dump(filePath string, f filter.Filter) error {
+	pf, err := pcap.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer pf.Close()
+	r := filter.NewReader(pf, f)
+	for {
+		pkg, err := r.ReadPackage()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		p, err := layers.DecodePackage(pkg)
+		line := fmt.Sprintf("%s %s", pkg.Time().UTC().Format("2006-01-02 15:04:05.000000"), summary(p, len(pkg.Buf)))
+		if err != nil {
+			line += fmt.Sprintf(" [%v]", err)
+		}
+		fmt.Println(line)
+	}
+}
+
+// summary returns a one-line summary of the decoded packet, whose captured
+// length is n bytes.
+func summary(p *layers.Packet, n int) string {
+	switch {
+	case p.ARP != nil:
+		switch p.ARP.Op {
+		case 1:
+			return fmt.Sprintf("ARP who-has %v tell %v", ip(p.ARP.DstProtocolAddr), ip(p.ARP.SrcProtocolAddr))
+		case 2:
+			return fmt.Sprintf("ARP %v is-at %v", ip(p.ARP.SrcProtocolAddr), p.ARP.SrcHardwareAddr)
+		}
+		return fmt.Sprintf("ARP op %d", p.ARP.Op)
+	case p.IPv4 != nil || p.IPv6 != nil:
+		version := "IP"
+		if p.IPv6 != nil {
+			version = "IP6"
+		}
+		src, dst := p.SrcIP().String(), p.DstIP().String()
+		switch {
+		case p.TCP != nil:
+			return fmt.Sprintf("%s %s.%d > %s.%d: TCP [%s] seq %d ack %d win %d len %d", version, src, p.TCP.SrcPort, dst, p.TCP.DstPort, flags(p.TCP), p.TCP.Seq, p.TCP.Ack, p.TCP.Window, len(p.TCP.Payload))
+		case p.UDP != nil:
+			return fmt.Sprintf("%s %s.%d > %s.%d: UDP len %d", version, src, p.UDP.SrcPort, dst, p.UDP.DstPort, len(p.UDP.Payload))
+		case p.ICMP != nil:
+			return fmt.Sprintf("%s %s > %s: ICMP type %d code %d len %d", version, src, dst, p.ICMP.Type, p.ICMP.Code, len(p.ICMP.Payload))
+		}
+		proto, _ := p.Protocol()
+		return fmt.Sprintf("%s %s > %s: proto %d len %d", version, src, dst, proto, len(p.Payload))
+	case p.Ethernet != nil:
+		return fmt.Sprintf("%v > %v: ethertype 0x%04X len %d", p.Ethernet.Src, p.Ethernet.Dst, p.Ethernet.EtherType, n)
+	}
+	return fmt.Sprintf("len %d", n)
+}
+
+// flags returns the TCP flags in tcpdump notation; e.g. "S." for SYN-ACK.
+func flags(tcp *layers.TCP) string {
+	var s strings.Builder
+	if tcp.SYN() {
+		s.WriteString("S")
+	}
+	if tcp.FIN() {
+		s.WriteString("F")
+	}
+	if tcp.RST() {
+		s.WriteString("R")
+	}
+	if tcp.PSH() {
+		s.WriteString("P")
+	}
+	if tcp.URG() {
+		s.WriteString("U")
+	}
+	if tcp.ACK() {
+		s.WriteString(".")
+	}
+	return s.String()
+}
+
+// ip returns the string representation of the provided IP address.
+func ip(addr []byte) string {
+	return fmt.Sprint(net.IP(addr))
+}
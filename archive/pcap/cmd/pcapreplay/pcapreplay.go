@@ -0,0 +1,85 @@
+// pcapreplay replays the UDP payloads of pcap and pcapng files to a network
+// address.
+//
+//      Usage: pcapreplay [OPTION]... ADDR FILE...
+//      Replay the UDP payloads of the provided pcap files to ADDR (host:port).
+//
+//        -f="": Filter expression (e.g. "udp port 53").
+//        -loop=1: Number of times to replay the files; once if 0 and forever if
+//                 negative.
+//        -rate=0: Maximum number of packets per second; unlimited if 0.
+//        -speed=1: Speed multiplier of the original timing; as fast as possible
+//                  if 0.
+//
+//      Packets other than UDP packets are skipped.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"github.com/mewmew/playground/archive/pcap/filter"
+	"github.com/mewmew/playground/archive/pcap/replay"
+)
+
+var (
+	// flagFilter corresponds to the filter expression.
+	flagFilter string
+	// flagLoop corresponds to the number of times to replay the files.
+	flagLoop int
+	// flagRate corresponds to the maximum number of packets per second.
+	flagRate float64
+	// flagSpeed corresponds to the speed multiplier of the original timing.
+	flagSpeed float64
+)
+
+func init() {
+	flag.StringVar(&flagFilter, "f", "", `Filter expression (e.g. "udp port 53").`)
+	flag.IntVar(&flagLoop, "loop", 1, "Number of times to replay the files; once if 0 and forever if negative.")
+	flag.Float64Var(&flagRate, "rate", 0, "Maximum number of packets per second; unlimited if 0.")
+	flag.Float64Var(&flagSpeed, "speed", 1, "Speed multiplier of the original timing; as fast as possible if 0.")
+	flag.Usage = usage
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: pcapreplay [OPTION]... ADDR FILE...")
+	fmt.Fprintln(os.Stderr, "Replay the UDP payloads of the provided pcap files to ADDR (host:port).")
+	fmt.Fprintln(os.Stderr)
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Packets other than UDP packets are skipped.")
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	opts := replay.Options{
+		Speed: flagSpeed,
+		Rate:  flagRate,
+		Loop:  flagLoop,
+	}
+	if flagFilter != "" {
+		f, err := filter.Compile(flagFilter)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		opts.Filter = f
+	}
+	conn, err := net.Dial("udp", flag.Arg(0))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer conn.Close()
+	r := replay.NewReplayer(conn, opts)
+	err = r.ReplayFiles(flag.Args()[1:])
+	log.Printf("sent %d packets (%d bytes); skipped %d packets", r.Stats.Packets, r.Stats.Bytes, r.Stats.Skipped)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,401 @@
+// pcapstat prints summary statistics of pcap and pcapng files.
+//
+//      Usage: pcapstat [OPTION]... FILE...
+//      Print summary statistics of the provided pcap files.
+//
+//        -j=NCPU: Number of files to read concurrently.
+//        -json=false: Output statistics in JSON format.
+//        -n=10: Number of top talkers and ports to report.
+//
+//      The statistics of all files are merged into a single report.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/mewmew/playground/archive/pcap"
+	"github.com/mewmew/playground/archive/pcap/layers"
+)
+
+var (
+	// flagJobs corresponds to the number of files to read concurrently.
+	flagJobs int
+	// flagJSON specifies whether to output statistics in JSON format.
+	flagJSON bool
+	// flagTop corresponds to the number of top talkers and ports to report.
+	flagTop int
+)
+
+func init() {
+	flag.IntVar(&flagJobs, "j", runtime.NumCPU(), "Number of files to read concurrently.")
+	flag.BoolVar(&flagJSON, "json", false, "Output statistics in JSON format.")
+	flag.IntVar(&flagTop, "n", 10, "Number of top talkers and ports to report.")
+	flag.Usage = usage
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: pcapstat [OPTION]... FILE...")
+	fmt.Fprintln(os.Stderr, "Print summary statistics of the provided pcap files.")
+	fmt.Fprintln(os.Stderr)
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "The statistics of all files are merged into a single report.")
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	stats, err := statFiles(flag.Args(), flagJobs)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	report := stats.Report(flagTop)
+	if flagJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		err = enc.Encode(report)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// statFiles reads the provided pcap files, at most jobs files at a time, and
+// returns their merged statistics.
+func statFiles(filePaths []string, jobs int) (*Stats, error) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	type result struct {
+		stats *Stats
+		err   error
+	}
+	paths := make(chan string)
+	results := make(chan result)
+	for i := 0; i < jobs; i++ {
+		go func() {
+			for filePath := range paths {
+				stats, err := statFile(filePath)
+				results <- result{stats: stats, err: err}
+			}
+		}()
+	}
+	go func() {
+		for _, filePath := range filePaths {
+			paths <- filePath
+		}
+		close(paths)
+	}()
+
+	total := NewStats()
+	var err error
+	for range filePaths {
+		res := <-results
+		if res.err != nil {
+			if err == nil {
+				err = res.err
+			}
+			continue
+		}
+		total.Merge(res.stats)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return total, nil
+}
+
+// statFile returns the statistics of the provided pcap file.
+func statFile(filePath string) (*Stats, error) {
+	f, err := pcap.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	stats := NewStats()
+	for {
+		pkg, err := f.ReadPackage()
+		if err != nil {
+			if err == io.EOF {
+				return stats, nil
+			}
+			return nil, fmt.Errorf("%s: %v", filePath, err)
+		}
+		stats.Add(pkg)
+	}
+}
+
+// sizeBuckets specifies the upper bound (exclusive) of each packet size
+// bucket; the last bucket has no upper bound.
+var sizeBuckets = []int{64, 128, 256, 512, 1024, 1518}
+
+// A Counter records a number of packets and bytes.
+type Counter struct {
+	Packets int   `json:"packets"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// add adds a packet of n bytes to the counter.
+func (c *Counter) add(n int64) {
+	c.Packets++
+	c.Bytes += n
+}
+
+// Stats records summary statistics of captured packets.
+type Stats struct {
+	// Number of packets and bytes on the wire.
+	Total Counter
+	// Number of captured bytes.
+	Captured int64
+	// Time of the first and last packet.
+	First, Last time.Time
+	// Number of packets per size bucket.
+	Sizes []int
+	// Traffic per IP address, counting both sent and received packets.
+	Talkers map[string]*Counter
+	// Traffic per transport layer port; e.g. "tcp/80".
+	Ports map[string]*Counter
+	// Traffic per protocol; e.g. "TCP" or "ARP".
+	Protocols map[string]*Counter
+}
+
+// NewStats returns a new empty statistics record.
+func NewStats() *Stats {
+	return &Stats{
+		Sizes:     make([]int, len(sizeBuckets)+1),
+		Talkers:   make(map[string]*Counter),
+		Ports:     make(map[string]*Counter),
+		Protocols: make(map[string]*Counter),
+	}
+}
+
+// count adds a packet of n bytes to the counter of key in m.
+func count(m map[string]*Counter, key string, n int64) {
+	c, ok := m[key]
+	if !ok {
+		c = new(Counter)
+		m[key] = c
+	}
+	c.add(n)
+}
+
+// Add adds the provided packet to the statistics.
+func (s *Stats) Add(pkg *pcap.Package) {
+	n := int64(pkg.Hdr.OrigLen)
+	s.Total.add(n)
+	s.Captured += int64(len(pkg.Buf))
+	ts := pkg.Time()
+	if s.First.IsZero() || ts.Before(s.First) {
+		s.First = ts
+	}
+	if ts.After(s.Last) {
+		s.Last = ts
+	}
+	bucket := sort.SearchInts(sizeBuckets, int(pkg.Hdr.OrigLen)+1)
+	s.Sizes[bucket]++
+
+	// Packets that could not be fully decoded are still counted by the layers
+	// that were decoded.
+	p, _ := layers.DecodePackage(pkg)
+	count(s.Protocols, protocol(p), n)
+	if src := p.SrcIP(); src != nil {
+		dst := p.DstIP()
+		count(s.Talkers, src.String(), n)
+		if !dst.Equal(src) {
+			count(s.Talkers, dst.String(), n)
+		}
+	}
+	var transport string
+	switch {
+	case p.TCP != nil:
+		transport = "tcp"
+	case p.UDP != nil:
+		transport = "udp"
+	}
+	if transport != "" {
+		src, dst := p.SrcPort(), p.DstPort()
+		count(s.Ports, fmt.Sprintf("%s/%d", transport, src), n)
+		if dst != src {
+			count(s.Ports, fmt.Sprintf("%s/%d", transport, dst), n)
+		}
+	}
+}
+
+// protocol returns the name of the innermost decoded protocol of the packet.
+func protocol(p *layers.Packet) string {
+	switch {
+	case p.TCP != nil:
+		return "TCP"
+	case p.UDP != nil:
+		return "UDP"
+	case p.ICMP != nil && p.IPv6 != nil:
+		return "ICMPv6"
+	case p.ICMP != nil:
+		return "ICMP"
+	case p.ARP != nil:
+		return "ARP"
+	case p.IPv4 != nil:
+		return "IPv4 (other)"
+	case p.IPv6 != nil:
+		return "IPv6 (other)"
+	}
+	return "other"
+}
+
+// Merge merges the statistics of t into s.
+func (s *Stats) Merge(t *Stats) {
+	s.Total.Packets += t.Total.Packets
+	s.Total.Bytes += t.Total.Bytes
+	s.Captured += t.Captured
+	if !t.First.IsZero() && (s.First.IsZero() || t.First.Before(s.First)) {
+		s.First = t.First
+	}
+	if t.Last.After(s.Last) {
+		s.Last = t.Last
+	}
+	for i, n := range t.Sizes {
+		s.Sizes[i] += n
+	}
+	merge := func(dst, src map[string]*Counter) {
+		for key, c := range src {
+			d, ok := dst[key]
+			if !ok {
+				d = new(Counter)
+				dst[key] = d
+			}
+			d.Packets += c.Packets
+			d.Bytes += c.Bytes
+		}
+	}
+	merge(s.Talkers, t.Talkers)
+	merge(s.Ports, t.Ports)
+	merge(s.Protocols, t.Protocols)
+}
+
+// A Report is a summary of statistics, suitable for output.
+type Report struct {
+	Packets   int         `json:"packets"`
+	Bytes     int64       `json:"bytes"`
+	Captured  int64       `json:"captured_bytes"`
+	First     time.Time   `json:"first"`
+	Last      time.Time   `json:"last"`
+	Duration  float64     `json:"duration_seconds"`
+	Sizes     []SizeCount `json:"sizes"`
+	Talkers   []KeyCount  `json:"top_talkers"`
+	Ports     []KeyCount  `json:"top_ports"`
+	Protocols []KeyCount  `json:"protocols"`
+}
+
+// A SizeCount is the number of packets in a size bucket.
+type SizeCount struct {
+	// Lower and upper bound (inclusive) of the bucket; Max is -1 for the last
+	// bucket.
+	Min     int `json:"min"`
+	Max     int `json:"max"`
+	Packets int `json:"packets"`
+}
+
+// A KeyCount is the traffic of a talker, port or protocol.
+type KeyCount struct {
+	Key string `json:"key"`
+	Counter
+}
+
+// Report returns a report of the statistics, listing the top n talkers and
+// ports by number of bytes.
+func (s *Stats) Report(n int) *Report {
+	r := &Report{
+		Packets:   s.Total.Packets,
+		Bytes:     s.Total.Bytes,
+		Captured:  s.Captured,
+		First:     s.First,
+		Last:      s.Last,
+		Duration:  s.Last.Sub(s.First).Seconds(),
+		Talkers:   top(s.Talkers, n),
+		Ports:     top(s.Ports, n),
+		Protocols: top(s.Protocols, -1),
+	}
+	min := 0
+	for i, count := range s.Sizes {
+		max := -1
+		if i < len(sizeBuckets) {
+			max = sizeBuckets[i] - 1
+		}
+		r.Sizes = append(r.Sizes, SizeCount{Min: min, Max: max, Packets: count})
+		min = max + 1
+	}
+	return r
+}
+
+// top returns the n entries of m with the most bytes, or all entries if n is
+// negative. Ties are broken by key.
+func top(m map[string]*Counter, n int) []KeyCount {
+	kcs := make([]KeyCount, 0, len(m))
+	for key, c := range m {
+		kcs = append(kcs, KeyCount{Key: key, Counter: *c})
+	}
+	sort.Slice(kcs, func(i, j int) bool {
+		if kcs[i].Bytes != kcs[j].Bytes {
+			return kcs[i].Bytes > kcs[j].Bytes
+		}
+		return kcs[i].Key < kcs[j].Key
+	})
+	if n >= 0 && len(kcs) > n {
+		kcs = kcs[:n]
+	}
+	return kcs
+}
+
+// WriteText writes the report in plain text format to w.
+func (r *Report) WriteText(w io.Writer) error {
+	buf := new(bytes.Buffer)
+	const layout = "2006-01-02 15:04:05.000000"
+	fmt.Fprintf(buf, "Packets:  %d\n", r.Packets)
+	fmt.Fprintf(buf, "Bytes:    %d (%d captured)\n", r.Bytes, r.Captured)
+	if r.Packets > 0 {
+		fmt.Fprintf(buf, "First:    %s\n", r.First.UTC().Format(layout))
+		fmt.Fprintf(buf, "Last:     %s\n", r.Last.UTC().Format(layout))
+	}
+	fmt.Fprintf(buf, "Duration: %.6fs\n", r.Duration)
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "Packet sizes:")
+	for _, sc := range r.Sizes {
+		bucket := fmt.Sprintf("%d-%d", sc.Min, sc.Max)
+		if sc.Max == -1 {
+			bucket = fmt.Sprintf("%d+", sc.Min)
+		}
+		fmt.Fprintf(buf, "   %-10s %d\n", bucket, sc.Packets)
+	}
+	sections := []struct {
+		title string
+		kcs   []KeyCount
+	}{
+		{"Protocols", r.Protocols},
+		{"Top talkers", r.Talkers},
+		{"Top ports", r.Ports},
+	}
+	for _, sec := range sections {
+		fmt.Fprintln(buf)
+		fmt.Fprintf(buf, "%s:\n", sec.title)
+		for _, kc := range sec.kcs {
+			fmt.Fprintf(buf, "   %-24s %8d packets %10d bytes\n", kc.Key, kc.Packets, kc.Bytes)
+		}
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
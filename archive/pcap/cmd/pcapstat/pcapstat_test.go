@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+func ExampleReport_WriteText() {
+	// Merge the statistics of two capture files, read concurrently.
+	filePaths := []string{
+		"../../layers/testdata/ethernet.pcap",
+		"../../layers/testdata/sll.pcap",
+	}
+	stats, err := statFiles(filePaths, 2)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	report := stats.Report(3)
+	if err := report.WriteText(os.Stdout); err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// Packets:  7
+	// Bytes:    428 (415 captured)
+	// First:    2017-07-14 02:40:00.000000
+	// Last:     2017-07-14 02:40:05.500000
+	// Duration: 5.500000s
+	//
+	// Packet sizes:
+	//    0-63       5
+	//    64-127     2
+	//    128-255    0
+	//    256-511    0
+	//    512-1023   0
+	//    1024-1517  0
+	//    1518+      0
+	//
+	// Protocols:
+	//    TCP                             3 packets        190 bytes
+	//    UDP                             2 packets        118 bytes
+	//    ARP                             1 packets         60 bytes
+	//    ICMP                            1 packets         60 bytes
+	//
+	// Top talkers:
+	//    10.0.0.1                        5 packets        297 bytes
+	//    10.0.0.2                        5 packets        297 bytes
+	//    2001:db8::1                     1 packets         71 bytes
+	//
+	// Top ports:
+	//    tcp/49152                       3 packets        190 bytes
+	//    tcp/80                          3 packets        190 bytes
+	//    udp/53                          1 packets         71 bytes
+}
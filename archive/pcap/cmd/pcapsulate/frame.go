@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// Header lengths of synthetic frames.
+const (
+	ethernetLen = 14
+	ipv4Len     = 20
+	udpLen      = 8
+)
+
+// A udpFramer wraps payloads in synthetic Ethernet, IPv4 and UDP headers.
+type udpFramer struct {
+	// MAC addresses of the Ethernet header.
+	srcMAC, dstMAC net.HardwareAddr
+	// IPv4 addresses of the IP header.
+	srcIP, dstIP net.IP
+	// Ports of the UDP header.
+	srcPort, dstPort uint16
+	// Maximum transmission unit; the maximum length of each IP packet.
+	mtu int
+	// Identification field of the next IP packet.
+	id uint16
+}
+
+// newUDPFramer returns a new UDP framer using the provided MAC addresses,
+// source and destination addresses of the form "host:port" and maximum
+// transmission unit.
+func newUDPFramer(srcMAC, dstMAC, src, dst string, mtu int) (*udpFramer, error) {
+	if mtu < ipv4Len+udpLen+1 || mtu > 0xFFFF {
+		return nil, fmt.Errorf("invalid MTU %d; expected %d to %d", mtu, ipv4Len+udpLen+1, 0xFFFF)
+	}
+	uf := &udpFramer{mtu: mtu}
+	var err error
+	if uf.srcMAC, err = net.ParseMAC(srcMAC); err != nil {
+		return nil, err
+	}
+	if uf.dstMAC, err = net.ParseMAC(dstMAC); err != nil {
+		return nil, err
+	}
+	if len(uf.srcMAC) != 6 || len(uf.dstMAC) != 6 {
+		return nil, fmt.Errorf("invalid MAC address; expected 48-bit Ethernet address")
+	}
+	if uf.srcIP, uf.srcPort, err = parseAddr(src); err != nil {
+		return nil, err
+	}
+	if uf.dstIP, uf.dstPort, err = parseAddr(dst); err != nil {
+		return nil, err
+	}
+	return uf, nil
+}
+
+// parseAddr parses an IPv4 address and port of the form "host:port".
+func parseAddr(s string) (ip net.IP, port uint16, err error) {
+	addr, err := net.ResolveUDPAddr("udp4", s)
+	if err != nil {
+		return nil, 0, err
+	}
+	if ip = addr.IP.To4(); ip == nil {
+		return nil, 0, fmt.Errorf("invalid address %q; expected IPv4 address", s)
+	}
+	return ip, uint16(addr.Port), nil
+}
+
+// frames wraps the payload in Ethernet frames, splitting it into chunks which
+// fit within the MTU. An empty payload produces a single frame.
+func (uf *udpFramer) frames(payload []byte) [][]byte {
+	max := uf.mtu - ipv4Len - udpLen
+	var frames [][]byte
+	for {
+		n := len(payload)
+		if n > max {
+			n = max
+		}
+		frames = append(frames, uf.frame(payload[:n]))
+		payload = payload[n:]
+		if len(payload) == 0 {
+			return frames
+		}
+	}
+}
+
+// frame wraps the payload in a single Ethernet frame.
+func (uf *udpFramer) frame(payload []byte) []byte {
+	buf := make([]byte, ethernetLen+ipv4Len+udpLen+len(payload))
+
+	// Ethernet header.
+	eth := buf[:ethernetLen]
+	copy(eth[0:6], uf.dstMAC)
+	copy(eth[6:12], uf.srcMAC)
+	binary.BigEndian.PutUint16(eth[12:14], 0x0800)
+
+	// IPv4 header, with the don't fragment flag set.
+	ip := buf[ethernetLen : ethernetLen+ipv4Len]
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:4], uint16(ipv4Len+udpLen+len(payload)))
+	binary.BigEndian.PutUint16(ip[4:6], uf.id)
+	binary.BigEndian.PutUint16(ip[6:8], 0x4000)
+	ip[8] = 64
+	ip[9] = 17
+	copy(ip[12:16], uf.srcIP)
+	copy(ip[16:20], uf.dstIP)
+	binary.BigEndian.PutUint16(ip[10:12], checksum(0, ip))
+	uf.id++
+
+	// UDP header, with a checksum covering the IPv4 pseudo-header.
+	udp := buf[ethernetLen+ipv4Len:]
+	binary.BigEndian.PutUint16(udp[0:2], uf.srcPort)
+	binary.BigEndian.PutUint16(udp[2:4], uf.dstPort)
+	binary.BigEndian.PutUint16(udp[4:6], uint16(len(udp)))
+	copy(udp[udpLen:], payload)
+	var pseudo [12]byte
+	copy(pseudo[0:4], uf.srcIP)
+	copy(pseudo[4:8], uf.dstIP)
+	pseudo[9] = 17
+	binary.BigEndian.PutUint16(pseudo[10:12], uint16(len(udp)))
+	csum := checksum(sum(0, pseudo[:]), udp)
+	if csum == 0 {
+		// A zero UDP checksum denotes that no checksum was computed.
+		csum = 0xFFFF
+	}
+	binary.BigEndian.PutUint16(udp[6:8], csum)
+	return buf
+}
+
+// sum adds the 16-bit words of buf to the partial ones' complement sum s. An
+// odd trailing byte is padded with zero.
+func sum(s uint32, buf []byte) uint32 {
+	for ; len(buf) >= 2; buf = buf[2:] {
+		s += uint32(buf[0])<<8 | uint32(buf[1])
+	}
+	if len(buf) == 1 {
+		s += uint32(buf[0]) << 8
+	}
+	return s
+}
+
+// checksum returns the Internet checksum (RFC 1071) of buf, given the partial
+// sum s of preceding data.
+func checksum(s uint32, buf []byte) uint16 {
+	s = sum(s, buf)
+	for s > 0xFFFF {
+		s = s>>16 + s&0xFFFF
+	}
+	return ^uint16(s)
+}
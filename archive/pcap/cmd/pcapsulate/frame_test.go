@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mewmew/playground/archive/pcap"
+	"github.com/mewmew/playground/archive/pcap/layers"
+)
+
+func TestUDPFramer(t *testing.T) {
+	golden := []struct {
+		payload []byte
+		mtu     int
+		// Payload length of each frame.
+		want []int
+	}{
+		// i=0
+		{payload: []byte("hello"), mtu: 1500, want: []int{5}},
+		// i=1
+		{payload: nil, mtu: 1500, want: []int{0}},
+		// i=2
+		{payload: bytes.Repeat([]byte("abc"), 1000), mtu: 1500, want: []int{1472, 1472, 56}},
+		// i=3; odd payload lengths are padded when computing the checksum.
+		{payload: []byte("abcdefg"), mtu: 31, want: []int{3, 3, 1}},
+	}
+	for i, g := range golden {
+		uf, err := newUDPFramer("02:00:00:00:00:01", "02:00:00:00:00:02", "192.168.1.10:4000", "192.168.1.20:53", g.mtu)
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		frames := uf.frames(g.payload)
+		if len(frames) != len(g.want) {
+			t.Errorf("i=%d: expected %d frames, got %d.", i, len(g.want), len(frames))
+			continue
+		}
+		var got []byte
+		for j, frame := range frames {
+			p, err := layers.Decode(pcap.LinkTypeEthernet, frame)
+			if err != nil {
+				t.Errorf("i=%d, j=%d: %v", i, j, err)
+				continue
+			}
+			if p.IPv4 == nil || p.UDP == nil {
+				t.Errorf("i=%d, j=%d: expected IPv4 and UDP layers, got %+v.", i, j, p)
+				continue
+			}
+			if p.IPv4.ID != uint16(j) || !p.IPv4.DontFragment() {
+				t.Errorf("i=%d, j=%d: expected IP identification %d with don't fragment flag, got %d (flags %v).", i, j, j, p.IPv4.ID, p.IPv4.DontFragment())
+			}
+			if p.SrcIP().String() != "192.168.1.10" || p.DstIP().String() != "192.168.1.20" || p.SrcPort() != 4000 || p.DstPort() != 53 {
+				t.Errorf("i=%d, j=%d: unexpected addresses %v:%d > %v:%d.", i, j, p.SrcIP(), p.SrcPort(), p.DstIP(), p.DstPort())
+			}
+			if len(p.UDP.Payload) != g.want[j] {
+				t.Errorf("i=%d, j=%d: expected payload length %d, got %d.", i, j, g.want[j], len(p.UDP.Payload))
+			}
+			got = append(got, p.UDP.Payload...)
+
+			// The checksum of data including a valid checksum is zero.
+			ip := frame[ethernetLen : ethernetLen+ipv4Len]
+			if sum := checksum(0, ip); sum != 0 {
+				t.Errorf("i=%d, j=%d: invalid IPv4 header checksum; residue 0x%04X.", i, j, sum)
+			}
+			udp := frame[ethernetLen+ipv4Len:]
+			pseudo := append(append([]byte{}, ip[12:20]...), 0, 17, byte(len(udp)>>8), byte(len(udp)))
+			if sum := checksum(sum(0, pseudo), udp); sum != 0 {
+				t.Errorf("i=%d, j=%d: invalid UDP checksum; residue 0x%04X.", i, j, sum)
+			}
+		}
+		if !bytes.Equal(got, g.payload) {
+			t.Errorf("i=%d: reassembled payload mismatch; expected %q, got %q.", i, g.payload, got)
+		}
+	}
+}
+
+func TestNewUDPFramerInvalid(t *testing.T) {
+	golden := []struct {
+		srcMAC, dstMAC, src, dst string
+		mtu                      int
+	}{
+		// i=0
+		{srcMAC: "02:00:00:00:00:01", dstMAC: "02:00:00:00:00:02", src: "10.0.0.1:1", dst: "10.0.0.2:2", mtu: 28},
+		// i=1
+		{srcMAC: "foo", dstMAC: "02:00:00:00:00:02", src: "10.0.0.1:1", dst: "10.0.0.2:2", mtu: 1500},
+		// i=2
+		{srcMAC: "02:00:00:00:00:01", dstMAC: "02:00:00:00:00:02", src: "[::1]:1", dst: "10.0.0.2:2", mtu: 1500},
+		// i=3
+		{srcMAC: "02:00:00:00:00:01", dstMAC: "02:00:00:00:00:02", src: "10.0.0.1:1", dst: "10.0.0.2", mtu: 1500},
+	}
+	for i, g := range golden {
+		if _, err := newUDPFramer(g.srcMAC, g.dstMAC, g.src, g.dst, g.mtu); err == nil {
+			t.Errorf("i=%d: expected error, got nil.", i)
+		}
+	}
+}
@@ -1,39 +1,81 @@
 // pcapsulate encapsulates the provided files as packets in a pcap file.
 //
-//      Usage: pcapsulate [FILE]...
+//      Usage: pcapsulate [OPTION]... [FILE]...
 //      Encapsulate the provided files as packets in a pcap file.
 //
 //        -o="pcapsulate.pcap": Output path.
+//        -mode="raw": Encapsulation mode (raw or udp).
+//        -link=1: Data link type of raw packets (raw mode only).
+//        -src="10.0.0.1:1024": Source address of UDP packets.
+//        -dst="10.0.0.2:1024": Destination address of UDP packets.
+//        -srcmac="02:00:00:00:00:01": Source MAC address of UDP packets.
+//        -dstmac="02:00:00:00:00:02": Destination MAC address of UDP packets.
+//        -mtu=1500: Maximum length of IP packets in udp mode.
+//        -interval=0: Time between packets; use file modification times if 0.
+//
+//      In raw mode, the content of each file is stored verbatim as a packet of
+//      the given data link type. In udp mode, the content of each file is split
+//      into MTU-sized chunks, each wrapped in synthetic Ethernet, IPv4 and UDP
+//      headers with valid checksums.
 //
 //      With no FILE, or when FILE is -, read standard input.
 package main
 
 import (
-	"encoding/binary"
+	"bufio"
 	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"math"
 	"os"
+	"time"
 
 	"github.com/mewmew/playground/archive/pcap"
 )
 
-// flagOutput corresponds to the output path.
-var flagOutput string
+var (
+	// flagOutput corresponds to the output path.
+	flagOutput string
+	// flagMode corresponds to the encapsulation mode.
+	flagMode string
+	// flagLinkType corresponds to the data link type of raw packets.
+	flagLinkType uint
+	// flagSrc and flagDst correspond to the addresses of UDP packets.
+	flagSrc, flagDst string
+	// flagSrcMAC and flagDstMAC correspond to the MAC addresses of UDP packets.
+	flagSrcMAC, flagDstMAC string
+	// flagMTU corresponds to the maximum length of IP packets.
+	flagMTU int
+	// flagInterval corresponds to the time between packets.
+	flagInterval time.Duration
+)
 
 func init() {
 	flag.StringVar(&flagOutput, "o", "pcapsulate.pcap", "Output path.")
+	flag.StringVar(&flagMode, "mode", "raw", "Encapsulation mode (raw or udp).")
+	flag.UintVar(&flagLinkType, "link", pcap.LinkTypeEthernet, "Data link type of raw packets (raw mode only).")
+	flag.StringVar(&flagSrc, "src", "10.0.0.1:1024", "Source address of UDP packets.")
+	flag.StringVar(&flagDst, "dst", "10.0.0.2:1024", "Destination address of UDP packets.")
+	flag.StringVar(&flagSrcMAC, "srcmac", "02:00:00:00:00:01", "Source MAC address of UDP packets.")
+	flag.StringVar(&flagDstMAC, "dstmac", "02:00:00:00:00:02", "Destination MAC address of UDP packets.")
+	flag.IntVar(&flagMTU, "mtu", 1500, "Maximum length of IP packets in udp mode.")
+	flag.DurationVar(&flagInterval, "interval", 0, "Time between packets; use file modification times if 0.")
 	flag.Usage = usage
 }
 
 func usage() {
-	fmt.Fprintln(os.Stderr, "Usage: pcapsulate [FILE]...")
+	fmt.Fprintln(os.Stderr, "Usage: pcapsulate [OPTION]... [FILE]...")
 	fmt.Fprintln(os.Stderr, "Encapsulate the provided files as packets in a pcap file.")
 	fmt.Fprintln(os.Stderr)
 	flag.PrintDefaults()
 	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "In raw mode, the content of each file is stored verbatim as a packet of")
+	fmt.Fprintln(os.Stderr, "the given data link type. In udp mode, the content of each file is split")
+	fmt.Fprintln(os.Stderr, "into MTU-sized chunks, each wrapped in synthetic Ethernet, IPv4 and UDP")
+	fmt.Fprintln(os.Stderr, "headers with valid checksums.")
+	fmt.Fprintln(os.Stderr)
 	fmt.Fprintln(os.Stderr, "With no FILE, or when FILE is -, read standard input.")
 }
 
@@ -51,80 +93,122 @@ func main() {
 		filePaths = flag.Args()
 	}
 
-	err := pcapsulate(filePaths)
+	var uf *udpFramer
+	switch flagMode {
+	case "raw":
+	case "udp":
+		// UDP packets are always wrapped in Ethernet frames.
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "link" {
+				log.Fatalln("invalid flag -link in udp mode; udp mode always produces Ethernet frames")
+			}
+		})
+		var err error
+		uf, err = newUDPFramer(flagSrcMAC, flagDstMAC, flagSrc, flagDst, flagMTU)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	default:
+		log.Fatalf("invalid mode %q; expected raw or udp", flagMode)
+	}
+
+	err := pcapsulate(filePaths, uint32(flagLinkType), uf, flagInterval)
 	if err != nil {
 		log.Fatalln(err)
 	}
 }
 
-// pcapsulate encapsulates the provided files as packets in a pcap file.
-func pcapsulate(filePaths []string) (err error) {
+// pcapsulate encapsulates the provided files as packets in a pcap file. The
+// files are stored verbatim as packets of the given data link type if uf is
+// nil, and wrapped in UDP packets using uf otherwise. Packets are sent at the
+// provided interval, starting at the Unix epoch, or at the modification time of
+// their file if interval is 0.
+func pcapsulate(filePaths []string, linkType uint32, uf *udpFramer, interval time.Duration) (err error) {
 	f, err := os.Create(flagOutput)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
+	bw := bufio.NewWriter(f)
+	if err := encapsulate(bw, filePaths, linkType, uf, interval); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// encapsulate encapsulates the provided files as packets in a pcap stream
+// written to w; see pcapsulate.
+func encapsulate(w io.Writer, filePaths []string, linkType uint32, uf *udpFramer, interval time.Duration) error {
+	// Read the files, so that the snapshot length fits the largest packet.
+	var bufs [][]byte
+	var mtimes []time.Time
+	for _, filePath := range filePaths {
+		buf, mtime, err := readFile(filePath)
+		if err != nil {
+			return err
+		}
+		bufs = append(bufs, buf)
+		mtimes = append(mtimes, mtime)
+	}
 
 	// Write pcap header.
-	fh := pcap.FileHeader{
-		Magic:    pcap.MagicLittleEndian,
-		MajorVer: 2,
-		MinorVer: 4,
-		ThisZone: 0,
-		SigFigs:  0,
-		SnapLen:  65535,
-		Network:  1,
+	snapLen := uint32(65535)
+	if uf != nil {
+		linkType = pcap.LinkTypeEthernet
+		if n := uint32(ethernetLen + uf.mtu); n > snapLen {
+			snapLen = n
+		}
+	} else {
+		// Raw packets hold the entire content of their file.
+		for i, buf := range bufs {
+			if uint64(len(buf)) > math.MaxUint32 {
+				return fmt.Errorf("%s: file of %d bytes too large for a raw packet", filePaths[i], len(buf))
+			}
+			if n := uint32(len(buf)); n > snapLen {
+				snapLen = n
+			}
+		}
 	}
-	err = binary.Write(f, binary.LittleEndian, fh)
+	pw, err := pcap.NewWriter(w, snapLen, linkType)
 	if err != nil {
 		return err
 	}
 
-	// Encapsulate each file as a packet and write them to the pcap file.
-	var r io.Reader
-	for _, filePath := range filePaths {
-		if filePath == StdinFileName {
-			r = os.Stdin
-		} else {
-			f, err := os.Open(filePath)
+	// Encapsulate each file as one or more packets and write them to the pcap
+	// stream.
+	n := 0
+	for i, buf := range bufs {
+		pkgs := [][]byte{buf}
+		if uf != nil {
+			pkgs = uf.frames(buf)
+		}
+		for _, pkg := range pkgs {
+			ts := mtimes[i]
+			if interval > 0 {
+				ts = time.Unix(0, 0).Add(time.Duration(n) * interval)
+			}
+			err = pw.WritePackage(ts, pkg, len(pkg))
 			if err != nil {
 				return err
 			}
-			defer f.Close()
-			r = f
-		}
-
-		// Generate and write the packet to the pcap file.
-		pkg, err := genPacket(r)
-		if err != nil {
-			return err
-		}
-		err = binary.Write(f, binary.LittleEndian, pkg.Hdr)
-		if err != nil {
-			return err
-		}
-		_, err = f.Write(pkg.Buf)
-		if err != nil {
-			return err
+			n++
 		}
 	}
-
 	return nil
 }
 
-// genPacket generates a pcap packet by encapsulating the content read from r.
-func genPacket(r io.Reader) (pkg *pcap.Package, err error) {
-	pkg = new(pcap.Package)
-	pkg.Buf, err = ioutil.ReadAll(r)
-	if err != nil {
-		return nil, err
+// readFile returns the content and modification time of the provided file, or
+// the content of standard input and the current time if filePath is
+// StdinFileName.
+func readFile(filePath string) (buf []byte, mtime time.Time, err error) {
+	if filePath == StdinFileName {
+		buf, err = ioutil.ReadAll(os.Stdin)
+		return buf, time.Now(), err
 	}
-
-	pkg.Hdr = pcap.PackageHeader{
-		Sec:     0,
-		Usec:    0,
-		Len:     uint32(len(pkg.Buf)),
-		OrigLen: uint32(len(pkg.Buf)),
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		return nil, time.Time{}, err
 	}
-	return pkg, nil
+	buf, err = ioutil.ReadFile(filePath)
+	return buf, fi.ModTime(), err
 }
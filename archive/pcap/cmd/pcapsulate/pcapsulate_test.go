@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mewmew/playground/archive/pcap"
+)
+
+func TestEncapsulateRaw(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pcapsulate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// Files larger than the default snapshot length of 65535 bytes are stored
+	// in full.
+	sizes := []int{100, 100000, 70000}
+	var want [][]byte
+	var filePaths []string
+	rnd := rand.New(rand.NewSource(1))
+	for i, size := range sizes {
+		buf := make([]byte, size)
+		rnd.Read(buf)
+		filePath := filepath.Join(dir, string('a'+rune(i)))
+		if err := ioutil.WriteFile(filePath, buf, 0644); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, buf)
+		filePaths = append(filePaths, filePath)
+	}
+	out := new(bytes.Buffer)
+	if err := encapsulate(out, filePaths, pcap.LinkTypeRaw, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	pr, err := pcap.NewReader(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; ; i++ {
+		pkg, err := pr.ReadPackage()
+		if err == io.EOF {
+			if i != len(want) {
+				t.Errorf("expected %d packets, got %d.", len(want), i)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		if i >= len(want) {
+			t.Fatalf("i=%d: unexpected packet.", i)
+		}
+		if !bytes.Equal(pkg.Buf, want[i]) {
+			t.Errorf("i=%d: content mismatch; expected %d bytes, got %d.", i, len(want[i]), len(pkg.Buf))
+		}
+		if pkg.Hdr.OrigLen != uint32(len(want[i])) {
+			t.Errorf("i=%d: expected original length %d, got %d.", i, len(want[i]), pkg.Hdr.OrigLen)
+		}
+	}
+}
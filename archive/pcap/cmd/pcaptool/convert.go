@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/mewmew/playground/archive/pcap"
+	"github.com/mewmew/playground/archive/pcap/layers"
+)
+
+// toEthernet converts a packet of the provided data link type to an Ethernet
+// frame. The MAC addresses of the frame are zero, except for the source
+// address of Linux SLL packets which is retained.
+func toEthernet(linkType uint32, data []byte) ([]byte, error) {
+	var (
+		src       []byte
+		etherType uint16
+		payload   []byte
+	)
+	switch linkType {
+	case pcap.LinkTypeEthernet:
+		return data, nil
+	case pcap.LinkTypeLinuxSLL:
+		if len(data) < 16 {
+			return nil, fmt.Errorf("unable to convert Linux SLL packet; truncated header of %d bytes", len(data))
+		}
+		if binary.BigEndian.Uint16(data[4:6]) == 6 {
+			src = data[6:12]
+		}
+		etherType = binary.BigEndian.Uint16(data[14:16])
+		payload = data[16:]
+	case pcap.LinkTypeNull, pcap.LinkTypeRaw, pcap.LinkTypeIPv4, pcap.LinkTypeIPv6:
+		payload = data
+		if linkType == pcap.LinkTypeNull {
+			if len(data) < 4 {
+				return nil, fmt.Errorf("unable to convert loopback packet; truncated header of %d bytes", len(data))
+			}
+			payload = data[4:]
+		}
+		if len(payload) == 0 {
+			return nil, fmt.Errorf("unable to convert empty IP packet")
+		}
+		switch version := payload[0] >> 4; version {
+		case 4:
+			etherType = layers.EtherTypeIPv4
+		case 6:
+			etherType = layers.EtherTypeIPv6
+		default:
+			return nil, fmt.Errorf("unable to convert IP packet; invalid version %d", version)
+		}
+	default:
+		return nil, fmt.Errorf("unable to convert packet of link type %d to Ethernet", linkType)
+	}
+	frame := make([]byte, 14+len(payload))
+	copy(frame[6:12], src)
+	binary.BigEndian.PutUint16(frame[12:14], etherType)
+	copy(frame[14:], payload)
+	return frame, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"io"
+
+	"github.com/mewmew/playground/archive/pcap"
+)
+
+func mergeCmd(args []string) error {
+	fs := newFlagSet("merge", "FILE...", "Merge the packets of the provided pcap files by timestamp.")
+	var (
+		// output corresponds to the output path.
+		output string
+		// convert specifies whether to convert packets to Ethernet.
+		convert bool
+	)
+	fs.StringVar(&output, "o", "-", "Output path.")
+	fs.BoolVar(&convert, "convert", false, "Convert packets of differing link types to Ethernet.")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("merge: no input files")
+	}
+	return merge(output, fs.Args(), convert)
+}
+
+// A source is an input file of a merge.
+type source struct {
+	f        *pcap.File
+	filePath string
+	// Index of the file; orders packets of equal timestamps.
+	idx int
+	// Next packet of the file.
+	pkg *pcap.Package
+}
+
+// next reads the next packet of the source. It returns io.EOF at the end of
+// the file.
+func (s *source) next() (err error) {
+	s.pkg, err = s.f.ReadPackage()
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("%s: %v", s.filePath, err)
+	}
+	return err
+}
+
+// A mergeHeap is a min-heap of sources ordered by the timestamp of their next
+// packet.
+type mergeHeap []*source
+
+func (h mergeHeap) Len() int { return len(h) }
+
+func (h mergeHeap) Less(i, j int) bool {
+	ti, tj := h[i].pkg.Time(), h[j].pkg.Time()
+	if !ti.Equal(tj) {
+		return ti.Before(tj)
+	}
+	return h[i].idx < h[j].idx
+}
+
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *mergeHeap) Push(x interface{}) { *h = append(*h, x.(*source)) }
+
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	s := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return s
+}
+
+// merge merges the packets of the provided pcap files by timestamp and writes
+// them to the output file. Only the next packet of each input file is kept in
+// memory.
+//
+// All packets must share the same data link type, unless convert is set in
+// which case the packets are converted to Ethernet.
+func merge(outPath string, filePaths []string, convert bool) error {
+	var h mergeHeap
+	defer func() {
+		for _, s := range h {
+			s.f.Close()
+		}
+	}()
+	for i, filePath := range filePaths {
+		f, err := pcap.Open(filePath)
+		if err != nil {
+			return err
+		}
+		s := &source{f: f, filePath: filePath, idx: i}
+		if err := s.next(); err != nil {
+			f.Close()
+			if err == io.EOF {
+				// Skip empty files.
+				continue
+			}
+			return err
+		}
+		h = append(h, s)
+	}
+
+	// Reject mismatched link types of the first packet of each file before any
+	// output is written. Files with several interfaces (pcapng) may change link
+	// type later on, in which case the partial output file is removed.
+	linkType := uint32(pcap.LinkTypeEthernet)
+	if len(h) > 0 {
+		linkType = h[0].pkg.LinkType()
+	}
+	n := uint32(defaultSnapLen)
+	for _, s := range h {
+		if got := s.pkg.LinkType(); got != linkType && !convert {
+			return fmt.Errorf("%s: link type %d differs from link type %d of %s; use -convert to convert packets to Ethernet", s.filePath, got, linkType, h[0].filePath)
+		}
+		if m := snapLen(s.f); m > n {
+			n = m
+		}
+	}
+
+	out, err := create(outPath, n, linkType, convert)
+	if err != nil {
+		return err
+	}
+	heap.Init(&h)
+	for len(h) > 0 {
+		s := h[0]
+		if err := out.write(s.pkg); err != nil {
+			out.Abort()
+			return fmt.Errorf("%s: %v", s.filePath, err)
+		}
+		switch err := s.next(); err {
+		case nil:
+			heap.Fix(&h, 0)
+		case io.EOF:
+			heap.Pop(&h)
+			s.f.Close()
+		default:
+			out.Abort()
+			return err
+		}
+	}
+	return out.Close()
+}
@@ -0,0 +1,200 @@
+// pcaptool merges, splits and slices pcap and pcapng files.
+//
+//      Usage: pcaptool COMMAND [OPTION]... FILE...
+//
+//      Commands:
+//        merge  Merge the packets of the provided files by timestamp.
+//        split  Split a file into several files by packet count, size or time.
+//        slice  Extract the packets of a time range.
+//
+//      Run "pcaptool COMMAND -h" for the options of each command.
+//
+//      Output files use the pcap format with microsecond timestamp resolution.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+
+	"github.com/mewmew/playground/archive/pcap"
+)
+
+func init() {
+	flag.Usage = usage
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: pcaptool COMMAND [OPTION]... FILE...")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  merge  Merge the packets of the provided files by timestamp.")
+	fmt.Fprintln(os.Stderr, "  split  Split a file into several files by packet count, size or time.")
+	fmt.Fprintln(os.Stderr, "  slice  Extract the packets of a time range.")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `Run "pcaptool COMMAND -h" for the options of each command.`)
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var err error
+	args := flag.Args()[1:]
+	switch cmd := flag.Arg(0); cmd {
+	case "merge":
+		err = mergeCmd(args)
+	case "split":
+		err = splitCmd(args)
+	case "slice":
+		err = sliceCmd(args)
+	default:
+		fmt.Fprintf(os.Stderr, "pcaptool: unknown command %q\n\n", cmd)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// newFlagSet returns a new flag set of the command, with a usage message
+// listing the provided usage line and description.
+func newFlagSet(cmd, args, desc string) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pcaptool %s [OPTION]... %s\n", cmd, args)
+		fmt.Fprintln(os.Stderr, desc)
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// defaultSnapLen is the snapshot length of output files of pcap inputs with a
+// smaller snapshot length; the pcap package reads packets of up to 256 KiB
+// regardless of the snapshot length of their file.
+const defaultSnapLen = 256 * 1024
+
+// maxNgPackageLen is the maximum length of packets in pcapng files, which is
+// bounded by the maximum block length of 16 MiB accepted by the pcap package.
+const maxNgPackageLen = 16 * 1024 * 1024
+
+// ethernetOverhead is the maximum number of bytes added to packets when
+// converting them to Ethernet.
+const ethernetOverhead = 14
+
+// snapLen returns the snapshot length of output files holding the packets of
+// the input file, which is large enough to hold any packet read from it.
+func snapLen(f *pcap.File) uint32 {
+	hdr := f.Header()
+	if hdr.Magic == 0 {
+		// pcapng files have no file header.
+		return maxNgPackageLen
+	}
+	if hdr.SnapLen > defaultSnapLen {
+		return hdr.SnapLen
+	}
+	return defaultSnapLen
+}
+
+// An output is a pcap file holding packets of a single data link type.
+type output struct {
+	// Data link type of the packets.
+	linkType uint32
+	// Convert packets of other data link types to Ethernet.
+	convert bool
+	// Number of packets and bytes written, including the file header.
+	n    int
+	size int64
+	pw   *pcap.Writer
+	bw   *bufio.Writer
+	// Underlying file; nil for standard output.
+	f *os.File
+}
+
+// create creates a pcap file for packets of the provided data link type, which
+// holds packets of up to snapLen bytes without truncating them. The file path
+// "-" denotes standard output. If convert is set, the output holds Ethernet
+// frames and packets of other data link types are converted.
+func create(filePath string, snapLen, linkType uint32, convert bool) (*output, error) {
+	if convert {
+		linkType = pcap.LinkTypeEthernet
+		if snapLen > math.MaxUint32-ethernetOverhead {
+			snapLen = math.MaxUint32
+		} else {
+			snapLen += ethernetOverhead
+		}
+	}
+	o := &output{linkType: linkType, convert: convert}
+	var w io.Writer = os.Stdout
+	if filePath != "-" {
+		f, err := os.Create(filePath)
+		if err != nil {
+			return nil, err
+		}
+		o.f = f
+		w = f
+	}
+	o.bw = bufio.NewWriter(w)
+	pw, err := pcap.NewWriter(o.bw, snapLen, linkType)
+	if err != nil {
+		o.Close()
+		return nil, err
+	}
+	o.pw = pw
+	o.size = 24
+	return o, nil
+}
+
+// write writes the packet to the output file.
+func (o *output) write(pkg *pcap.Package) error {
+	data, origLen := pkg.Buf, int(pkg.Hdr.OrigLen)
+	if linkType := pkg.LinkType(); linkType != o.linkType {
+		if !o.convert {
+			return fmt.Errorf("packet of link type %d in output of link type %d", linkType, o.linkType)
+		}
+		frame, err := toEthernet(linkType, data)
+		if err != nil {
+			return err
+		}
+		origLen += len(frame) - len(data)
+		data = frame
+	}
+	if err := o.pw.WritePackage(pkg.Time(), data, origLen); err != nil {
+		return err
+	}
+	o.n++
+	o.size += 16 + int64(len(data))
+	return nil
+}
+
+// Abort closes and removes the output file; it is used when the output is
+// incomplete. Standard output is left as is.
+func (o *output) Abort() error {
+	err := o.Close()
+	if o.f != nil {
+		if e := os.Remove(o.f.Name()); err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Close flushes and closes the output file.
+func (o *output) Close() error {
+	err := o.bw.Flush()
+	if o.f != nil {
+		if e := o.f.Close(); err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mewmew/playground/archive/pcap"
+	"github.com/mewmew/playground/archive/pcap/layers"
+)
+
+// The packets of testdata/ethernet.pcap are sent at 1500000000 seconds plus
+// 0, 1.1, 2.2, 3.3, 4.4 and 5.5 seconds; the packet of testdata/sll.pcap at
+// 1500000000 seconds.
+const (
+	ethernetPath = "../../layers/testdata/ethernet.pcap"
+	sllPath      = "../../layers/testdata/sll.pcap"
+)
+
+// tempDir returns a temporary directory which is removed at the end of the
+// test.
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "pcaptool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// offsets returns the link type of the pcap file and the timestamp of each
+// packet, in milliseconds relative to 1500000000 seconds.
+func offsets(t *testing.T, filePath string) (linkType uint32, ms []int) {
+	f, err := pcap.Open(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	base := time.Unix(1500000000, 0)
+	for {
+		pkg, err := f.ReadPackage()
+		if err == io.EOF {
+			return linkType, ms
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		linkType = pkg.LinkType()
+		ms = append(ms, int(pkg.Time().Sub(base)/time.Millisecond))
+	}
+}
+
+// writeFile writes a pcap file of empty Ethernet frames, sent at the provided
+// offsets in milliseconds relative to 1500000000 seconds.
+func writeFile(t *testing.T, filePath string, ms ...int) {
+	f, err := os.Create(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	pw, err := pcap.NewWriter(f, defaultSnapLen, pcap.LinkTypeEthernet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range ms {
+		ts := time.Unix(1500000000, 0).Add(time.Duration(m) * time.Millisecond)
+		if err := pw.WritePackage(ts, make([]byte, 14), 14); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dir := tempDir(t)
+	extraPath := filepath.Join(dir, "extra.pcap")
+	writeFile(t, extraPath, 500, 1100, 9000)
+	emptyPath := filepath.Join(dir, "empty.pcap")
+	writeFile(t, emptyPath)
+
+	outPath := filepath.Join(dir, "out.pcap")
+	if err := merge(outPath, []string{ethernetPath, emptyPath, extraPath}, false); err != nil {
+		t.Fatal(err)
+	}
+	linkType, got := offsets(t, outPath)
+	want := []int{0, 500, 1100, 1100, 2200, 3300, 4400, 5500, 9000}
+	if linkType != pcap.LinkTypeEthernet || !reflect.DeepEqual(got, want) {
+		t.Errorf("expected link type 1 and offsets %v, got link type %d and offsets %v.", want, linkType, got)
+	}
+}
+
+func TestMergeConvert(t *testing.T) {
+	outPath := filepath.Join(tempDir(t), "out.pcap")
+	if err := merge(outPath, []string{ethernetPath, sllPath}, false); err == nil {
+		t.Fatal("expected error when merging mismatched link types, got nil")
+	}
+	if err := merge(outPath, []string{sllPath, ethernetPath}, true); err != nil {
+		t.Fatal(err)
+	}
+	f, err := pcap.Open(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	// The converted Linux SLL packet comes first, as its input file does.
+	pkg, err := f.ReadPackage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := layers.DecodePackage(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Ethernet == nil || p.UDP == nil || p.UDP.DstPort != 5678 || string(p.UDP.Payload) != "sll" {
+		t.Errorf("expected converted Ethernet frame of UDP packet to port 5678, got %+v.", p)
+	}
+}
+
+func TestMergeLarge(t *testing.T) {
+	dir := tempDir(t)
+	// Packets larger than 256 KiB are read from files of a larger snapshot
+	// length, and must not be truncated.
+	large := make([]byte, 300*1024)
+	for i := range large {
+		large[i] = byte(i)
+	}
+	largePath := filepath.Join(dir, "large.pcap")
+	f, err := os.Create(largePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw, err := pcap.NewWriter(f, uint32(len(large)), pcap.LinkTypeEthernet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pw.WritePackage(time.Unix(1500000000, 0), large, len(large)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	extraPath := filepath.Join(dir, "extra.pcap")
+	writeFile(t, extraPath, 500)
+
+	outPath := filepath.Join(dir, "out.pcap")
+	if err := merge(outPath, []string{extraPath, largePath}, false); err != nil {
+		t.Fatal(err)
+	}
+	out, err := pcap.Open(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	pkg, err := out.ReadPackage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pkg.Buf, large) || pkg.Hdr.OrigLen != uint32(len(large)) {
+		t.Errorf("expected packet of %d bytes, got %d of %d bytes.", len(large), len(pkg.Buf), pkg.Hdr.OrigLen)
+	}
+}
+
+func TestMergeLinkTypeChange(t *testing.T) {
+	dir := tempDir(t)
+	// The second packet of the pcapng file is of a different link type.
+	ngPath := filepath.Join(dir, "in.pcapng")
+	f, err := os.Create(ngPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nw, err := pcap.NewNgWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eth, err := nw.AddInterface(pcap.LinkTypeEthernet, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := nw.AddInterface(pcap.LinkTypeRaw, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := nw.WritePackage(eth, time.Unix(1500000000, 0), make([]byte, 14), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := nw.WritePackage(raw, time.Unix(1500000001, 0), []byte{0x45}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(dir, "out.pcap")
+	if err := merge(outPath, []string{ngPath}, false); err == nil {
+		t.Fatal("expected error when link type changes, got nil")
+	}
+	if _, err := os.Stat(outPath); !os.IsNotExist(err) {
+		t.Errorf("expected partial output file to be removed, got %v.", err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	golden := []struct {
+		sp   splitter
+		want [][]int
+	}{
+		// i=0
+		{sp: splitter{count: 4}, want: [][]int{{0, 1100, 2200, 3300}, {4400, 5500}}},
+		// i=1
+		{sp: splitter{window: 2 * time.Second}, want: [][]int{{0, 1100}, {2200, 3300}, {4400, 5500}}},
+		// i=2
+		{sp: splitter{window: 1500 * time.Millisecond}, want: [][]int{{0, 1100}, {2200}, {3300, 4400}, {5500}}},
+		// i=3; the file header takes 24 bytes, and each packet 16 bytes plus
+		// its captured length of 58, 59, 60, 71, 60 and 60 bytes.
+		{sp: splitter{size: 200}, want: [][]int{{0, 1100}, {2200, 3300}, {4400, 5500}}},
+		// i=4; files exceed the size when holding a single packet.
+		{sp: splitter{size: 1}, want: [][]int{{0}, {1100}, {2200}, {3300}, {4400}, {5500}}},
+	}
+	for i, g := range golden {
+		prefix := filepath.Join(tempDir(t), "out")
+		if err := split(prefix, ethernetPath, g.sp); err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		var got [][]int
+		for j := 0; ; j++ {
+			outPath := filepath.Join(filepath.Dir(prefix), fmt.Sprintf("out_%05d.pcap", j))
+			if _, err := os.Stat(outPath); err != nil {
+				break
+			}
+			_, ms := offsets(t, outPath)
+			got = append(got, ms)
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
+
+func TestSlice(t *testing.T) {
+	golden := []struct {
+		start, end string
+		want       []int
+	}{
+		// i=0
+		{want: []int{0, 1100, 2200, 3300, 4400, 5500}},
+		// i=1
+		{start: "2s", end: "4400ms", want: []int{2200, 3300}},
+		// i=2
+		{start: "2017-07-14T02:40:03Z", want: []int{3300, 4400, 5500}},
+		// i=3
+		{end: "2017-07-14T04:40:01.1+02:00", want: []int{0}},
+		// i=4
+		{start: "1m", want: nil},
+	}
+	for i, g := range golden {
+		var start, end timeFlag
+		if g.start != "" {
+			if err := start.Set(g.start); err != nil {
+				t.Fatalf("i=%d: %v", i, err)
+			}
+		}
+		if g.end != "" {
+			if err := end.Set(g.end); err != nil {
+				t.Fatalf("i=%d: %v", i, err)
+			}
+		}
+		outPath := filepath.Join(tempDir(t), "out.pcap")
+		if err := slice(outPath, ethernetPath, start, end); err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if _, got := offsets(t, outPath); !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mewmew/playground/archive/pcap"
+)
+
+func sliceCmd(args []string) error {
+	fs := newFlagSet("slice", "FILE", "Extract the packets of a time range from the provided pcap file.")
+	var (
+		// output corresponds to the output path.
+		output string
+		// start and end correspond to the time range.
+		start, end timeFlag
+	)
+	fs.StringVar(&output, "o", "-", "Output path.")
+	fs.Var(&start, "start", "Start of time range (inclusive); RFC 3339 time or offset from first packet (e.g. 10s).")
+	fs.Var(&end, "end", "End of time range (exclusive); RFC 3339 time or offset from first packet (e.g. 1m).")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("slice: expected one input file; got %d", fs.NArg())
+	}
+	return slice(output, fs.Arg(0), start, end)
+}
+
+// A timeFlag is a point in time, specified either as an absolute time in RFC
+// 3339 format or as an offset from the first packet of a file.
+type timeFlag struct {
+	// Absolute time.
+	t time.Time
+	// Offset from the first packet, if rel is set.
+	offset time.Duration
+	rel    bool
+	// Flag specified.
+	set bool
+}
+
+func (tf *timeFlag) String() string {
+	switch {
+	case !tf.set:
+		return ""
+	case tf.rel:
+		return tf.offset.String()
+	}
+	return tf.t.Format(time.RFC3339Nano)
+}
+
+func (tf *timeFlag) Set(s string) error {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		*tf = timeFlag{t: t, set: true}
+		return nil
+	}
+	offset, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid time %q; expected RFC 3339 time or duration", s)
+	}
+	*tf = timeFlag{offset: offset, rel: true, set: true}
+	return nil
+}
+
+// resolve returns the point in time, given the timestamp of the first packet.
+func (tf *timeFlag) resolve(first time.Time) time.Time {
+	if tf.rel {
+		return first.Add(tf.offset)
+	}
+	return tf.t
+}
+
+// slice writes the packets of the pcap file within the time range [start, end)
+// to the output file. An unspecified start or end leaves the range unbounded.
+// The packets of the input file need not be ordered by timestamp.
+func slice(outPath, filePath string, start, end timeFlag) error {
+	f, err := pcap.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var (
+		out    *output
+		lo, hi time.Time
+	)
+	defer func() {
+		if out != nil {
+			out.Close()
+		}
+	}()
+	for {
+		pkg, err := f.ReadPackage()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("%s: %v", filePath, err)
+		}
+		ts := pkg.Time()
+		if out == nil {
+			// The output file uses the link type of the first packet.
+			out, err = create(outPath, snapLen(f), pkg.LinkType(), false)
+			if err != nil {
+				return err
+			}
+			lo, hi = start.resolve(ts), end.resolve(ts)
+		}
+		if start.set && ts.Before(lo) || end.set && !ts.Before(hi) {
+			continue
+		}
+		if err := out.write(pkg); err != nil {
+			return fmt.Errorf("%s: %v", filePath, err)
+		}
+	}
+	if out == nil {
+		// Create an empty output file for empty input files.
+		out, err = create(outPath, snapLen(f), pcap.LinkTypeEthernet, false)
+		if err != nil {
+			return err
+		}
+	}
+	err = out.Close()
+	out = nil
+	return err
+}
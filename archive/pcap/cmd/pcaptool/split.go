@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mewmew/playground/archive/pcap"
+)
+
+func splitCmd(args []string) error {
+	fs := newFlagSet("split", "FILE", "Split the provided pcap file into several files.")
+	var (
+		// prefix corresponds to the output path prefix.
+		prefix string
+		// count corresponds to the maximum number of packets per file.
+		count int
+		// size corresponds to the maximum size in bytes per file.
+		size int64
+		// window corresponds to the time window per file.
+		window time.Duration
+	)
+	fs.StringVar(&prefix, "o", "", "Output path prefix (default: input path without extension).")
+	fs.IntVar(&count, "n", 0, "Maximum number of packets per file.")
+	fs.Int64Var(&size, "size", 0, "Maximum size in bytes per file.")
+	fs.DurationVar(&window, "t", 0, "Time window per file (e.g. 1m).")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("split: expected one input file; got %d", fs.NArg())
+	}
+	n := 0
+	for _, set := range []bool{count > 0, size > 0, window > 0} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		fs.Usage()
+		return fmt.Errorf("split: exactly one of -n, -size and -t must be specified")
+	}
+	filePath := fs.Arg(0)
+	if prefix == "" {
+		prefix = strings.TrimSuffix(filePath, filepath.Ext(filePath))
+	}
+	return split(prefix, filePath, splitter{count: count, size: size, window: window})
+}
+
+// A splitter specifies when to start a new output file; only one of its
+// fields is non-zero.
+type splitter struct {
+	// Maximum number of packets per file.
+	count int
+	// Maximum size in bytes per file. A file holds at least one packet, even if
+	// it exceeds the size.
+	size int64
+	// Time window per file. Windows are aligned to the timestamp of the first
+	// packet, and empty windows produce no files.
+	window time.Duration
+}
+
+// split splits the pcap file into several files, named after the output path
+// prefix and the index of the file; e.g. "foo_00000.pcap".
+func split(prefix, filePath string, sp splitter) error {
+	f, err := pcap.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var (
+		out *output
+		// Number of output files.
+		n int
+		// Start of the time window of the current output file.
+		start time.Time
+	)
+	defer func() {
+		if out != nil {
+			out.Close()
+		}
+	}()
+	for {
+		pkg, err := f.ReadPackage()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("%s: %v", filePath, err)
+		}
+		ts := pkg.Time()
+		if out == nil || sp.full(out, pkg, start) {
+			if out != nil {
+				err := out.Close()
+				out = nil
+				if err != nil {
+					return err
+				}
+			}
+			switch {
+			case start.IsZero():
+				start = ts
+			case sp.window > 0:
+				// Skip ahead to the window of the packet.
+				start = start.Add(ts.Sub(start) / sp.window * sp.window)
+			}
+			outPath := fmt.Sprintf("%s_%05d.pcap", prefix, n)
+			out, err = create(outPath, snapLen(f), pkg.LinkType(), false)
+			if err != nil {
+				return err
+			}
+			n++
+		}
+		if err := out.write(pkg); err != nil {
+			return fmt.Errorf("%s: %v", filePath, err)
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	err = out.Close()
+	out = nil
+	return err
+}
+
+// full reports whether the packet should be written to a new output file,
+// given the current output file and the start of its time window.
+func (sp splitter) full(out *output, pkg *pcap.Package, start time.Time) bool {
+	switch {
+	case sp.count > 0:
+		return out.n >= sp.count
+	case sp.size > 0:
+		return out.n > 0 && out.size+16+int64(len(pkg.Buf)) > sp.size
+	case sp.window > 0:
+		return pkg.Time().Sub(start) >= sp.window
+	}
+	return false
+}
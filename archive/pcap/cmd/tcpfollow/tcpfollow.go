@@ -0,0 +1,125 @@
+// tcpfollow reassembles the TCP streams of pcap files and dumps each stream to
+// a file.
+//
+//      Usage: tcpfollow [OPTION]... FILE...
+//      Reassemble the TCP streams of the provided pcap files.
+//
+//        -o=".": Output directory.
+//
+//      Each connection produces up to two files in the output directory, one
+//      per direction with data, named after the index of the connection and its
+//      endpoints; e.g.
+//
+//        0000_10.0.0.1.49152-10.0.0.2.80  (client to server)
+//        0000_10.0.0.2.80-10.0.0.1.49152  (server to client)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mewmew/playground/archive/pcap"
+	"github.com/mewmew/playground/archive/pcap/stream"
+)
+
+// flagOutput corresponds to the output directory.
+var flagOutput string
+
+func init() {
+	flag.StringVar(&flagOutput, "o", ".", "Output directory.")
+	flag.Usage = usage
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: tcpfollow [OPTION]... FILE...")
+	fmt.Fprintln(os.Stderr, "Reassemble the TCP streams of the provided pcap files.")
+	fmt.Fprintln(os.Stderr)
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	a := stream.NewAssembler()
+	for _, filePath := range flag.Args() {
+		err := follow(a, filePath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+	err := dump(a.Flush())
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// follow adds the packets of the provided pcap file to the assembler.
+func follow(a *stream.Assembler, filePath string) error {
+	f, err := pcap.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for {
+		pkg, err := f.ReadPackage()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		// Decode errors of truncated or malformed packets are not fatal; the
+		// captured part of the packet is still reassembled.
+		a.AddPackage(pkg)
+	}
+}
+
+// dump writes each direction of the provided connections to a file in the
+// output directory.
+func dump(conns []*stream.Conn) error {
+	for i, c := range conns {
+		err := dumpStream(i, c.Client, c.Server, c.ClientToServer)
+		if err != nil {
+			return err
+		}
+		err = dumpStream(i, c.Server, c.Client, c.ServerToClient)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%04d %v <-> %v (%d lost bytes)\n", i, c.Client, c.Server, c.ClientToServer.Lost+c.ServerToClient.Lost)
+	}
+	return nil
+}
+
+// dumpStream writes the data sent from src to dst in the ith connection to a
+// file in the output directory. Empty streams are skipped.
+func dumpStream(i int, src, dst stream.Endpoint, s *stream.Stream) error {
+	if s.Len() == 0 {
+		return nil
+	}
+	name := fmt.Sprintf("%04d_%s-%s", i, fileName(src), fileName(dst))
+	f, err := os.Create(filepath.Join(flagOutput, name))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, s)
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+// fileName returns a file name friendly representation of the endpoint.
+func fileName(e stream.Endpoint) string {
+	ip := strings.Replace(e.IP, ":", "_", -1)
+	return fmt.Sprintf("%s.%d", ip, e.Port)
+}
@@ -0,0 +1,187 @@
+// Package filter implements filter expressions for captured packets, using a
+// practical subset of the tcpdump (pcap-filter) syntax.
+//
+// The following primitives are supported, where DIR is an optional direction
+// qualifier (src, dst, src or dst, src and dst) which defaults to either
+// direction.
+//
+//    DIR host ADDR        IPv4 or IPv6 address
+//    DIR net NET          CIDR prefix (10.0.0.0/8) or NET mask MASK
+//    DIR port PORT        TCP or UDP port number or service name
+//    proto NAME           tcp, udp, icmp, icmp6 or an IP protocol number
+//    tcp, udp, icmp, icmp6, ip, ip6, arp
+//
+// A protocol may precede a qualified primitive, as in "tcp port 80" or
+// "ip6 host ::1", which is equivalent to "tcp and port 80". Primitives are
+// combined using "and" (&&), "or" (||), "not" (!) and parentheses; "not" binds
+// tighter than "and", which binds tighter than "or".
+package filter
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/mewmew/playground/archive/pcap/layers"
+)
+
+// A Filter matches decoded packets.
+type Filter interface {
+	// Match reports whether the packet matches the filter.
+	Match(p *layers.Packet) bool
+}
+
+// all matches every packet.
+type all struct{}
+
+func (all) Match(p *layers.Packet) bool { return true }
+
+// and matches packets matched by both x and y.
+type and struct{ x, y Filter }
+
+func (f and) Match(p *layers.Packet) bool { return f.x.Match(p) && f.y.Match(p) }
+
+// or matches packets matched by either x or y.
+type or struct{ x, y Filter }
+
+func (f or) Match(p *layers.Packet) bool { return f.x.Match(p) || f.y.Match(p) }
+
+// not matches packets not matched by x.
+type not struct{ x Filter }
+
+func (f not) Match(p *layers.Packet) bool { return !f.x.Match(p) }
+
+// A dir specifies which endpoint of a packet a primitive applies to.
+type dir int
+
+// Directions.
+const (
+	dirEither dir = iota
+	dirSrc
+	dirDst
+	dirBoth
+)
+
+// match reports whether the source and destination match according to the
+// direction.
+func (d dir) match(src, dst bool) bool {
+	switch d {
+	case dirSrc:
+		return src
+	case dirDst:
+		return dst
+	case dirBoth:
+		return src && dst
+	}
+	return src || dst
+}
+
+// addrs returns the source and destination network addresses of the packet,
+// including the protocol addresses of ARP packets.
+func addrs(p *layers.Packet) (src, dst net.IP) {
+	if p.ARP != nil {
+		return net.IP(p.ARP.SrcProtocolAddr), net.IP(p.ARP.DstProtocolAddr)
+	}
+	return p.SrcIP(), p.DstIP()
+}
+
+// host matches packets to or from an address.
+type host struct {
+	dir dir
+	ip  net.IP
+}
+
+func (f host) Match(p *layers.Packet) bool {
+	src, dst := addrs(p)
+	if src == nil {
+		return false
+	}
+	return f.dir.match(f.ip.Equal(src), f.ip.Equal(dst))
+}
+
+// network matches packets to or from a network.
+type network struct {
+	dir dir
+	net *net.IPNet
+}
+
+func (f network) Match(p *layers.Packet) bool {
+	src, dst := addrs(p)
+	if src == nil {
+		return false
+	}
+	return f.dir.match(f.net.Contains(src), f.net.Contains(dst))
+}
+
+// port matches TCP and UDP packets to or from a port.
+type port struct {
+	dir  dir
+	port uint16
+}
+
+func (f port) Match(p *layers.Packet) bool {
+	if p.TCP == nil && p.UDP == nil {
+		return false
+	}
+	return f.dir.match(p.SrcPort() == f.port, p.DstPort() == f.port)
+}
+
+// proto matches packets of an IP protocol.
+type proto uint8
+
+func (f proto) Match(p *layers.Packet) bool {
+	n, ok := p.Protocol()
+	return ok && n == uint8(f)
+}
+
+// layer matches packets containing a given protocol layer.
+type layer string
+
+func (f layer) Match(p *layers.Packet) bool {
+	switch f {
+	case "ip":
+		return p.IPv4 != nil
+	case "ip6":
+		return p.IPv6 != nil
+	case "arp":
+		return p.ARP != nil
+	}
+	return false
+}
+
+// A SyntaxError is returned when an invalid filter expression is compiled.
+type SyntaxError struct {
+	// Byte offset of the invalid token in the expression.
+	Pos int
+	// Description of the problem.
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("filter: syntax error at offset %d; %s", e.Pos, e.Msg)
+}
+
+// Compile compiles the filter expression. An empty expression matches every
+// packet.
+func Compile(expr string) (Filter, error) {
+	p := &parser{toks: lex(expr), end: len(expr)}
+	if len(p.toks) == 0 {
+		return all{}, nil
+	}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.val)}
+	}
+	return f, nil
+}
+
+// MustCompile is like Compile but panics if the expression is invalid.
+func MustCompile(expr string) Filter {
+	f, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
@@ -0,0 +1,143 @@
+package filter
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/playground/archive/pcap"
+)
+
+// The packets of testdata/ethernet.pcap are:
+//
+//    0: 10.0.0.1:49152 > 10.0.0.2:80 TCP SYN
+//    1: 10.0.0.1:49152 > 10.0.0.2:80 TCP data
+//    2: 10.0.0.1 > 10.0.0.2 ARP request
+//    3: [2001:db8::1]:5353 > [2001:db8::2]:53 UDP (VLAN 100)
+//    4: 10.0.0.1 > 10.0.0.2 ICMP echo request
+//    5: 10.0.0.2:80 > 10.0.0.1:49152 TCP data (truncated)
+const testPath = "../layers/testdata/ethernet.pcap"
+
+var golden = []struct {
+	expr string
+	want []int
+}{
+	// i=0
+	{expr: "", want: []int{0, 1, 2, 3, 4, 5}},
+	// i=1
+	{expr: "tcp", want: []int{0, 1, 5}},
+	// i=2
+	{expr: "udp", want: []int{3}},
+	// i=3
+	{expr: "icmp", want: []int{4}},
+	// i=4
+	{expr: "arp", want: []int{2}},
+	// i=5
+	{expr: "ip6", want: []int{3}},
+	// i=6
+	{expr: "ip", want: []int{0, 1, 4, 5}},
+	// i=7
+	{expr: "host 10.0.0.1", want: []int{0, 1, 2, 4, 5}},
+	// i=8
+	{expr: "src host 10.0.0.1", want: []int{0, 1, 2, 4}},
+	// i=9
+	{expr: "dst host 10.0.0.1", want: []int{5}},
+	// i=10
+	{expr: "src or dst host 10.0.0.2", want: []int{0, 1, 2, 4, 5}},
+	// i=11
+	{expr: "src and dst net 10.0.0.0/8", want: []int{0, 1, 2, 4, 5}},
+	// i=12
+	{expr: "net 2001:db8::/32", want: []int{3}},
+	// i=13
+	{expr: "dst net 10.0.0.2 mask 255.255.255.254", want: []int{0, 1, 2, 4}},
+	// i=14
+	{expr: "port 80", want: []int{0, 1, 5}},
+	// i=15
+	{expr: "dst port 80", want: []int{0, 1}},
+	// i=16
+	{expr: "tcp port 53 or udp port 53", want: []int{3}},
+	// i=17
+	{expr: "port domain", want: []int{3}},
+	// i=18
+	{expr: "not tcp and ip", want: []int{4}},
+	// i=19
+	{expr: "!(tcp || icmp) && host 10.0.0.1", want: []int{2}},
+	// i=20
+	{expr: "proto 17", want: []int{3}},
+	// i=21
+	{expr: "tcp and (src port 80 or dst port 49152)", want: []int{5}},
+	// i=22
+	{expr: "ip6 host 2001:db8::2", want: []int{3}},
+	// i=23
+	{expr: "not not arp", want: []int{2}},
+}
+
+func TestFilter(t *testing.T) {
+	for i, g := range golden {
+		f, err := Compile(g.expr)
+		if err != nil {
+			t.Errorf("i=%d: %s", i, err)
+			continue
+		}
+		pf, err := pcap.Open(testPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Record the index of each packet by its timestamp, which is unique.
+		r := NewReader(pf, f)
+		var got []int
+		for {
+			pkg, err := r.ReadPackage()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("i=%d: %s", i, err)
+			}
+			got = append(got, int(pkg.Hdr.Sec-1500000000))
+		}
+		pf.Close()
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: %q: expected %v, got %v.", i, g.expr, g.want, got)
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	golden := []struct {
+		expr string
+		pos  int
+	}{
+		// i=0
+		{expr: "host", pos: 4},
+		// i=1
+		{expr: "host foo", pos: 5},
+		// i=2
+		{expr: "(tcp", pos: 4},
+		// i=3
+		{expr: "tcp )", pos: 4},
+		// i=4
+		{expr: "port 99999", pos: 5},
+		// i=5
+		{expr: "src foo", pos: 4},
+		// i=6
+		{expr: "proto xyz", pos: 6},
+		// i=7
+		{expr: "net 10.0.0.0/33", pos: 4},
+		// i=8
+		{expr: "tcp and", pos: 7},
+		// i=9
+		{expr: "tcp & udp", pos: 4},
+	}
+	for i, g := range golden {
+		_, err := Compile(g.expr)
+		e, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("i=%d: %q: expected *SyntaxError, got %v.", i, g.expr, err)
+			continue
+		}
+		if e.Pos != g.pos {
+			t.Errorf("i=%d: %q: expected error at offset %d, got %d (%v).", i, g.expr, g.pos, e.Pos, e)
+		}
+	}
+}
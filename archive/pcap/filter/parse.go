@@ -0,0 +1,303 @@
+package filter
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// A token is a lexical token of a filter expression.
+type token struct {
+	// Token text.
+	val string
+	// Byte offset in the expression.
+	pos int
+}
+
+// lex splits the filter expression into tokens. Parentheses, "!", "&&" and
+// "||" are tokens of their own; all other tokens are separated by whitespace.
+func lex(expr string) (toks []token) {
+	for i := 0; i < len(expr); {
+		switch c := expr[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '!':
+			toks = append(toks, token{val: expr[i : i+1], pos: i})
+			i++
+		case strings.HasPrefix(expr[i:], "&&") || strings.HasPrefix(expr[i:], "||"):
+			toks = append(toks, token{val: expr[i : i+2], pos: i})
+			i += 2
+		default:
+			j := i
+			for j < len(expr) && !strings.ContainsRune(" \t\n\r()!&|", rune(expr[j])) {
+				j++
+			}
+			if j == i {
+				// Lone '&' or '|'.
+				j++
+			}
+			toks = append(toks, token{val: expr[i:j], pos: i})
+			i = j
+		}
+	}
+	return toks
+}
+
+// A parser parses the tokens of a filter expression.
+type parser struct {
+	toks []token
+	// Length of the expression; used as offset at end of input.
+	end int
+}
+
+// peek returns the next token without consuming it.
+func (p *parser) peek() (token, bool) {
+	if len(p.toks) == 0 {
+		return token{pos: p.end}, false
+	}
+	return p.toks[0], true
+}
+
+// accept consumes the next token if it is one of the provided values.
+func (p *parser) accept(vals ...string) bool {
+	tok, ok := p.peek()
+	if !ok {
+		return false
+	}
+	for _, val := range vals {
+		if tok.val == val {
+			p.toks = p.toks[1:]
+			return true
+		}
+	}
+	return false
+}
+
+// next consumes and returns the next token.
+func (p *parser) next(what string) (token, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return tok, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected %s; got end of expression", what)}
+	}
+	p.toks = p.toks[1:]
+	return tok, nil
+}
+
+// parseOr parses a disjunction.
+//
+//    or = and { ("or" | "||") and }
+func (p *parser) parseOr() (Filter, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or", "||") {
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = or{x, y}
+	}
+	return x, nil
+}
+
+// parseAnd parses a conjunction.
+//
+//    and = not { ("and" | "&&") not }
+func (p *parser) parseAnd() (Filter, error) {
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and", "&&") {
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = and{x, y}
+	}
+	return x, nil
+}
+
+// parseNot parses a negation.
+//
+//    not = ("not" | "!") not | "(" or ")" | primitive
+func (p *parser) parseNot() (Filter, error) {
+	if p.accept("not", "!") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return not{x}, nil
+	}
+	if p.accept("(") {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		tok, ok := p.peek()
+		if !p.accept(")") {
+			if !ok {
+				return nil, &SyntaxError{Pos: tok.pos, Msg: `expected ")"; got end of expression`}
+			}
+			return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf(`expected ")"; got %q`, tok.val)}
+		}
+		return x, nil
+	}
+	return p.parsePrimitive()
+}
+
+// protos maps from protocol names to IP protocol numbers.
+var protos = map[string]uint8{
+	"icmp":  1,
+	"tcp":   6,
+	"udp":   17,
+	"icmp6": 58,
+}
+
+// parsePrimitive parses a primitive.
+//
+//    primitive = "proto" NAME | PROTO [ qualified ] | qualified
+func (p *parser) parsePrimitive() (Filter, error) {
+	tok, err := p.next("primitive")
+	if err != nil {
+		return nil, err
+	}
+	var x Filter
+	switch tok.val {
+	case "proto":
+		name, err := p.next("protocol")
+		if err != nil {
+			return nil, err
+		}
+		n, ok := protos[name.val]
+		if !ok {
+			v, err := strconv.ParseUint(name.val, 10, 8)
+			if err != nil {
+				return nil, &SyntaxError{Pos: name.pos, Msg: fmt.Sprintf("invalid protocol %q", name.val)}
+			}
+			n = uint8(v)
+		}
+		return proto(n), nil
+	case "tcp", "udp", "icmp", "icmp6":
+		x = proto(protos[tok.val])
+	case "ip", "ip6", "arp":
+		x = layer(tok.val)
+	default:
+		p.toks = append([]token{tok}, p.toks...)
+		return p.parseQualified()
+	}
+	// Protocol followed by a qualified primitive.
+	if next, ok := p.peek(); ok {
+		switch next.val {
+		case "src", "dst", "host", "net", "port":
+			y, err := p.parseQualified()
+			if err != nil {
+				return nil, err
+			}
+			return and{x, y}, nil
+		}
+	}
+	return x, nil
+}
+
+// parseQualified parses a primitive with an optional direction qualifier.
+//
+//    qualified = [ DIR ] ( "host" ADDR | "net" NET [ "mask" MASK ] | "port" PORT )
+//    DIR       = "src" | "dst" | "src" "or" "dst" | "src" "and" "dst"
+func (p *parser) parseQualified() (Filter, error) {
+	d := dirEither
+	switch {
+	case p.accept("src"):
+		d = dirSrc
+		// Look ahead for "src or dst" and "src and dst".
+		if len(p.toks) >= 2 && p.toks[1].val == "dst" {
+			switch p.toks[0].val {
+			case "or", "||":
+				d = dirEither
+				p.toks = p.toks[2:]
+			case "and", "&&":
+				d = dirBoth
+				p.toks = p.toks[2:]
+			}
+		}
+	case p.accept("dst"):
+		d = dirDst
+	}
+	tok, err := p.next(`"host", "net" or "port"`)
+	if err != nil {
+		return nil, err
+	}
+	switch tok.val {
+	case "host":
+		arg, err := p.next("address")
+		if err != nil {
+			return nil, err
+		}
+		ip := net.ParseIP(arg.val)
+		if ip == nil {
+			return nil, &SyntaxError{Pos: arg.pos, Msg: fmt.Sprintf("invalid address %q", arg.val)}
+		}
+		return host{dir: d, ip: ip}, nil
+	case "net":
+		arg, err := p.next("network")
+		if err != nil {
+			return nil, err
+		}
+		n, err := p.parseNet(arg)
+		if err != nil {
+			return nil, err
+		}
+		return network{dir: d, net: n}, nil
+	case "port":
+		arg, err := p.next("port")
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.ParseUint(arg.val, 10, 16)
+		if err != nil {
+			v, lerr := net.LookupPort("tcp", arg.val)
+			if lerr != nil {
+				return nil, &SyntaxError{Pos: arg.pos, Msg: fmt.Sprintf("invalid port %q", arg.val)}
+			}
+			n = uint64(v)
+		}
+		return port{dir: d, port: uint16(n)}, nil
+	}
+	return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf(`expected "host", "net" or "port"; got %q`, tok.val)}
+}
+
+// parseNet parses a network in CIDR notation, a plain address or an address
+// followed by "mask" and a netmask.
+func (p *parser) parseNet(arg token) (*net.IPNet, error) {
+	if strings.Contains(arg.val, "/") {
+		_, n, err := net.ParseCIDR(arg.val)
+		if err != nil {
+			return nil, &SyntaxError{Pos: arg.pos, Msg: fmt.Sprintf("invalid network %q", arg.val)}
+		}
+		return n, nil
+	}
+	ip := net.ParseIP(arg.val)
+	if ip == nil {
+		return nil, &SyntaxError{Pos: arg.pos, Msg: fmt.Sprintf("invalid network %q", arg.val)}
+	}
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		bits = 8 * net.IPv4len
+	}
+	mask := net.CIDRMask(bits, bits)
+	if p.accept("mask") {
+		m, err := p.next("netmask")
+		if err != nil {
+			return nil, err
+		}
+		mip := net.ParseIP(m.val)
+		if mip == nil || mip.To4() == nil || len(ip) != net.IPv4len {
+			return nil, &SyntaxError{Pos: m.pos, Msg: fmt.Sprintf("invalid netmask %q", m.val)}
+		}
+		mask = net.IPMask(mip.To4())
+	}
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}, nil
+}
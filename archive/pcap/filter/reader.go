@@ -0,0 +1,35 @@
+package filter
+
+import (
+	"github.com/mewmew/playground/archive/pcap"
+	"github.com/mewmew/playground/archive/pcap/layers"
+)
+
+// A Reader reads the packets of a capture stream which match a filter.
+type Reader struct {
+	// Underlying packet reader.
+	r pcap.PackageReader
+	// Packet filter.
+	f Filter
+}
+
+// NewReader returns a new Reader which reads the packets of r matching f.
+func NewReader(r pcap.PackageReader, f Filter) *Reader {
+	return &Reader{r: r, f: f}
+}
+
+// ReadPackage reads and returns the next matching packet of the capture stream.
+// Truncated and malformed packets are matched against the layers that could be
+// decoded. At the end of the stream ReadPackage returns io.EOF.
+func (r *Reader) ReadPackage() (pkg *pcap.Package, err error) {
+	for {
+		pkg, err = r.r.ReadPackage()
+		if err != nil {
+			return nil, err
+		}
+		p, _ := layers.DecodePackage(pkg)
+		if r.f.Match(p) {
+			return pkg, nil
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Package layers implements decoding of the protocol layers of network packets
+// stored in pcap files.
+//
+// The supported protocols are Ethernet (including 802.1Q VLAN tags), Linux
+// cooked capture, ARP, IPv4, IPv6, TCP, UDP and ICMP.
+package layers
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/mewmew/playground/archive/pcap"
+)
+
+// A Packet represents a decoded network packet. Layers which are not present
+// in the packet are nil.
+type Packet struct {
+	// Data link layer.
+	Ethernet *Ethernet
+	// VLAN tags of the Ethernet frame, outermost first.
+	VLANs []Dot1Q
+	// Linux cooked capture header.
+	LinuxSLL *LinuxSLL
+
+	// Network layer.
+	ARP  *ARP
+	IPv4 *IPv4
+	IPv6 *IPv6
+
+	// Transport layer.
+	TCP  *TCP
+	UDP  *UDP
+	ICMP *ICMP
+
+	// Payload of the innermost decoded layer.
+	Payload []byte
+}
+
+// A DecodeError is returned when a truncated or malformed packet is
+// encountered. The layers decoded before the error are still present in the
+// returned packet.
+type DecodeError struct {
+	// Name of the protocol layer.
+	Layer string
+	// Description of the problem.
+	Msg string
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("layers: unable to decode %s layer; %s", e.Layer, e.Msg)
+}
+
+// truncated returns a DecodeError for a layer which requires at least want
+// bytes, when only n bytes are available.
+func truncated(layer string, n, want int) error {
+	return &DecodeError{Layer: layer, Msg: fmt.Sprintf("truncated; %d of %d bytes present", n, want)}
+}
+
+// DecodePackage decodes the protocol layers of the provided packet, based on
+// its data link type.
+func DecodePackage(pkg *pcap.Package) (p *Packet, err error) {
+	return Decode(pkg.LinkType(), pkg.Buf)
+}
+
+// Decode decodes the protocol layers of the provided packet data, starting at
+// the data link layer specified by linkType. Truncated or malformed packets
+// return the successfully decoded layers together with a *DecodeError.
+func Decode(linkType uint32, data []byte) (p *Packet, err error) {
+	p = &Packet{Payload: data}
+	switch linkType {
+	case pcap.LinkTypeEthernet:
+		err = p.decodeEthernet(data)
+	case pcap.LinkTypeLinuxSLL:
+		err = p.decodeLinuxSLL(data)
+	case pcap.LinkTypeNull:
+		err = p.decodeNull(data)
+	case pcap.LinkTypeRaw:
+		err = p.decodeIP(data)
+	case pcap.LinkTypeIPv4:
+		err = p.decodeIPv4(data)
+	case pcap.LinkTypeIPv6:
+		err = p.decodeIPv6(data)
+	default:
+		err = &DecodeError{Layer: "link", Msg: fmt.Sprintf("unsupported link type %d", linkType)}
+	}
+	return p, err
+}
+
+// decodeEtherType decodes the payload of a data link layer based on the
+// provided EtherType. Unknown EtherTypes are left undecoded.
+func (p *Packet) decodeEtherType(typ uint16, data []byte) error {
+	p.Payload = data
+	switch typ {
+	case EtherTypeIPv4:
+		return p.decodeIPv4(data)
+	case EtherTypeIPv6:
+		return p.decodeIPv6(data)
+	case EtherTypeARP:
+		return p.decodeARP(data)
+	case EtherTypeDot1Q, EtherTypeQinQ:
+		return p.decodeDot1Q(data)
+	}
+	return nil
+}
+
+// decodeIP decodes an IPv4 or IPv6 packet, based on its version field.
+func (p *Packet) decodeIP(data []byte) error {
+	if len(data) < 1 {
+		return truncated("IP", len(data), 1)
+	}
+	switch version := data[0] >> 4; version {
+	case 4:
+		return p.decodeIPv4(data)
+	case 6:
+		return p.decodeIPv6(data)
+	default:
+		return &DecodeError{Layer: "IP", Msg: fmt.Sprintf("invalid version %d", version)}
+	}
+}
+
+// decodeProtocol decodes the payload of a network layer based on the provided
+// IP protocol number. Unknown protocols are left undecoded.
+func (p *Packet) decodeProtocol(proto uint8, data []byte) error {
+	p.Payload = data
+	switch proto {
+	case IPProtoTCP:
+		return p.decodeTCP(data)
+	case IPProtoUDP:
+		return p.decodeUDP(data)
+	case IPProtoICMP, IPProtoICMPv6:
+		return p.decodeICMP(data)
+	}
+	return nil
+}
+
+// SrcIP returns the source IP address of the packet, or nil if the packet has
+// no IP layer.
+func (p *Packet) SrcIP() net.IP {
+	switch {
+	case p.IPv4 != nil:
+		return p.IPv4.Src
+	case p.IPv6 != nil:
+		return p.IPv6.Src
+	}
+	return nil
+}
+
+// DstIP returns the destination IP address of the packet, or nil if the packet
+// has no IP layer.
+func (p *Packet) DstIP() net.IP {
+	switch {
+	case p.IPv4 != nil:
+		return p.IPv4.Dst
+	case p.IPv6 != nil:
+		return p.IPv6.Dst
+	}
+	return nil
+}
+
+// Protocol returns the IP protocol number of the transport layer of the
+// packet, and a boolean indicating whether the packet has an IP layer.
+func (p *Packet) Protocol() (proto uint8, ok bool) {
+	switch {
+	case p.IPv4 != nil:
+		return p.IPv4.Protocol, true
+	case p.IPv6 != nil:
+		return p.IPv6.Protocol, true
+	}
+	return 0, false
+}
+
+// SrcPort returns the source port of the packet, or 0 if the packet has no TCP
+// or UDP layer.
+func (p *Packet) SrcPort() uint16 {
+	switch {
+	case p.TCP != nil:
+		return p.TCP.SrcPort
+	case p.UDP != nil:
+		return p.UDP.SrcPort
+	}
+	return 0
+}
+
+// DstPort returns the destination port of the packet, or 0 if the packet has
+// no TCP or UDP layer.
+func (p *Packet) DstPort() uint16 {
+	switch {
+	case p.TCP != nil:
+		return p.TCP.DstPort
+	case p.UDP != nil:
+		return p.UDP.DstPort
+	}
+	return 0
+}
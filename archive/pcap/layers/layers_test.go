@@ -0,0 +1,175 @@
+package layers
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/mewmew/playground/archive/pcap"
+)
+
+// summary returns a one-line summary of the decoded layers of p.
+func summary(p *Packet) string {
+	s := ""
+	if p.Ethernet != nil {
+		s += fmt.Sprintf("eth %v>%v 0x%04X", p.Ethernet.Src, p.Ethernet.Dst, p.Ethernet.EtherType)
+	}
+	for _, tag := range p.VLANs {
+		s += fmt.Sprintf(" vlan %d prio %d", tag.VLAN, tag.Priority)
+	}
+	if p.LinuxSLL != nil {
+		s += fmt.Sprintf("sll %d %x 0x%04X", p.LinuxSLL.PacketType, p.LinuxSLL.Addr, p.LinuxSLL.Protocol)
+	}
+	if p.ARP != nil {
+		s += fmt.Sprintf(" arp op %d %v %v>%v", p.ARP.Op, p.ARP.SrcHardwareAddr, net.IP(p.ARP.SrcProtocolAddr), net.IP(p.ARP.DstProtocolAddr))
+	}
+	if p.IPv4 != nil {
+		s += fmt.Sprintf(" ipv4 id %d ttl %d df %v", p.IPv4.ID, p.IPv4.TTL, p.IPv4.DontFragment())
+	}
+	if p.IPv6 != nil {
+		s += fmt.Sprintf(" ipv6 tc 0x%02X flow 0x%05X", p.IPv6.TrafficClass, p.IPv6.FlowLabel)
+	}
+	if proto, ok := p.Protocol(); ok {
+		s += fmt.Sprintf(" %v>%v proto %d", p.SrcIP(), p.DstIP(), proto)
+	}
+	if p.TCP != nil {
+		s += fmt.Sprintf(" tcp %d>%d seq %d ack %d flags 0x%02X opts %x", p.TCP.SrcPort, p.TCP.DstPort, p.TCP.Seq, p.TCP.Ack, p.TCP.Flags, p.TCP.Options)
+	}
+	if p.UDP != nil {
+		s += fmt.Sprintf(" udp %d>%d len %d", p.UDP.SrcPort, p.UDP.DstPort, p.UDP.Length)
+	}
+	if p.ICMP != nil {
+		s += fmt.Sprintf(" icmp type %d code %d rest %x", p.ICMP.Type, p.ICMP.Code, p.ICMP.Rest)
+	}
+	s += fmt.Sprintf(" payload %q", p.Payload)
+	return strings.TrimSpace(s)
+}
+
+type testPacket struct {
+	summary string
+	err     string
+}
+
+var golden = []struct {
+	path string
+	pkts []testPacket
+}{
+	// i=0
+	{
+		path: "testdata/ethernet.pcap",
+		pkts: []testPacket{
+			{summary: `eth 00:11:22:33:44:55>66:77:88:99:aa:bb 0x0800 ipv4 id 1 ttl 64 df true 10.0.0.1>10.0.0.2 proto 6 tcp 49152>80 seq 1000 ack 0 flags 0x02 opts 020405b4 payload ""`},
+			{summary: `eth 00:11:22:33:44:55>66:77:88:99:aa:bb 0x0800 ipv4 id 2 ttl 64 df true 10.0.0.1>10.0.0.2 proto 6 tcp 49152>80 seq 1001 ack 5001 flags 0x18 opts  payload "hello"`},
+			{summary: `eth 00:11:22:33:44:55>ff:ff:ff:ff:ff:ff 0x0806 arp op 1 00:11:22:33:44:55 10.0.0.1>10.0.0.2 payload ""`},
+			{summary: `eth 00:11:22:33:44:55>66:77:88:99:aa:bb 0x8100 vlan 100 prio 5 ipv6 tc 0x12 flow 0xABCDE 2001:db8::1>2001:db8::2 proto 17 udp 5353>53 len 13 payload "query"`},
+			{summary: `eth 00:11:22:33:44:55>66:77:88:99:aa:bb 0x0800 ipv4 id 3 ttl 64 df true 10.0.0.1>10.0.0.2 proto 1 icmp type 8 code 0 rest 12340001 payload "ping"`},
+			{
+				summary: `eth 00:11:22:33:44:55>66:77:88:99:aa:bb 0x0800 ipv4 id 4 ttl 64 df true 10.0.0.2>10.0.0.1 proto 6 tcp 80>49152 seq 5001 ack 1006 flags 0x18 opts  payload "world,"`,
+				err:     "layers: unable to decode IPv4 layer; truncated; 46 of 59 bytes present",
+			},
+		},
+	},
+	// i=1
+	{
+		path: "testdata/sll.pcap",
+		pkts: []testPacket{
+			{summary: `sll 4 001122334455 0x0800 ipv4 id 5 ttl 64 df true 10.0.0.1>10.0.0.2 proto 17 udp 1234>5678 len 11 payload "sll"`},
+		},
+	},
+}
+
+func TestDecodePackage(t *testing.T) {
+	for i, g := range golden {
+		f, err := pcap.Open(g.path)
+		if err != nil {
+			t.Errorf("i=%d: %s", i, err)
+			continue
+		}
+		for j, want := range g.pkts {
+			pkg, err := f.ReadPackage()
+			if err != nil {
+				t.Errorf("i=%d, j=%d: %s", i, j, err)
+				break
+			}
+			p, err := DecodePackage(pkg)
+			var gotErr string
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != want.err {
+				t.Errorf("i=%d, j=%d: expected error %q, got %q.", i, j, want.err, gotErr)
+			}
+			if got := summary(p); got != want.summary {
+				t.Errorf("i=%d, j=%d: expected\n\t%s\ngot\n\t%s", i, j, want.summary, got)
+			}
+		}
+		if _, err := f.ReadPackage(); err != io.EOF {
+			t.Errorf("i=%d: expected io.EOF, got %v.", i, err)
+		}
+		f.Close()
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	golden := []struct {
+		linkType uint32
+		data     []byte
+		layer    string
+		summary  string
+	}{
+		// i=0
+		{
+			linkType: pcap.LinkTypeEthernet,
+			data:     []byte{1, 2, 3},
+			layer:    "Ethernet",
+			summary:  `payload "\x01\x02\x03"`,
+		},
+		// i=1
+		{
+			linkType: pcap.LinkTypeRaw,
+			data:     []byte{0x55, 0, 0, 20},
+			layer:    "IP",
+			summary:  `payload "U\x00\x00\x14"`,
+		},
+		// i=2
+		{
+			linkType: pcap.LinkTypeIPv4,
+			data:     []byte{0x44, 0, 0, 20, 0, 0, 0, 0, 64, 17, 0, 0, 1, 2, 3, 4, 5, 6, 7, 8},
+			layer:    "IPv4",
+			summary:  `payload "D\x00\x00\x14\x00\x00\x00\x00@\x11\x00\x00\x01\x02\x03\x04\x05\x06\a\b"`,
+		},
+		// i=3
+		{
+			linkType: pcap.LinkTypeIPv4,
+			data:     []byte{0x45, 0, 0, 24, 0, 0, 0, 0, 64, 17, 0, 0, 1, 2, 3, 4, 5, 6, 7, 8, 0, 53, 0, 53},
+			layer:    "UDP",
+			summary:  `ipv4 id 0 ttl 64 df false 1.2.3.4>5.6.7.8 proto 17 payload "\x005\x005"`,
+		},
+		// i=4
+		{
+			linkType: pcap.LinkTypeIPv4,
+			data:     []byte{0x45, 0, 0, 28, 0, 0, 0, 0, 64, 6, 0, 0, 1, 2, 3, 4, 5, 6, 7, 8, 0, 1, 0, 2, 0, 0, 0, 0},
+			layer:    "TCP",
+			summary:  `ipv4 id 0 ttl 64 df false 1.2.3.4>5.6.7.8 proto 6 payload "\x00\x01\x00\x02\x00\x00\x00\x00"`,
+		},
+		// i=5
+		{
+			linkType: 147,
+			data:     []byte{1},
+			layer:    "link",
+			summary:  `payload "\x01"`,
+		},
+	}
+	for i, g := range golden {
+		p, err := Decode(g.linkType, g.data)
+		if e, ok := err.(*DecodeError); !ok || e.Layer != g.layer {
+			t.Errorf("i=%d: expected *DecodeError of %s layer, got %v.", i, g.layer, err)
+			continue
+		}
+		if got := summary(p); got != g.summary {
+			t.Errorf("i=%d: expected\n\t%s\ngot\n\t%s", i, g.summary, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package layers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// EtherTypes of the network layer protocols.
+const (
+	EtherTypeIPv4  = 0x0800
+	EtherTypeARP   = 0x0806
+	EtherTypeDot1Q = 0x8100
+	EtherTypeIPv6  = 0x86DD
+	EtherTypeQinQ  = 0x88A8
+)
+
+// An Ethernet header is present at the beginning of each Ethernet frame.
+type Ethernet struct {
+	// Destination MAC address.
+	Dst net.HardwareAddr
+	// Source MAC address.
+	Src net.HardwareAddr
+	// EtherType of the payload, or the payload length for IEEE 802.3 frames.
+	EtherType uint16
+}
+
+// decodeEthernet decodes an Ethernet frame.
+func (p *Packet) decodeEthernet(data []byte) error {
+	const n = 14
+	if len(data) < n {
+		return truncated("Ethernet", len(data), n)
+	}
+	p.Ethernet = &Ethernet{
+		Dst:       net.HardwareAddr(data[0:6]),
+		Src:       net.HardwareAddr(data[6:12]),
+		EtherType: binary.BigEndian.Uint16(data[12:14]),
+	}
+	// EtherType values below 0x0600 specify the payload length of IEEE 802.3
+	// frames.
+	if p.Ethernet.EtherType < 0x0600 {
+		p.Payload = data[n:]
+		return nil
+	}
+	return p.decodeEtherType(p.Ethernet.EtherType, data[n:])
+}
+
+// A Dot1Q header is present in Ethernet frames tagged with an IEEE 802.1Q VLAN
+// tag.
+type Dot1Q struct {
+	// Priority code point.
+	Priority uint8
+	// Drop eligible indicator.
+	DropEligible bool
+	// VLAN identifier.
+	VLAN uint16
+	// EtherType of the payload.
+	EtherType uint16
+}
+
+// decodeDot1Q decodes an IEEE 802.1Q VLAN tag.
+func (p *Packet) decodeDot1Q(data []byte) error {
+	const n = 4
+	if len(data) < n {
+		return truncated("802.1Q", len(data), n)
+	}
+	tci := binary.BigEndian.Uint16(data[0:2])
+	tag := Dot1Q{
+		Priority:     uint8(tci >> 13),
+		DropEligible: tci&0x1000 != 0,
+		VLAN:         tci & 0x0FFF,
+		EtherType:    binary.BigEndian.Uint16(data[2:4]),
+	}
+	p.VLANs = append(p.VLANs, tag)
+	return p.decodeEtherType(tag.EtherType, data[n:])
+}
+
+// A LinuxSLL header is present at the beginning of each packet captured using
+// the Linux "any" device.
+type LinuxSLL struct {
+	// Packet type; e.g. 0 for unicast to us and 4 for sent by us.
+	PacketType uint16
+	// ARPHRD type of the link layer device.
+	AddrType uint16
+	// Link layer address of the sender.
+	Addr []byte
+	// EtherType of the payload.
+	Protocol uint16
+}
+
+// decodeLinuxSLL decodes a Linux cooked capture packet.
+func (p *Packet) decodeLinuxSLL(data []byte) error {
+	const n = 16
+	if len(data) < n {
+		return truncated("Linux SLL", len(data), n)
+	}
+	addrLen := int(binary.BigEndian.Uint16(data[4:6]))
+	if addrLen > 8 {
+		addrLen = 8
+	}
+	p.LinuxSLL = &LinuxSLL{
+		PacketType: binary.BigEndian.Uint16(data[0:2]),
+		AddrType:   binary.BigEndian.Uint16(data[2:4]),
+		Addr:       data[6 : 6+addrLen],
+		Protocol:   binary.BigEndian.Uint16(data[14:16]),
+	}
+	return p.decodeEtherType(p.LinuxSLL.Protocol, data[n:])
+}
+
+// decodeNull decodes a BSD loopback packet, which starts with the address
+// family of the payload in the byte order of the capturing host.
+func (p *Packet) decodeNull(data []byte) error {
+	const n = 4
+	if len(data) < n {
+		return truncated("loopback", len(data), n)
+	}
+	family := binary.LittleEndian.Uint32(data[0:4])
+	if family > 0xFF {
+		family = binary.BigEndian.Uint32(data[0:4])
+	}
+	p.Payload = data[n:]
+	switch family {
+	// AF_INET
+	case 2:
+		return p.decodeIPv4(data[n:])
+	// AF_INET6 on Linux, FreeBSD, macOS and NetBSD/OpenBSD respectively.
+	case 10, 28, 30, 24:
+		return p.decodeIPv6(data[n:])
+	}
+	return &DecodeError{Layer: "loopback", Msg: fmt.Sprintf("unsupported address family %d", family)}
+}
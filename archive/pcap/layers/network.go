@@ -0,0 +1,242 @@
+package layers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// IP protocol numbers of the transport layer protocols.
+const (
+	IPProtoICMP   = 1
+	IPProtoTCP    = 6
+	IPProtoUDP    = 17
+	IPProtoICMPv6 = 58
+)
+
+// An ARP packet is used to resolve network layer addresses into link layer
+// addresses.
+type ARP struct {
+	// Hardware type; 1 for Ethernet.
+	HardwareType uint16
+	// Protocol type; an EtherType.
+	ProtocolType uint16
+	// Operation; 1 for request and 2 for reply.
+	Op uint16
+	// Sender hardware address.
+	SrcHardwareAddr net.HardwareAddr
+	// Sender protocol address.
+	SrcProtocolAddr []byte
+	// Target hardware address.
+	DstHardwareAddr net.HardwareAddr
+	// Target protocol address.
+	DstProtocolAddr []byte
+}
+
+// decodeARP decodes an ARP packet.
+func (p *Packet) decodeARP(data []byte) error {
+	const n = 8
+	if len(data) < n {
+		return truncated("ARP", len(data), n)
+	}
+	hlen, plen := int(data[4]), int(data[5])
+	if want := n + 2*hlen + 2*plen; len(data) < want {
+		return truncated("ARP", len(data), want)
+	}
+	addrs := data[n:]
+	p.ARP = &ARP{
+		HardwareType:    binary.BigEndian.Uint16(data[0:2]),
+		ProtocolType:    binary.BigEndian.Uint16(data[2:4]),
+		Op:              binary.BigEndian.Uint16(data[6:8]),
+		SrcHardwareAddr: net.HardwareAddr(addrs[:hlen]),
+		SrcProtocolAddr: addrs[hlen : hlen+plen],
+		DstHardwareAddr: net.HardwareAddr(addrs[hlen+plen : 2*hlen+plen]),
+		DstProtocolAddr: addrs[2*hlen+plen : 2*hlen+2*plen],
+	}
+	p.Payload = nil
+	return nil
+}
+
+// IPv4 flags.
+const (
+	IPv4DontFragment  = 0x2
+	IPv4MoreFragments = 0x1
+)
+
+// An IPv4 header is present at the beginning of each IPv4 packet.
+type IPv4 struct {
+	// Header length in 32-bit words.
+	IHL uint8
+	// Type of service.
+	TOS uint8
+	// Total length of header and payload.
+	Length uint16
+	// Identification.
+	ID uint16
+	// Flags; see IPv4DontFragment and IPv4MoreFragments.
+	Flags uint8
+	// Fragment offset in 8-byte units.
+	FragOffset uint16
+	// Time to live.
+	TTL uint8
+	// Protocol of the payload.
+	Protocol uint8
+	// Header checksum.
+	Checksum uint16
+	// Source address.
+	Src net.IP
+	// Destination address.
+	Dst net.IP
+	// Options.
+	Options []byte
+}
+
+// DontFragment reports whether the don't fragment flag is set.
+func (ip *IPv4) DontFragment() bool {
+	return ip.Flags&IPv4DontFragment != 0
+}
+
+// MoreFragments reports whether the more fragments flag is set.
+func (ip *IPv4) MoreFragments() bool {
+	return ip.Flags&IPv4MoreFragments != 0
+}
+
+// decodeIPv4 decodes an IPv4 packet.
+func (p *Packet) decodeIPv4(data []byte) error {
+	const n = 20
+	if len(data) < n {
+		return truncated("IPv4", len(data), n)
+	}
+	if version := data[0] >> 4; version != 4 {
+		return &DecodeError{Layer: "IPv4", Msg: fmt.Sprintf("invalid version %d", version)}
+	}
+	ihl := data[0] & 0x0F
+	hlen := int(ihl) * 4
+	if hlen < n {
+		return &DecodeError{Layer: "IPv4", Msg: fmt.Sprintf("invalid header length %d", hlen)}
+	}
+	if len(data) < hlen {
+		return truncated("IPv4", len(data), hlen)
+	}
+	flagsFrag := binary.BigEndian.Uint16(data[6:8])
+	ip := &IPv4{
+		IHL:        ihl,
+		TOS:        data[1],
+		Length:     binary.BigEndian.Uint16(data[2:4]),
+		ID:         binary.BigEndian.Uint16(data[4:6]),
+		Flags:      uint8(flagsFrag >> 13),
+		FragOffset: flagsFrag & 0x1FFF,
+		TTL:        data[8],
+		Protocol:   data[9],
+		Checksum:   binary.BigEndian.Uint16(data[10:12]),
+		Src:        net.IP(data[12:16]),
+		Dst:        net.IP(data[16:20]),
+		Options:    data[n:hlen],
+	}
+	p.IPv4 = ip
+	if int(ip.Length) < hlen {
+		return &DecodeError{Layer: "IPv4", Msg: fmt.Sprintf("invalid total length %d", ip.Length)}
+	}
+	// Strip link layer padding; the payload may also be truncated by the
+	// snapshot length of the capture.
+	payload := data[hlen:]
+	if len(data) > int(ip.Length) {
+		payload = data[hlen:ip.Length]
+	}
+	p.Payload = payload
+	// Only the first fragment contains the transport layer header.
+	if ip.FragOffset != 0 {
+		return nil
+	}
+	err := p.decodeProtocol(ip.Protocol, payload)
+	if err == nil && len(data) < int(ip.Length) {
+		err = truncated("IPv4", len(data), int(ip.Length))
+	}
+	return err
+}
+
+// IPv6 extension header types.
+const (
+	ipv6HopByHop = 0
+	ipv6Routing  = 43
+	ipv6Fragment = 44
+	ipv6DestOpts = 60
+)
+
+// An IPv6 header is present at the beginning of each IPv6 packet.
+type IPv6 struct {
+	// Traffic class.
+	TrafficClass uint8
+	// Flow label.
+	FlowLabel uint32
+	// Length of the payload, including extension headers.
+	Length uint16
+	// Type of the next header.
+	NextHeader uint8
+	// Hop limit.
+	HopLimit uint8
+	// Source address.
+	Src net.IP
+	// Destination address.
+	Dst net.IP
+	// Protocol of the payload, after any extension headers.
+	Protocol uint8
+}
+
+// decodeIPv6 decodes an IPv6 packet.
+func (p *Packet) decodeIPv6(data []byte) error {
+	const n = 40
+	if len(data) < n {
+		return truncated("IPv6", len(data), n)
+	}
+	if version := data[0] >> 4; version != 6 {
+		return &DecodeError{Layer: "IPv6", Msg: fmt.Sprintf("invalid version %d", version)}
+	}
+	vtf := binary.BigEndian.Uint32(data[0:4])
+	ip := &IPv6{
+		TrafficClass: uint8(vtf >> 20),
+		FlowLabel:    vtf & 0x000FFFFF,
+		Length:       binary.BigEndian.Uint16(data[4:6]),
+		NextHeader:   data[6],
+		HopLimit:     data[7],
+		Src:          net.IP(data[8:24]),
+		Dst:          net.IP(data[24:40]),
+	}
+	p.IPv6 = ip
+	payload := data[n:]
+	if len(payload) > int(ip.Length) {
+		payload = payload[:ip.Length]
+	}
+	// Skip extension headers.
+	proto := ip.NextHeader
+	for {
+		switch proto {
+		case ipv6HopByHop, ipv6Routing, ipv6DestOpts, ipv6Fragment:
+		default:
+			ip.Protocol = proto
+			p.Payload = payload
+			err := p.decodeProtocol(proto, payload)
+			if err == nil && len(data)-n < int(ip.Length) {
+				err = truncated("IPv6", len(data), n+int(ip.Length))
+			}
+			return err
+		}
+		if len(payload) < 8 {
+			return truncated("IPv6 extension header", len(payload), 8)
+		}
+		hlen := 8
+		if proto != ipv6Fragment {
+			hlen = (int(payload[1]) + 1) * 8
+		} else if binary.BigEndian.Uint16(payload[2:4])&^0x7 != 0 {
+			// Only the first fragment contains the transport layer header.
+			ip.Protocol = payload[0]
+			p.Payload = payload[hlen:]
+			return nil
+		}
+		if len(payload) < hlen {
+			return truncated("IPv6 extension header", len(payload), hlen)
+		}
+		proto = payload[0]
+		payload = payload[hlen:]
+	}
+}
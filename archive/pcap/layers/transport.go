@@ -0,0 +1,165 @@
+package layers
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// TCP flags.
+const (
+	TCPFlagFIN = 0x01
+	TCPFlagSYN = 0x02
+	TCPFlagRST = 0x04
+	TCPFlagPSH = 0x08
+	TCPFlagACK = 0x10
+	TCPFlagURG = 0x20
+	TCPFlagECE = 0x40
+	TCPFlagCWR = 0x80
+)
+
+// A TCP header is present at the beginning of each TCP segment.
+type TCP struct {
+	// Source port.
+	SrcPort uint16
+	// Destination port.
+	DstPort uint16
+	// Sequence number.
+	Seq uint32
+	// Acknowledgment number.
+	Ack uint32
+	// Header length in 32-bit words.
+	DataOffset uint8
+	// Flags; see TCPFlagFIN, TCPFlagSYN, etc.
+	Flags uint8
+	// Window size.
+	Window uint16
+	// Checksum.
+	Checksum uint16
+	// Urgent pointer.
+	Urgent uint16
+	// Options.
+	Options []byte
+	// Segment data.
+	Payload []byte
+}
+
+// FIN reports whether the FIN flag is set.
+func (tcp *TCP) FIN() bool { return tcp.Flags&TCPFlagFIN != 0 }
+
+// SYN reports whether the SYN flag is set.
+func (tcp *TCP) SYN() bool { return tcp.Flags&TCPFlagSYN != 0 }
+
+// RST reports whether the RST flag is set.
+func (tcp *TCP) RST() bool { return tcp.Flags&TCPFlagRST != 0 }
+
+// PSH reports whether the PSH flag is set.
+func (tcp *TCP) PSH() bool { return tcp.Flags&TCPFlagPSH != 0 }
+
+// ACK reports whether the ACK flag is set.
+func (tcp *TCP) ACK() bool { return tcp.Flags&TCPFlagACK != 0 }
+
+// URG reports whether the URG flag is set.
+func (tcp *TCP) URG() bool { return tcp.Flags&TCPFlagURG != 0 }
+
+// decodeTCP decodes a TCP segment.
+func (p *Packet) decodeTCP(data []byte) error {
+	const n = 20
+	if len(data) < n {
+		return truncated("TCP", len(data), n)
+	}
+	off := data[12] >> 4
+	hlen := int(off) * 4
+	if hlen < n {
+		return &DecodeError{Layer: "TCP", Msg: fmt.Sprintf("invalid header length %d", hlen)}
+	}
+	if len(data) < hlen {
+		return truncated("TCP", len(data), hlen)
+	}
+	p.TCP = &TCP{
+		SrcPort:    binary.BigEndian.Uint16(data[0:2]),
+		DstPort:    binary.BigEndian.Uint16(data[2:4]),
+		Seq:        binary.BigEndian.Uint32(data[4:8]),
+		Ack:        binary.BigEndian.Uint32(data[8:12]),
+		DataOffset: off,
+		Flags:      data[13],
+		Window:     binary.BigEndian.Uint16(data[14:16]),
+		Checksum:   binary.BigEndian.Uint16(data[16:18]),
+		Urgent:     binary.BigEndian.Uint16(data[18:20]),
+		Options:    data[n:hlen],
+		Payload:    data[hlen:],
+	}
+	p.Payload = p.TCP.Payload
+	return nil
+}
+
+// A UDP header is present at the beginning of each UDP datagram.
+type UDP struct {
+	// Source port.
+	SrcPort uint16
+	// Destination port.
+	DstPort uint16
+	// Length of header and payload.
+	Length uint16
+	// Checksum.
+	Checksum uint16
+	// Datagram data.
+	Payload []byte
+}
+
+// decodeUDP decodes a UDP datagram.
+func (p *Packet) decodeUDP(data []byte) error {
+	const n = 8
+	if len(data) < n {
+		return truncated("UDP", len(data), n)
+	}
+	udp := &UDP{
+		SrcPort:  binary.BigEndian.Uint16(data[0:2]),
+		DstPort:  binary.BigEndian.Uint16(data[2:4]),
+		Length:   binary.BigEndian.Uint16(data[4:6]),
+		Checksum: binary.BigEndian.Uint16(data[6:8]),
+		Payload:  data[n:],
+	}
+	p.UDP = udp
+	p.Payload = udp.Payload
+	if udp.Length < n {
+		return &DecodeError{Layer: "UDP", Msg: fmt.Sprintf("invalid length %d", udp.Length)}
+	}
+	if len(data) > int(udp.Length) {
+		udp.Payload = data[n:udp.Length]
+		p.Payload = udp.Payload
+	}
+	return nil
+}
+
+// An ICMP header is present at the beginning of each ICMP or ICMPv6 message.
+type ICMP struct {
+	// Message type.
+	Type uint8
+	// Message code.
+	Code uint8
+	// Checksum.
+	Checksum uint16
+	// Type specific header data; e.g. identifier and sequence number of echo
+	// messages.
+	Rest [4]byte
+	// Message data.
+	Payload []byte
+}
+
+// decodeICMP decodes an ICMP or ICMPv6 message.
+func (p *Packet) decodeICMP(data []byte) error {
+	const n = 8
+	if len(data) < n {
+		return truncated("ICMP", len(data), n)
+	}
+	icmp := &ICMP{
+		Type:     data[0],
+		Code:     data[1],
+		Checksum: binary.BigEndian.Uint16(data[2:4]),
+		Payload:  data[n:],
+	}
+	copy(icmp.Rest[:], data[4:8])
+	p.ICMP = icmp
+	p.Payload = icmp.Payload
+	return nil
+}
@@ -1,19 +1,16 @@
-// Package pcap provides support for reading pcap files.
+// Package pcap provides support for reading and writing pcap and pcapng files.
 package pcap
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/binary"
+	"fmt"
 	"io"
 	"os"
 	"time"
 )
 
-// A File represents a pcap file.
-type File struct {
-	hdr FileHeader
-	io.ReadCloser
-}
-
 // A FileHeader structure is present at the beginning of each pcap file.
 type FileHeader struct {
 	// Magic number.
@@ -32,33 +29,183 @@ type FileHeader struct {
 	Network uint32
 }
 
-// Magic specifies if little or big endian encoding has been used.
+// Magic specifies if little or big endian encoding has been used, and whether
+// the packet timestamps have microsecond or nanosecond resolution. The
+// constants hold the value of the magic number when decoded using little
+// endian byte order.
 const (
-	MagicLittleEndian = 0xA1B2C3D4
-	MagicBigEndian    = 0xD4C3B2A1
+	MagicLittleEndian     = 0xA1B2C3D4
+	MagicBigEndian        = 0xD4C3B2A1
+	MagicNanoLittleEndian = 0xA1B23C4D
+	MagicNanoBigEndian    = 0x4D3CB2A1
 )
 
-// Open opens the named pcap file for reading.
+// maxPackageLen specifies the maximum length of packet data accepted
+// regardless of the snapshot length of the file; it protects against huge
+// allocations caused by corrupt packet headers.
+const maxPackageLen = 256 * 1024
+
+// A HeaderError is returned when a corrupt file or packet header is
+// encountered.
+type HeaderError struct {
+	// Name of the invalid header field.
+	Field string
+	// Value of the invalid header field.
+	Val uint32
+	// Description of the problem.
+	Msg string
+}
+
+func (e *HeaderError) Error() string {
+	return fmt.Sprintf("pcap: invalid %s (0x%08X); %s", e.Field, e.Val, e.Msg)
+}
+
+// A Reader reads packets from a pcap stream.
+type Reader struct {
+	// File header of the pcap stream.
+	hdr FileHeader
+	// Byte order of the pcap stream.
+	order binary.ByteOrder
+	// Resolution of the packet timestamps.
+	res time.Duration
+	// Underlying reader.
+	r io.Reader
+}
+
+// NewReader returns a new Reader reading from r. The file header is read and
+// validated immediately, and the byte order and timestamp resolution of the
+// stream are detected from its magic number.
+func NewReader(r io.Reader) (pr *Reader, err error) {
+	pr = &Reader{r: r}
+	var buf [24]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, err
+	}
+	magic := binary.LittleEndian.Uint32(buf[:])
+	switch magic {
+	case MagicLittleEndian:
+		pr.order, pr.res = binary.LittleEndian, time.Microsecond
+	case MagicBigEndian:
+		pr.order, pr.res = binary.BigEndian, time.Microsecond
+	case MagicNanoLittleEndian:
+		pr.order, pr.res = binary.LittleEndian, time.Nanosecond
+	case MagicNanoBigEndian:
+		pr.order, pr.res = binary.BigEndian, time.Nanosecond
+	default:
+		return nil, &HeaderError{Field: "magic number", Val: magic, Msg: "not a pcap file"}
+	}
+	if err := binary.Read(bytes.NewReader(buf[:]), pr.order, &pr.hdr); err != nil {
+		return nil, err
+	}
+	if pr.hdr.MajorVer != 2 {
+		return nil, &HeaderError{Field: "major version", Val: uint32(pr.hdr.MajorVer), Msg: "only version 2 is supported"}
+	}
+	return pr, nil
+}
+
+// Header returns the file header of the pcap stream.
+func (pr *Reader) Header() FileHeader {
+	return pr.hdr
+}
+
+// ReadPackage reads and returns the next packet in the pcap stream. At the end
+// of the stream ReadPackage returns io.EOF, and io.ErrUnexpectedEOF if the
+// stream ends in the middle of a packet.
+func (pr *Reader) ReadPackage() (pkg *Package, err error) {
+	pkg = &Package{res: pr.res, link: pr.hdr.Network}
+	err = binary.Read(pr.r, pr.order, &pkg.Hdr)
+	if err != nil {
+		return nil, err
+	}
+	max := uint32(maxPackageLen)
+	if pr.hdr.SnapLen > max {
+		max = pr.hdr.SnapLen
+	}
+	if pkg.Hdr.Len > max {
+		return nil, &HeaderError{Field: "packet length", Val: pkg.Hdr.Len, Msg: fmt.Sprintf("exceeds maximum of %d bytes", max)}
+	}
+	pkg.Buf = make([]byte, pkg.Hdr.Len)
+	_, err = io.ReadFull(pr.r, pkg.Buf)
+	if err != nil {
+		return nil, unexpected(err)
+	}
+	return pkg, nil
+}
+
+// unexpected converts io.EOF into io.ErrUnexpectedEOF; it is used when the
+// stream ends in the middle of a header or packet.
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// A PackageReader reads network packets from a capture stream.
+type PackageReader interface {
+	// ReadPackage reads and returns the next packet of the capture stream. At
+	// the end of the stream ReadPackage returns io.EOF.
+	ReadPackage() (*Package, error)
+}
+
+// NewPackageReader returns a new PackageReader reading from r. The container
+// format of the capture stream, pcap or pcapng, is detected from its magic
+// number.
+func NewPackageReader(r io.Reader) (PackageReader, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, err
+	}
+	mr := io.MultiReader(bytes.NewReader(buf[:]), r)
+	magic := binary.LittleEndian.Uint32(buf[:])
+	if magic == blockTypeSection {
+		return NewNgReader(mr)
+	}
+	return NewReader(mr)
+}
+
+// A File represents a pcap or pcapng file.
+type File struct {
+	PackageReader
+	f *os.File
+}
+
+// Open opens the named pcap or pcapng file for reading.
 func Open(filePath string) (f *File, err error) {
 	fr, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
-	f = &File{
-		ReadCloser: fr,
-	}
-	err = binary.Read(f, binary.LittleEndian, &f.hdr)
+	pr, err := NewPackageReader(bufio.NewReader(fr))
 	if err != nil {
 		fr.Close()
 		return nil, err
 	}
-	return f, nil
+	return &File{PackageReader: pr, f: fr}, nil
+}
+
+// Header returns the file header of a pcap file, or the zero FileHeader for
+// pcapng files, which have no single file header.
+func (f *File) Header() FileHeader {
+	if pr, ok := f.PackageReader.(*Reader); ok {
+		return pr.Header()
+	}
+	return FileHeader{}
+}
+
+// Close closes the pcap file.
+func (f *File) Close() error {
+	return f.f.Close()
 }
 
 // A Package represents a network packet.
 type Package struct {
 	Hdr PackageHeader
 	Buf []byte
+	// Resolution of the sub-second timestamp; microseconds if zero.
+	res time.Duration
+	// Data link type.
+	link uint32
 }
 
 // A PackageHeader structure is present at the beginning of each packet stored
@@ -66,7 +213,8 @@ type Package struct {
 type PackageHeader struct {
 	// Timestamp seconds.
 	Sec uint32
-	// Timestamp microseconds.
+	// Timestamp microseconds, or nanoseconds for pcap files with nanosecond
+	// resolution and for pcapng files.
 	Usec uint32
 	// Packet length saved in file.
 	Len uint32
@@ -74,27 +222,21 @@ type PackageHeader struct {
 	OrigLen uint32
 }
 
-// ReadPackage reads and returns the next packet in the pcap file.
-func (f *File) ReadPackage() (pkg *Package, err error) {
-	pkg = new(Package)
-	err = binary.Read(f, binary.LittleEndian, &pkg.Hdr)
-	if err != nil {
-		return nil, err
-	}
-	pkg.Buf = make([]byte, pkg.Hdr.Len)
-	_, err = f.Read(pkg.Buf)
-	if err != nil {
-		return nil, err
-	}
-	return pkg, nil
-}
-
 // Bytes returns the package's content as a byte slice.
 func (pkg *Package) Bytes() (buf []byte) {
 	return pkg.Buf
 }
 
+// LinkType returns the data link type of the package.
+func (pkg *Package) LinkType() uint32 {
+	return pkg.link
+}
+
 // Time returns the time when the package was sent.
 func (pkg *Package) Time() (t time.Time) {
-	return time.Unix(int64(pkg.Hdr.Sec), int64(pkg.Hdr.Usec*1000))
+	res := pkg.res
+	if res == 0 {
+		res = time.Microsecond
+	}
+	return time.Unix(int64(pkg.Hdr.Sec), int64(pkg.Hdr.Usec)*int64(res))
 }
@@ -0,0 +1,299 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testFile struct {
+	order binary.ByteOrder
+	hdr   FileHeader
+	pkgs  []*Package
+	times []time.Time
+}
+
+var golden = []testFile{
+	// i=0
+	{
+		order: binary.LittleEndian,
+		hdr:   FileHeader{Magic: 0xA1B2C3D4, MajorVer: 2, MinorVer: 4, SnapLen: 65535, Network: 1},
+		pkgs: []*Package{
+			{Hdr: PackageHeader{Sec: 1, Usec: 500000, Len: 3, OrigLen: 3}, Buf: []byte("foo")},
+			{Hdr: PackageHeader{Sec: 2, Usec: 1, Len: 2, OrigLen: 10}, Buf: []byte("ba")},
+		},
+		times: []time.Time{
+			time.Unix(1, 500000000),
+			time.Unix(2, 1000),
+		},
+	},
+	// i=1
+	{
+		order: binary.BigEndian,
+		hdr:   FileHeader{Magic: 0xA1B2C3D4, MajorVer: 2, MinorVer: 4, SnapLen: 65535, Network: 1},
+		pkgs: []*Package{
+			{Hdr: PackageHeader{Sec: 1, Usec: 500000, Len: 3, OrigLen: 3}, Buf: []byte("foo")},
+		},
+		times: []time.Time{
+			time.Unix(1, 500000000),
+		},
+	},
+	// i=2
+	{
+		order: binary.LittleEndian,
+		hdr:   FileHeader{Magic: 0xA1B23C4D, MajorVer: 2, MinorVer: 4, SnapLen: 65535, Network: 1},
+		pkgs: []*Package{
+			{Hdr: PackageHeader{Sec: 1, Usec: 500000001, Len: 3, OrigLen: 3}, Buf: []byte("foo")},
+		},
+		times: []time.Time{
+			time.Unix(1, 500000001),
+		},
+	},
+	// i=3
+	{
+		order: binary.BigEndian,
+		hdr:   FileHeader{Magic: 0xA1B23C4D, MajorVer: 2, MinorVer: 4, SnapLen: 65535, Network: 1},
+		pkgs: []*Package{
+			{Hdr: PackageHeader{Sec: 3, Usec: 7, Len: 0, OrigLen: 0}, Buf: []byte{}},
+		},
+		times: []time.Time{
+			time.Unix(3, 7),
+		},
+	},
+}
+
+// encode returns the binary representation of the provided pcap file.
+func encode(t *testing.T, order binary.ByteOrder, hdr FileHeader, pkgs []*Package) []byte {
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, order, hdr); err != nil {
+		t.Fatal(err)
+	}
+	for _, pkg := range pkgs {
+		if err := binary.Write(buf, order, pkg.Hdr); err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(pkg.Buf)
+	}
+	return buf.Bytes()
+}
+
+func TestReader(t *testing.T) {
+	for i, g := range golden {
+		buf := encode(t, g.order, g.hdr, g.pkgs)
+		r, err := NewReader(bytes.NewReader(buf))
+		if err != nil {
+			t.Errorf("i=%d: %s", i, err)
+			continue
+		}
+		if got := r.Header(); got != g.hdr {
+			t.Errorf("i=%d: expected %#v, got %#v.", i, g.hdr, got)
+			continue
+		}
+		for j, want := range g.pkgs {
+			got, err := r.ReadPackage()
+			if err != nil {
+				t.Errorf("i=%d, j=%d: %s", i, j, err)
+				break
+			}
+			if got.Hdr != want.Hdr || !bytes.Equal(got.Buf, want.Buf) {
+				t.Errorf("i=%d, j=%d: expected %#v, got %#v.", i, j, want, got)
+				break
+			}
+			if !got.Time().Equal(g.times[j]) {
+				t.Errorf("i=%d, j=%d: expected time %v, got %v.", i, j, g.times[j], got.Time())
+				break
+			}
+		}
+		if _, err := r.ReadPackage(); err != io.EOF {
+			t.Errorf("i=%d: expected io.EOF, got %v.", i, err)
+		}
+	}
+}
+
+func TestReaderCorrupt(t *testing.T) {
+	hdr := FileHeader{Magic: 0xA1B2C3D4, MajorVer: 2, MinorVer: 4, SnapLen: 65535, Network: 1}
+	pkg := &Package{Hdr: PackageHeader{Sec: 1, Len: 3, OrigLen: 3}, Buf: []byte("foo")}
+	valid := encode(t, binary.LittleEndian, hdr, []*Package{pkg})
+
+	// Invalid magic number.
+	buf := append([]byte{}, valid...)
+	copy(buf, "GIF8")
+	_, err := NewReader(bytes.NewReader(buf))
+	if e, ok := err.(*HeaderError); !ok || e.Field != "magic number" {
+		t.Errorf("magic: expected *HeaderError, got %v.", err)
+	}
+
+	// Invalid major version.
+	bad := hdr
+	bad.MajorVer = 3
+	buf = encode(t, binary.LittleEndian, bad, nil)
+	_, err = NewReader(bytes.NewReader(buf))
+	if e, ok := err.(*HeaderError); !ok || e.Field != "major version" {
+		t.Errorf("version: expected *HeaderError, got %v.", err)
+	}
+
+	// Truncated file header.
+	_, err = NewReader(bytes.NewReader(valid[:10]))
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("file header: expected io.ErrUnexpectedEOF, got %v.", err)
+	}
+
+	// Invalid packet length.
+	huge := &Package{Hdr: PackageHeader{Len: 0xFFFFFFFF, OrigLen: 3}}
+	buf = encode(t, binary.LittleEndian, hdr, []*Package{huge})
+	r, err := NewReader(bytes.NewReader(buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.ReadPackage()
+	if e, ok := err.(*HeaderError); !ok || e.Field != "packet length" {
+		t.Errorf("packet length: expected *HeaderError, got %v.", err)
+	}
+
+	// Truncated packet data and header.
+	for _, n := range []int{len(valid) - 1, len(valid) - 5} {
+		r, err = NewReader(bytes.NewReader(valid[:n]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = r.ReadPackage()
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("n=%d: expected io.ErrUnexpectedEOF, got %v.", n, err)
+		}
+	}
+}
+
+// oneByteReader returns at most one byte for each call to Read.
+type oneByteReader struct {
+	r io.Reader
+}
+
+func (r oneByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return r.r.Read(p[:1])
+}
+
+func TestReaderShortReads(t *testing.T) {
+	g := golden[0]
+	buf := encode(t, g.order, g.hdr, g.pkgs)
+	r, err := NewReader(oneByteReader{bytes.NewReader(buf)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j, want := range g.pkgs {
+		got, err := r.ReadPackage()
+		if err != nil {
+			t.Fatalf("j=%d: %s", j, err)
+		}
+		if !reflect.DeepEqual(got.Buf, want.Buf) {
+			t.Errorf("j=%d: expected %q, got %q.", j, want.Buf, got.Buf)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	type pkg struct {
+		ts      time.Time
+		data    []byte
+		origLen int
+	}
+	pkgs := []pkg{
+		{ts: time.Unix(1, 500000000), data: []byte("foo"), origLen: 3},
+		{ts: time.Unix(2, 1999), data: []byte("foobar"), origLen: 6},
+		{ts: time.Unix(3, 0), data: []byte("ba"), origLen: 0},
+		{ts: time.Unix(4, 0), data: []byte{}, origLen: 100},
+	}
+	want := []*Package{
+		{Hdr: PackageHeader{Sec: 1, Usec: 500000, Len: 3, OrigLen: 3}, Buf: []byte("foo")},
+		{Hdr: PackageHeader{Sec: 2, Usec: 1, Len: 4, OrigLen: 6}, Buf: []byte("foob")},
+		{Hdr: PackageHeader{Sec: 3, Usec: 0, Len: 2, OrigLen: 2}, Buf: []byte("ba")},
+		{Hdr: PackageHeader{Sec: 4, Usec: 0, Len: 0, OrigLen: 100}, Buf: []byte{}},
+	}
+
+	buf := new(bytes.Buffer)
+	w, err := NewWriter(buf, 4, LinkTypeRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range pkgs {
+		if err := w.WritePackage(p.ts, p.data, p.origLen); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Header(); got != w.Header() {
+		t.Errorf("expected %#v, got %#v.", w.Header(), got)
+	}
+	for j := range want {
+		got, err := r.ReadPackage()
+		if err != nil {
+			t.Fatalf("j=%d: %s", j, err)
+		}
+		if got.Hdr != want[j].Hdr || !bytes.Equal(got.Buf, want[j].Buf) {
+			t.Errorf("j=%d: expected %#v, got %#v.", j, want[j], got)
+		}
+	}
+	if _, err := r.ReadPackage(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v.", err)
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	for i, g := range golden {
+		// The writer only produces microsecond resolution files.
+		if g.hdr.Magic != MagicLittleEndian {
+			continue
+		}
+		buf := new(bytes.Buffer)
+		w, err := NewWriter(buf, g.hdr.SnapLen, g.hdr.Network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, pkg := range g.pkgs {
+			if err := w.WritePackage(g.times[j], pkg.Buf, int(pkg.Hdr.OrigLen)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if want := encode(t, binary.LittleEndian, g.hdr, g.pkgs); !bytes.Equal(buf.Bytes(), want) {
+			t.Errorf("i=%d: expected % X, got % X.", i, want, buf.Bytes())
+		}
+	}
+}
+
+func TestFileHeader(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w, err := NewWriter(buf, 1500, LinkTypeRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := Open(writeTemp(t, buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if got, want := f.Header(), w.Header(); got != want {
+		t.Errorf("expected %#v, got %#v.", want, got)
+	}
+	// pcapng files have no single file header.
+	buf.Reset()
+	if _, err := NewNgWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	ng, err := Open(writeTemp(t, buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ng.Close()
+	if got := ng.Header(); got != (FileHeader{}) {
+		t.Errorf("expected zero file header, got %#v.", got)
+	}
+}
@@ -0,0 +1,413 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+	"time"
+)
+
+// Block types of pcapng files.
+const (
+	blockTypeInterface = 0x00000001
+	blockTypeSimple    = 0x00000003
+	blockTypeEnhanced  = 0x00000006
+	blockTypeSection   = 0x0A0D0D0A
+)
+
+// byteOrderMagic is present in each section header block, and is used to
+// detect the byte order of the section.
+const byteOrderMagic = 0x1A2B3C4D
+
+// Option codes of interface description blocks.
+const (
+	optEndOfOpt = 0
+	optTSResol  = 9
+	optTSOffset = 14
+)
+
+// maxBlockLen specifies the maximum length of a pcapng block; it protects
+// against huge allocations caused by corrupt block headers.
+const maxBlockLen = 16 * 1024 * 1024
+
+// An Interface describes a capture interface of a pcapng section.
+type Interface struct {
+	// Data link type.
+	LinkType uint32
+	// Max length of captured packets; zero if unlimited.
+	SnapLen uint32
+	// Number of timestamp units per second.
+	UnitsPerSec uint64
+	// Offset in seconds added to each timestamp.
+	Offset int64
+}
+
+// time returns the time of the provided timestamp, as recorded by the
+// interface.
+func (iface *Interface) time(ts uint64) (sec uint32, nsec uint32) {
+	s := ts / iface.UnitsPerSec
+	frac := ts % iface.UnitsPerSec
+	hi, lo := bits.Mul64(frac, uint64(time.Second))
+	ns, _ := bits.Div64(hi, lo, iface.UnitsPerSec)
+	return uint32(int64(s) + iface.Offset), uint32(ns)
+}
+
+// A NgReader reads packets from a pcapng stream.
+type NgReader struct {
+	// Byte order of the current section.
+	order binary.ByteOrder
+	// Interfaces of the current section.
+	ifaces []Interface
+	// Underlying reader.
+	r io.Reader
+}
+
+// NewNgReader returns a new NgReader reading from r. The section header block
+// at the beginning of the stream is read and validated immediately.
+func NewNgReader(r io.Reader) (nr *NgReader, err error) {
+	nr = &NgReader{r: r}
+	typ, body, err := nr.readBlock()
+	if err != nil {
+		return nil, err
+	}
+	if typ != blockTypeSection {
+		return nil, &HeaderError{Field: "block type", Val: typ, Msg: "not a pcapng file"}
+	}
+	if err := nr.parseSection(body); err != nil {
+		return nil, err
+	}
+	return nr, nil
+}
+
+// Interfaces returns the interfaces of the current section.
+func (nr *NgReader) Interfaces() []Interface {
+	return nr.ifaces
+}
+
+// ReadPackage reads and returns the next packet in the pcapng stream. Blocks
+// of unknown type are skipped. At the end of the stream ReadPackage returns
+// io.EOF, and io.ErrUnexpectedEOF if the stream ends in the middle of a block.
+func (nr *NgReader) ReadPackage() (pkg *Package, err error) {
+	for {
+		typ, body, err := nr.readBlock()
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case blockTypeSection:
+			if err := nr.parseSection(body); err != nil {
+				return nil, err
+			}
+		case blockTypeInterface:
+			if err := nr.parseInterface(body); err != nil {
+				return nil, err
+			}
+		case blockTypeEnhanced:
+			return nr.parseEnhanced(body)
+		case blockTypeSimple:
+			return nr.parseSimple(body)
+		}
+	}
+}
+
+// readBlock reads the next block of the pcapng stream, and returns its type and
+// body.
+func (nr *NgReader) readBlock() (typ uint32, body []byte, err error) {
+	var hdr [8]byte
+	if _, err := io.ReadFull(nr.r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	// The block type of section header blocks is a palindrome, and the byte
+	// order is given by the byte-order magic which follows the length.
+	if binary.LittleEndian.Uint32(hdr[:4]) == blockTypeSection {
+		var magic [4]byte
+		if _, err := io.ReadFull(nr.r, magic[:]); err != nil {
+			return 0, nil, unexpected(err)
+		}
+		switch binary.LittleEndian.Uint32(magic[:]) {
+		case byteOrderMagic:
+			nr.order = binary.LittleEndian
+		case bits.ReverseBytes32(byteOrderMagic):
+			nr.order = binary.BigEndian
+		default:
+			return 0, nil, &HeaderError{Field: "byte-order magic", Val: binary.LittleEndian.Uint32(magic[:]), Msg: "not a pcapng file"}
+		}
+		body, err = nr.readBody(nr.order.Uint32(hdr[4:]), 4)
+		if err != nil {
+			return 0, nil, err
+		}
+		return blockTypeSection, append(magic[:], body...), nil
+	}
+	if nr.order == nil {
+		return 0, nil, &HeaderError{Field: "block type", Val: binary.LittleEndian.Uint32(hdr[:4]), Msg: "missing section header block"}
+	}
+	typ = nr.order.Uint32(hdr[:4])
+	body, err = nr.readBody(nr.order.Uint32(hdr[4:]), 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	return typ, body, nil
+}
+
+// readBody reads and returns the body of a block with the provided total
+// length, of which n bytes of the body have already been read.
+func (nr *NgReader) readBody(total uint32, n int) (body []byte, err error) {
+	if total < uint32(12+n) || total%4 != 0 || total > maxBlockLen {
+		return nil, &HeaderError{Field: "block length", Val: total, Msg: "corrupt block"}
+	}
+	buf := make([]byte, int(total)-8-n)
+	if _, err := io.ReadFull(nr.r, buf); err != nil {
+		return nil, unexpected(err)
+	}
+	trailer := buf[len(buf)-4:]
+	if nr.order.Uint32(trailer) != total {
+		return nil, &HeaderError{Field: "trailing block length", Val: nr.order.Uint32(trailer), Msg: fmt.Sprintf("mismatch with block length %d", total)}
+	}
+	return buf[:len(buf)-4], nil
+}
+
+// parseSection parses the body of a section header block, and starts a new
+// section.
+func (nr *NgReader) parseSection(body []byte) error {
+	if len(body) < 16 {
+		return &HeaderError{Field: "block length", Val: uint32(len(body)), Msg: "section header block too short"}
+	}
+	if major := nr.order.Uint16(body[4:]); major != 1 {
+		return &HeaderError{Field: "major version", Val: uint32(major), Msg: "only version 1 is supported"}
+	}
+	nr.ifaces = nil
+	return nil
+}
+
+// parseInterface parses the body of an interface description block, and adds
+// the interface to the current section.
+func (nr *NgReader) parseInterface(body []byte) error {
+	if len(body) < 8 {
+		return &HeaderError{Field: "block length", Val: uint32(len(body)), Msg: "interface description block too short"}
+	}
+	iface := Interface{
+		LinkType:    uint32(nr.order.Uint16(body)),
+		SnapLen:     nr.order.Uint32(body[4:]),
+		UnitsPerSec: 1000000,
+	}
+	opts := body[8:]
+	for len(opts) >= 4 {
+		code := nr.order.Uint16(opts)
+		n := int(nr.order.Uint16(opts[2:]))
+		opts = opts[4:]
+		if code == optEndOfOpt {
+			break
+		}
+		if n > len(opts) {
+			return &HeaderError{Field: "option length", Val: uint32(n), Msg: "exceeds block length"}
+		}
+		val := opts[:n]
+		switch {
+		case code == optTSResol && n == 1:
+			exp := uint(val[0] & 0x7F)
+			if val[0]&0x80 != 0 {
+				if exp > 63 {
+					return &HeaderError{Field: "timestamp resolution", Val: uint32(val[0]), Msg: "too fine"}
+				}
+				iface.UnitsPerSec = 1 << exp
+			} else {
+				if exp > 19 {
+					return &HeaderError{Field: "timestamp resolution", Val: uint32(val[0]), Msg: "too fine"}
+				}
+				iface.UnitsPerSec = 1
+				for i := uint(0); i < exp; i++ {
+					iface.UnitsPerSec *= 10
+				}
+			}
+		case code == optTSOffset && n == 8:
+			iface.Offset = int64(nr.order.Uint64(val))
+		}
+		// Options are padded to 32 bits.
+		n = (n + 3) &^ 3
+		if n > len(opts) {
+			n = len(opts)
+		}
+		opts = opts[n:]
+	}
+	nr.ifaces = append(nr.ifaces, iface)
+	return nil
+}
+
+// parseEnhanced parses the body of an enhanced packet block.
+func (nr *NgReader) parseEnhanced(body []byte) (pkg *Package, err error) {
+	if len(body) < 20 {
+		return nil, &HeaderError{Field: "block length", Val: uint32(len(body)), Msg: "enhanced packet block too short"}
+	}
+	id := nr.order.Uint32(body)
+	if id >= uint32(len(nr.ifaces)) {
+		return nil, &HeaderError{Field: "interface ID", Val: id, Msg: fmt.Sprintf("only %d interfaces defined", len(nr.ifaces))}
+	}
+	iface := &nr.ifaces[id]
+	ts := uint64(nr.order.Uint32(body[4:]))<<32 | uint64(nr.order.Uint32(body[8:]))
+	n := nr.order.Uint32(body[12:])
+	if n > uint32(len(body)-20) {
+		return nil, &HeaderError{Field: "captured packet length", Val: n, Msg: "exceeds block length"}
+	}
+	sec, nsec := iface.time(ts)
+	pkg = &Package{
+		Hdr: PackageHeader{
+			Sec:     sec,
+			Usec:    nsec,
+			Len:     n,
+			OrigLen: nr.order.Uint32(body[16:]),
+		},
+		Buf:  body[20 : 20+n],
+		res:  time.Nanosecond,
+		link: iface.LinkType,
+	}
+	return pkg, nil
+}
+
+// parseSimple parses the body of a simple packet block.
+func (nr *NgReader) parseSimple(body []byte) (pkg *Package, err error) {
+	if len(body) < 4 {
+		return nil, &HeaderError{Field: "block length", Val: uint32(len(body)), Msg: "simple packet block too short"}
+	}
+	if len(nr.ifaces) == 0 {
+		return nil, &HeaderError{Field: "block type", Val: blockTypeSimple, Msg: "no interface defined"}
+	}
+	iface := &nr.ifaces[0]
+	origLen := nr.order.Uint32(body)
+	n := origLen
+	if iface.SnapLen != 0 && n > iface.SnapLen {
+		n = iface.SnapLen
+	}
+	if n > uint32(len(body)-4) {
+		return nil, &HeaderError{Field: "original packet length", Val: origLen, Msg: "exceeds block length"}
+	}
+	pkg = &Package{
+		Hdr: PackageHeader{
+			Len:     n,
+			OrigLen: origLen,
+		},
+		Buf:  body[4 : 4+n],
+		res:  time.Nanosecond,
+		link: iface.LinkType,
+	}
+	return pkg, nil
+}
+
+// A NgWriter writes packets to a pcapng stream. The stream is encoded using
+// little endian byte order and nanosecond timestamp resolution.
+type NgWriter struct {
+	// Interfaces of the section.
+	ifaces []Interface
+	// Underlying writer.
+	w io.Writer
+}
+
+// NewNgWriter returns a new NgWriter writing to w, and writes the section
+// header block of the pcapng stream.
+func NewNgWriter(w io.Writer) (nw *NgWriter, err error) {
+	nw = &NgWriter{w: w}
+	body := new(bytes.Buffer)
+	binary.Write(body, binary.LittleEndian, uint32(byteOrderMagic))
+	// Major and minor version.
+	binary.Write(body, binary.LittleEndian, uint16(1))
+	binary.Write(body, binary.LittleEndian, uint16(0))
+	// Section length; unspecified.
+	binary.Write(body, binary.LittleEndian, int64(-1))
+	if err := nw.writeBlock(blockTypeSection, body.Bytes()); err != nil {
+		return nil, err
+	}
+	return nw, nil
+}
+
+// AddInterface writes an interface description block to the pcapng stream, and
+// returns the ID of the new interface. Packets longer than snapLen bytes are
+// truncated, unless snapLen is zero.
+func (nw *NgWriter) AddInterface(linkType, snapLen uint32) (id int, err error) {
+	body := new(bytes.Buffer)
+	binary.Write(body, binary.LittleEndian, uint16(linkType))
+	// Reserved.
+	binary.Write(body, binary.LittleEndian, uint16(0))
+	binary.Write(body, binary.LittleEndian, snapLen)
+	// Nanosecond timestamp resolution; padded to 32 bits.
+	binary.Write(body, binary.LittleEndian, [2]uint16{optTSResol, 1})
+	body.Write([]byte{9, 0, 0, 0})
+	binary.Write(body, binary.LittleEndian, [2]uint16{optEndOfOpt, 0})
+	if err := nw.writeBlock(blockTypeInterface, body.Bytes()); err != nil {
+		return 0, err
+	}
+	iface := Interface{
+		LinkType:    linkType,
+		SnapLen:     snapLen,
+		UnitsPerSec: uint64(time.Second),
+	}
+	nw.ifaces = append(nw.ifaces, iface)
+	return len(nw.ifaces) - 1, nil
+}
+
+// WritePackage writes an enhanced packet block with the provided timestamp and
+// content to the pcapng stream. id specifies the interface on which the packet
+// was captured, and origLen the length of the packet on the wire; it is
+// increased to len(data) if smaller. The stored content is truncated to the
+// snapshot length of the interface.
+func (nw *NgWriter) WritePackage(id int, ts time.Time, data []byte, origLen int) error {
+	if id < 0 || id >= len(nw.ifaces) {
+		return fmt.Errorf("pcap.NgWriter.WritePackage: invalid interface ID %d; only %d interfaces defined", id, len(nw.ifaces))
+	}
+	data, origLen = nw.truncate(id, data, origLen)
+	t := uint64(ts.UnixNano())
+	body := new(bytes.Buffer)
+	binary.Write(body, binary.LittleEndian, uint32(id))
+	binary.Write(body, binary.LittleEndian, uint32(t>>32))
+	binary.Write(body, binary.LittleEndian, uint32(t))
+	binary.Write(body, binary.LittleEndian, uint32(len(data)))
+	binary.Write(body, binary.LittleEndian, uint32(origLen))
+	body.Write(data)
+	return nw.writeBlock(blockTypeEnhanced, body.Bytes())
+}
+
+// WriteSimplePackage writes a simple packet block with the provided content to
+// the pcapng stream. Simple packets have no timestamp and are captured on the
+// first interface, which must have been added. As the captured length of simple
+// packets is derived from the original length, data must hold origLen bytes or
+// at least the snapshot length of the interface.
+func (nw *NgWriter) WriteSimplePackage(data []byte, origLen int) error {
+	if len(nw.ifaces) == 0 {
+		return fmt.Errorf("pcap.NgWriter.WriteSimplePackage: no interface defined")
+	}
+	data, origLen = nw.truncate(0, data, origLen)
+	if n := nw.ifaces[0].SnapLen; len(data) != origLen && uint32(len(data)) != n {
+		return fmt.Errorf("pcap.NgWriter.WriteSimplePackage: invalid data length %d; expected %d or snapshot length %d", len(data), origLen, n)
+	}
+	body := new(bytes.Buffer)
+	binary.Write(body, binary.LittleEndian, uint32(origLen))
+	body.Write(data)
+	return nw.writeBlock(blockTypeSimple, body.Bytes())
+}
+
+// truncate truncates data to the snapshot length of the given interface, and
+// adjusts origLen to be at least len(data).
+func (nw *NgWriter) truncate(id int, data []byte, origLen int) ([]byte, int) {
+	if origLen < len(data) {
+		origLen = len(data)
+	}
+	if snapLen := nw.ifaces[id].SnapLen; snapLen != 0 && uint32(len(data)) > snapLen {
+		data = data[:snapLen]
+	}
+	return data, origLen
+}
+
+// writeBlock writes a block of the given type and body to the pcapng stream.
+// The body is padded to 32 bits.
+func (nw *NgWriter) writeBlock(typ uint32, body []byte) error {
+	pad := (4 - len(body)%4) % 4
+	total := uint32(12 + len(body) + pad)
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, typ)
+	binary.Write(buf, binary.LittleEndian, total)
+	buf.Write(body)
+	buf.Write(make([]byte, pad))
+	binary.Write(buf, binary.LittleEndian, total)
+	_, err := nw.w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,220 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// block returns the binary representation of a pcapng block.
+func block(order binary.ByteOrder, typ uint32, body ...interface{}) []byte {
+	buf := new(bytes.Buffer)
+	for _, v := range body {
+		binary.Write(buf, order, v)
+	}
+	for buf.Len()%4 != 0 {
+		buf.WriteByte(0)
+	}
+	total := uint32(12 + buf.Len())
+	out := new(bytes.Buffer)
+	binary.Write(out, order, typ)
+	binary.Write(out, order, total)
+	out.Write(buf.Bytes())
+	binary.Write(out, order, total)
+	return out.Bytes()
+}
+
+// sectionBlock returns the binary representation of a section header block.
+func sectionBlock(order binary.ByteOrder) []byte {
+	return block(order, blockTypeSection, uint32(byteOrderMagic), uint16(1), uint16(0), int64(-1))
+}
+
+func TestNgReader(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		var buf []byte
+		buf = append(buf, sectionBlock(order)...)
+		// Ethernet interface with microsecond resolution.
+		buf = append(buf, block(order, blockTypeInterface, uint16(LinkTypeEthernet), uint16(0), uint32(4))...)
+		// Raw IP interface with 2^-10 second resolution and a 100 second offset.
+		buf = append(buf, block(order, blockTypeInterface, uint16(LinkTypeRaw), uint16(0), uint32(0),
+			[2]uint16{optTSResol, 1}, [4]byte{0x8A}, [2]uint16{optTSOffset, 8}, int64(100), [2]uint16{optEndOfOpt, 0})...)
+		// Unknown block.
+		buf = append(buf, block(order, 0x0BAD, []byte("unknown block"))...)
+		// Enhanced packet on interface 1.
+		buf = append(buf, block(order, blockTypeEnhanced, uint32(1), uint32(0), uint32(3*1024+512), uint32(3), uint32(3), []byte("foo"))...)
+		// Simple packet on interface 0, truncated to the snapshot length.
+		buf = append(buf, block(order, blockTypeSimple, uint32(6), []byte("foob"))...)
+		// Enhanced packet on interface 0.
+		buf = append(buf, block(order, blockTypeEnhanced, uint32(0), uint32(0), uint32(2500001), uint32(2), uint32(2), []byte("ba"))...)
+
+		r, err := NewPackageReader(bytes.NewReader(buf))
+		if err != nil {
+			t.Fatalf("%v: %s", order, err)
+		}
+		if _, ok := r.(*NgReader); !ok {
+			t.Fatalf("%v: expected *NgReader, got %T.", order, r)
+		}
+		want := []struct {
+			hdr  PackageHeader
+			buf  string
+			link uint32
+			time time.Time
+		}{
+			{hdr: PackageHeader{Sec: 103, Usec: 500000000, Len: 3, OrigLen: 3}, buf: "foo", link: LinkTypeRaw, time: time.Unix(103, 500000000)},
+			{hdr: PackageHeader{Len: 4, OrigLen: 6}, buf: "foob", link: LinkTypeEthernet, time: time.Unix(0, 0)},
+			{hdr: PackageHeader{Sec: 2, Usec: 500001000, Len: 2, OrigLen: 2}, buf: "ba", link: LinkTypeEthernet, time: time.Unix(2, 500001000)},
+		}
+		for j, w := range want {
+			got, err := r.ReadPackage()
+			if err != nil {
+				t.Fatalf("%v, j=%d: %s", order, j, err)
+			}
+			if got.Hdr != w.hdr || string(got.Buf) != w.buf || got.LinkType() != w.link || !got.Time().Equal(w.time) {
+				t.Errorf("%v, j=%d: expected %v %q %d %v, got %v %q %d %v.", order, j, w.hdr, w.buf, w.link, w.time, got.Hdr, got.Buf, got.LinkType(), got.Time())
+			}
+		}
+		if _, err := r.ReadPackage(); err != io.EOF {
+			t.Errorf("%v: expected io.EOF, got %v.", order, err)
+		}
+	}
+}
+
+func TestNgWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w, err := NewNgWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eth, err := w.AddInterface(LinkTypeEthernet, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := w.AddInterface(LinkTypeRaw, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WritePackage(raw, time.Unix(1, 999999999), []byte("foobar"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WritePackage(eth, time.Unix(2, 1), []byte("foo"), 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteSimplePackage([]byte("bar"), 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WritePackage(2, time.Unix(3, 0), nil, 0); err == nil {
+		t.Error("expected error for invalid interface ID.")
+	}
+
+	f, err := Open(writeTemp(t, buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	want := []*Package{
+		{Hdr: PackageHeader{Sec: 1, Usec: 999999999, Len: 4, OrigLen: 6}, Buf: []byte("foob"), link: LinkTypeRaw},
+		{Hdr: PackageHeader{Sec: 2, Usec: 1, Len: 3, OrigLen: 10}, Buf: []byte("foo"), link: LinkTypeEthernet},
+		{Hdr: PackageHeader{Len: 3, OrigLen: 3}, Buf: []byte("bar"), link: LinkTypeEthernet},
+	}
+	for j := range want {
+		got, err := f.ReadPackage()
+		if err != nil {
+			t.Fatalf("j=%d: %s", j, err)
+		}
+		if got.Hdr != want[j].Hdr || !bytes.Equal(got.Buf, want[j].Buf) || got.LinkType() != want[j].link {
+			t.Errorf("j=%d: expected %#v, got %#v.", j, want[j], got)
+		}
+	}
+	if _, err := f.ReadPackage(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v.", err)
+	}
+}
+
+func TestNgReaderCorrupt(t *testing.T) {
+	order := binary.LittleEndian
+	shb := sectionBlock(order)
+	idb := block(order, blockTypeInterface, uint16(LinkTypeEthernet), uint16(0), uint32(0))
+	epb := block(order, blockTypeEnhanced, uint32(0), uint32(0), uint32(0), uint32(3), uint32(3), []byte("foo"))
+
+	golden := []struct {
+		buf   []byte
+		field string
+	}{
+		// i=0
+		{buf: concat(shb[:12], []byte{0xFF, 0, 0, 0}, shb[16:]), field: "major version"},
+		// i=1
+		{buf: concat(shb[:8], []byte{1, 2, 3, 4}, shb[12:]), field: "byte-order magic"},
+		// i=2
+		{buf: concat(shb, epb), field: "interface ID"},
+		// i=3
+		{buf: concat(shb, idb, epb[:4], []byte{13, 0, 0, 0}, epb[8:]), field: "block length"},
+		// i=4
+		{buf: concat(shb, idb, epb[:len(epb)-4], []byte{0, 1, 0, 0}), field: "trailing block length"},
+		// i=5
+		{buf: concat(shb, idb, epb[:20], []byte{0xFF, 0, 0, 0}, epb[24:]), field: "captured packet length"},
+	}
+	for i, g := range golden {
+		r, err := NewNgReader(bytes.NewReader(g.buf))
+		if err == nil {
+			_, err = r.ReadPackage()
+		}
+		if e, ok := err.(*HeaderError); !ok || e.Field != g.field {
+			t.Errorf("i=%d: expected *HeaderError of %s, got %v.", i, g.field, err)
+		}
+	}
+
+	// Truncated block.
+	r, err := NewNgReader(bytes.NewReader(concat(shb, idb, epb[:len(epb)-1])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadPackage(); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v.", err)
+	}
+}
+
+func TestNewPackageReader(t *testing.T) {
+	g := golden[1]
+	r, err := NewPackageReader(bytes.NewReader(encode(t, g.order, g.hdr, g.pkgs)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.(*Reader); !ok {
+		t.Fatalf("expected *Reader, got %T.", r)
+	}
+	pkg, err := r.ReadPackage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Hdr != g.pkgs[0].Hdr || pkg.LinkType() != g.hdr.Network {
+		t.Errorf("expected %#v, got %#v.", g.pkgs[0], pkg)
+	}
+}
+
+// concat returns the concatenation of the provided byte slices.
+func concat(bufs ...[]byte) []byte {
+	var out []byte
+	for _, buf := range bufs {
+		out = append(out, buf...)
+	}
+	return out
+}
+
+// writeTemp writes buf to a temporary file and returns its path.
+func writeTemp(t *testing.T, buf []byte) string {
+	dir, err := ioutil.TempDir("", "pcap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	filePath := filepath.Join(dir, "test.pcapng")
+	if err := ioutil.WriteFile(filePath, buf, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filePath
+}
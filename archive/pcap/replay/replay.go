@@ -0,0 +1,155 @@
+// Package replay replays the UDP payloads of captured packets to a network
+// connection.
+//
+// Packets are sent either as fast as possible or following the original
+// inter-packet timing of the capture, optionally sped up or slowed down, and
+// subject to an optional rate limit.
+package replay
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mewmew/playground/archive/pcap"
+	"github.com/mewmew/playground/archive/pcap/filter"
+	"github.com/mewmew/playground/archive/pcap/layers"
+)
+
+// Options specify how packets are replayed. The zero value replays each
+// capture once, as fast as possible.
+type Options struct {
+	// Speed multiplier of the original timing; e.g. 1 preserves the original
+	// inter-packet delays and 2 halves them. Packets are sent as fast as
+	// possible if zero.
+	Speed float64
+	// Maximum number of packets sent per second; unlimited if zero.
+	Rate float64
+	// Number of times the captures are replayed by ReplayFiles; once if zero
+	// and forever if negative.
+	Loop int
+	// Filter selecting the packets replayed by ReplayFiles; all packets if nil.
+	Filter filter.Filter
+}
+
+// Stats records the number of replayed and skipped packets.
+type Stats struct {
+	// Number of packets sent.
+	Packets int
+	// Number of payload bytes sent.
+	Bytes int64
+	// Number of packets skipped, as they were not UDP packets or could not be
+	// fully decoded.
+	Skipped int
+}
+
+// A Replayer replays the UDP payloads of captured packets, writing each
+// payload using a separate call to Write of the underlying writer; e.g. a
+// connected UDP socket as returned by net.Dial("udp", addr).
+type Replayer struct {
+	// Statistics of the replayed packets.
+	Stats Stats
+	// Underlying writer.
+	w io.Writer
+	// Replay options.
+	opts Options
+	// Time of the last sent packet.
+	last time.Time
+}
+
+// NewReplayer returns a new Replayer writing to w.
+func NewReplayer(w io.Writer, opts Options) *Replayer {
+	return &Replayer{w: w, opts: opts}
+}
+
+// Replay replays the UDP payloads of the packets read from pr, until the end of
+// the capture stream. The original timing is measured from the first packet.
+// Packets which precede their predecessor in time are sent without delay.
+func (r *Replayer) Replay(pr pcap.PackageReader) error {
+	var (
+		// Time of the first packet in the capture and at replay.
+		first, start time.Time
+		// Interval between packets imposed by the rate limit.
+		interval time.Duration
+	)
+	if r.opts.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / r.opts.Rate)
+	}
+	for {
+		pkg, err := pr.ReadPackage()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		p, err := layers.DecodePackage(pkg)
+		if err != nil || p.UDP == nil {
+			r.Stats.Skipped++
+			continue
+		}
+
+		// Wait until the packet is due.
+		now := time.Now()
+		if start.IsZero() {
+			first, start = pkg.Time(), now
+		}
+		due := now
+		if r.opts.Speed > 0 {
+			offset := time.Duration(float64(pkg.Time().Sub(first)) / r.opts.Speed)
+			if t := start.Add(offset); t.After(due) {
+				due = t
+			}
+		}
+		if !r.last.IsZero() {
+			if t := r.last.Add(interval); t.After(due) {
+				due = t
+			}
+		}
+		if d := due.Sub(now); d > 0 {
+			time.Sleep(d)
+		}
+
+		if _, err := r.w.Write(p.UDP.Payload); err != nil {
+			return fmt.Errorf("replay: unable to send packet; %v", err)
+		}
+		r.last = time.Now()
+		r.Stats.Packets++
+		r.Stats.Bytes += int64(len(p.UDP.Payload))
+	}
+}
+
+// ReplayFiles replays the UDP payloads of the packets of the provided pcap
+// files, in order, as many times as specified by the loop option.
+func (r *Replayer) ReplayFiles(filePaths []string) error {
+	n := r.opts.Loop
+	if n == 0 {
+		// The zero value replays the captures once.
+		n = 1
+	}
+	for i := 0; n < 0 || i < n; i++ {
+		for _, filePath := range filePaths {
+			if err := r.replayFile(filePath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// replayFile replays the UDP payloads of the packets of the pcap file.
+func (r *Replayer) replayFile(filePath string) error {
+	f, err := pcap.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var pr pcap.PackageReader = f
+	if r.opts.Filter != nil {
+		pr = filter.NewReader(f, r.opts.Filter)
+	}
+	if err := r.Replay(pr); err != nil {
+		return fmt.Errorf("%s: %v", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package replay
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mewmew/playground/archive/pcap"
+	"github.com/mewmew/playground/archive/pcap/filter"
+)
+
+// writeFile writes a pcap file of raw IPv4 packets to a temporary directory,
+// with one UDP packet to port 53 per payload, sent at offsets of delay. A
+// non-UDP packet is written after the first packet.
+func writeFile(t *testing.T, delay time.Duration, payloads ...string) string {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	filePath := filepath.Join(dir, "test.pcap")
+	f, err := os.Create(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	pw, err := pcap.NewWriter(f, 65535, pcap.LinkTypeRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(1500000000, 0)
+	for i, payload := range payloads {
+		ts := start.Add(time.Duration(i) * delay)
+		if err := pw.WritePackage(ts, udpPacket(payload), 28+len(payload)); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			// ICMP echo request.
+			icmp := udpPacket("")
+			icmp[9] = 1
+			if err := pw.WritePackage(ts, icmp[:28], 28); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return filePath
+}
+
+// udpPacket returns an IPv4 packet from 10.0.0.1:1024 to 10.0.0.2:53 with the
+// provided UDP payload. The checksums are left zero.
+func udpPacket(payload string) []byte {
+	buf := make([]byte, 28+len(payload))
+	buf[0] = 0x45
+	binary.BigEndian.PutUint16(buf[2:4], uint16(len(buf)))
+	buf[8] = 64
+	buf[9] = 17
+	copy(buf[12:16], []byte{10, 0, 0, 1})
+	copy(buf[16:20], []byte{10, 0, 0, 2})
+	binary.BigEndian.PutUint16(buf[20:22], 1024)
+	binary.BigEndian.PutUint16(buf[22:24], 53)
+	binary.BigEndian.PutUint16(buf[24:26], uint16(8+len(payload)))
+	copy(buf[28:], payload)
+	return buf
+}
+
+// listen returns a loopback UDP listener and a connection to it.
+func listen(t *testing.T) (l, conn *net.UDPConn) {
+	l, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	conn, err = net.DialUDP("udp", nil, l.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return l, conn
+}
+
+// receive receives n datagrams from the listener.
+func receive(t *testing.T, l *net.UDPConn, n int) []string {
+	var got []string
+	buf := make([]byte, 65535)
+	l.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for i := 0; i < n; i++ {
+		m, _, err := l.ReadFromUDP(buf)
+		if err != nil {
+			t.Fatalf("unable to receive datagram %d of %d; %v", i+1, n, err)
+		}
+		got = append(got, string(buf[:m]))
+	}
+	return got
+}
+
+func TestReplayFiles(t *testing.T) {
+	golden := []struct {
+		opts Options
+		want []string
+		// Number of skipped packets.
+		skipped int
+		// Minimum duration of the replay.
+		min time.Duration
+	}{
+		// i=0; as fast as possible.
+		{opts: Options{}, want: []string{"a", "b", "c"}, skipped: 1},
+		// i=1; original timing.
+		{opts: Options{Speed: 1}, want: []string{"a", "b", "c"}, skipped: 1, min: 200 * time.Millisecond},
+		// i=2; twice as fast.
+		{opts: Options{Speed: 2}, want: []string{"a", "b", "c"}, skipped: 1, min: 100 * time.Millisecond},
+		// i=3; rate limited to 20 packets per second.
+		{opts: Options{Rate: 20}, want: []string{"a", "b", "c"}, skipped: 1, min: 100 * time.Millisecond},
+		// i=4; replayed twice.
+		{opts: Options{Loop: 2}, want: []string{"a", "b", "c", "a", "b", "c"}, skipped: 2},
+		// i=5; filtered packets are not counted as skipped.
+		{opts: Options{Filter: filter.MustCompile("udp")}, want: []string{"a", "b", "c"}},
+		// i=6
+		{opts: Options{Filter: filter.MustCompile("udp port 80")}, want: nil},
+	}
+	filePath := writeFile(t, 100*time.Millisecond, "a", "b", "c")
+	for i, g := range golden {
+		l, conn := listen(t)
+		r := NewReplayer(conn, g.opts)
+		start := time.Now()
+		if err := r.ReplayFiles([]string{filePath}); err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		elapsed := time.Since(start)
+		got := receive(t, l, len(g.want))
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expected %q, got %q.", i, g.want, got)
+		}
+		if elapsed < g.min {
+			t.Errorf("i=%d: expected replay to take at least %v, took %v.", i, g.min, elapsed)
+		}
+		if r.Stats.Packets != len(g.want) || r.Stats.Skipped != g.skipped {
+			t.Errorf("i=%d: expected %d packets sent and %d skipped, got %+v.", i, len(g.want), g.skipped, r.Stats)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// Package stream implements reassembly of TCP streams from captured packets.
+//
+// Each TCP connection is identified by the addresses and ports of its two
+// endpoints, and is reassembled into one byte stream per direction.
+// Retransmitted and overlapping segments are deduplicated, out-of-order
+// segments are buffered until the missing data arrives, and sequence numbers
+// are compared using serial number arithmetic to handle wraparound.
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/mewmew/playground/archive/pcap"
+	"github.com/mewmew/playground/archive/pcap/layers"
+)
+
+// maxPending specifies the maximum number of out-of-order bytes buffered per
+// stream. When exceeded, the stream skips ahead to the first buffered segment
+// and the missing data is recorded as lost.
+const maxPending = 4 * 1024 * 1024
+
+// An Endpoint is the address and port of one side of a TCP connection.
+type Endpoint struct {
+	// IP address.
+	IP string
+	// TCP port.
+	Port uint16
+}
+
+func (e Endpoint) String() string {
+	return net.JoinHostPort(e.IP, strconv.Itoa(int(e.Port)))
+}
+
+// A Key identifies one direction of a TCP connection.
+type Key struct {
+	Src, Dst Endpoint
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%v-%v", k.Src, k.Dst)
+}
+
+// reverse returns the key of the opposite direction.
+func (k Key) reverse() Key {
+	return Key{Src: k.Dst, Dst: k.Src}
+}
+
+// A Conn is a reassembled TCP connection.
+type Conn struct {
+	// Client is the endpoint which initiated the connection; or the sender of
+	// the first captured packet if the handshake was not captured.
+	Client Endpoint
+	// Server is the endpoint which accepted the connection.
+	Server Endpoint
+	// Time of the first captured packet of the connection.
+	Start time.Time
+	// Time of the last captured packet of the connection.
+	End time.Time
+	// Data sent from the client to the server.
+	ClientToServer *Stream
+	// Data sent from the server to the client.
+	ServerToClient *Stream
+}
+
+// done reports whether the connection has been reset or closed in both
+// directions.
+func (c *Conn) done() bool {
+	if c.ClientToServer.Reset || c.ServerToClient.Reset {
+		return true
+	}
+	return c.ClientToServer.Closed && c.ServerToClient.Closed
+}
+
+// A Stream is the reassembled data of one direction of a TCP connection. The
+// reassembled data is read from the stream using Read.
+type Stream struct {
+	// Closed is set when a FIN has been received and all data preceding it has
+	// been reassembled.
+	Closed bool
+	// Reset is set when a RST has been received.
+	Reset bool
+	// Number of bytes which were never captured and are missing from the
+	// stream.
+	Lost int
+	// Reassembled data not yet read.
+	buf bytes.Buffer
+	// Whether the initial sequence number is known.
+	started bool
+	// Sequence number of the next expected byte.
+	next uint32
+	// Sequence number of the FIN, if received.
+	fin    uint32
+	hasFin bool
+	// Out-of-order segments.
+	pending []segment
+	// Total length of the out-of-order segments.
+	npending int
+}
+
+// A segment is an out-of-order TCP segment.
+type segment struct {
+	seq  uint32
+	data []byte
+}
+
+// Read reads reassembled data from the stream. It returns io.EOF once all
+// reassembled data has been read.
+func (s *Stream) Read(p []byte) (n int, err error) {
+	return s.buf.Read(p)
+}
+
+// Len returns the number of reassembled bytes not yet read.
+func (s *Stream) Len() int {
+	return s.buf.Len()
+}
+
+// diff returns the signed distance from b to a in sequence number space.
+func diff(a, b uint32) int32 {
+	return int32(a - b)
+}
+
+// add adds a TCP segment to the stream.
+func (s *Stream) add(tcp *layers.TCP) {
+	if tcp.RST() {
+		s.Reset = true
+		return
+	}
+	seq := tcp.Seq
+	if tcp.SYN() {
+		// The SYN occupies one sequence number. A retransmitted or duplicate
+		// SYN received after the stream has started is ignored, as resetting the
+		// next expected sequence number would duplicate reassembled data.
+		if !s.started {
+			s.started = true
+			s.next = seq + 1
+		}
+		seq++
+	} else if !s.started {
+		// The handshake was not captured; start at the first segment.
+		s.started = true
+		s.next = seq
+	}
+	if tcp.FIN() {
+		s.fin = seq + uint32(len(tcp.Payload))
+		s.hasFin = true
+	}
+	if len(tcp.Payload) > 0 {
+		s.insert(seq, tcp.Payload)
+	}
+	s.checkFin()
+}
+
+// insert inserts the data of a segment with the given sequence number into the
+// stream.
+func (s *Stream) insert(seq uint32, data []byte) {
+	if diff(seq, s.next) > 0 {
+		// Out-of-order segment; copy the data as the packet buffer may be reused.
+		s.pending = append(s.pending, segment{seq: seq, data: append([]byte(nil), data...)})
+		s.npending += len(data)
+		if s.npending > maxPending {
+			s.skip()
+		}
+		return
+	}
+	s.append(seq, data)
+	s.drain()
+}
+
+// append appends the data of a segment starting at or before the next expected
+// sequence number, skipping data which has already been reassembled.
+func (s *Stream) append(seq uint32, data []byte) {
+	overlap := int(-diff(seq, s.next))
+	if overlap >= len(data) {
+		// Retransmission of already reassembled data.
+		return
+	}
+	data = data[overlap:]
+	s.buf.Write(data)
+	s.next += uint32(len(data))
+}
+
+// drain appends the buffered out-of-order segments which have become
+// contiguous with the reassembled data.
+func (s *Stream) drain() {
+	for {
+		found := false
+		for i := 0; i < len(s.pending); i++ {
+			seg := s.pending[i]
+			if diff(seg.seq, s.next) > 0 {
+				continue
+			}
+			s.append(seg.seq, seg.data)
+			s.npending -= len(seg.data)
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			i--
+			found = true
+		}
+		if !found {
+			return
+		}
+	}
+}
+
+// skip skips ahead to the first buffered out-of-order segment, recording the
+// gap as lost data.
+func (s *Stream) skip() {
+	if len(s.pending) == 0 {
+		return
+	}
+	first := s.pending[0].seq
+	for _, seg := range s.pending[1:] {
+		if diff(seg.seq, first) < 0 {
+			first = seg.seq
+		}
+	}
+	s.Lost += int(diff(first, s.next))
+	s.next = first
+	s.drain()
+	s.checkFin()
+}
+
+// checkFin marks the stream as closed once all data preceding the FIN has been
+// reassembled.
+func (s *Stream) checkFin() {
+	if s.hasFin && !s.Closed && diff(s.next, s.fin) >= 0 {
+		s.Closed = true
+	}
+}
+
+// flush reassembles any remaining out-of-order segments, skipping over missing
+// data.
+func (s *Stream) flush() {
+	for len(s.pending) > 0 {
+		s.skip()
+	}
+}
+
+// An Assembler reassembles the TCP connections of captured packets.
+type Assembler struct {
+	// Open connections, indexed by the key of the client to server direction.
+	open map[Key]*Conn
+	// All connections in order of their first packet.
+	conns []*Conn
+}
+
+// NewAssembler returns a new Assembler.
+func NewAssembler() *Assembler {
+	return &Assembler{
+		open: make(map[Key]*Conn),
+	}
+}
+
+// AddPackage decodes the provided packet and adds it to its TCP connection.
+// Packets which are not TCP segments are ignored. Truncated packets are added
+// with the data that was captured, and the decode error is returned.
+func (a *Assembler) AddPackage(pkg *pcap.Package) error {
+	p, err := layers.DecodePackage(pkg)
+	a.Add(p, pkg.Time())
+	return err
+}
+
+// Add adds the decoded packet, captured at time ts, to its TCP connection.
+// Packets without TCP layer are ignored.
+func (a *Assembler) Add(p *layers.Packet, ts time.Time) {
+	if p.TCP == nil {
+		return
+	}
+	key := Key{
+		Src: Endpoint{IP: p.SrcIP().String(), Port: p.TCP.SrcPort},
+		Dst: Endpoint{IP: p.DstIP().String(), Port: p.TCP.DstPort},
+	}
+	var s *Stream
+	c, ok := a.open[key]
+	if ok {
+		s = c.ClientToServer
+	} else if c, ok = a.open[key.reverse()]; ok {
+		s = c.ServerToClient
+	}
+	// A SYN on a finished connection starts a new connection on the same
+	// endpoints.
+	if ok && c.done() && p.TCP.SYN() && !p.TCP.ACK() {
+		ok = false
+	}
+	if !ok {
+		// The sender of a SYN-ACK is the server.
+		if p.TCP.SYN() && p.TCP.ACK() {
+			key = key.reverse()
+		}
+		c = &Conn{
+			Client:         key.Src,
+			Server:         key.Dst,
+			Start:          ts,
+			ClientToServer: new(Stream),
+			ServerToClient: new(Stream),
+		}
+		delete(a.open, key.reverse())
+		a.open[key] = c
+		a.conns = append(a.conns, c)
+		if p.TCP.SYN() && p.TCP.ACK() {
+			s = c.ServerToClient
+		} else {
+			s = c.ClientToServer
+		}
+	}
+	c.End = ts
+	s.add(p.TCP)
+}
+
+// Flush reassembles the remaining out-of-order segments of every connection,
+// skipping over missing data, and returns all connections in order of their
+// first packet.
+func (a *Assembler) Flush() []*Conn {
+	for _, c := range a.conns {
+		c.ClientToServer.flush()
+		c.ServerToClient.flush()
+	}
+	return a.conns
+}
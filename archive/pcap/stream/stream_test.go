@@ -0,0 +1,185 @@
+package stream
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mewmew/playground/archive/pcap/layers"
+)
+
+var (
+	client = net.IP{10, 0, 0, 1}
+	server = net.IP{10, 0, 0, 2}
+)
+
+// seg describes a captured TCP segment.
+type seg struct {
+	// Sent by the client if set, and by the server otherwise.
+	fromClient bool
+	seq, ack   uint32
+	flags      uint8
+	data       string
+}
+
+// packet returns the decoded packet of the provided segment.
+func (s seg) packet() *layers.Packet {
+	src, dst := client, server
+	sport, dport := uint16(49152), uint16(80)
+	if !s.fromClient {
+		src, dst = dst, src
+		sport, dport = dport, sport
+	}
+	return &layers.Packet{
+		IPv4: &layers.IPv4{Src: src, Dst: dst, Protocol: layers.IPProtoTCP},
+		TCP: &layers.TCP{
+			SrcPort: sport,
+			DstPort: dport,
+			Seq:     s.seq,
+			Ack:     s.ack,
+			Flags:   s.flags,
+			Payload: []byte(s.data),
+		},
+	}
+}
+
+const (
+	syn    = layers.TCPFlagSYN
+	ack    = layers.TCPFlagACK
+	fin    = layers.TCPFlagFIN
+	rst    = layers.TCPFlagRST
+	synack = syn | ack
+	finack = fin | ack
+	psh    = layers.TCPFlagPSH | ack
+)
+
+type testConn struct {
+	c2s, s2c      string
+	closed, reset bool
+	lost          int
+}
+
+var golden = []struct {
+	segs  []seg
+	conns []testConn
+}{
+	// i=0: handshake, out-of-order segments, retransmissions and FIN.
+	{
+		segs: []seg{
+			{true, 100, 0, syn, ""},
+			{false, 500, 101, synack, ""},
+			{true, 101, 501, ack, ""},
+			{true, 101, 501, psh, "GET "},
+			{true, 109, 501, psh, "HTTP"},
+			{true, 105, 501, psh, "/foo"},
+			{true, 101, 501, psh, "GET /f"},
+			{false, 501, 113, psh, "200 OK"},
+			{false, 507, 113, finack, " bye"},
+			{true, 113, 512, finack, ""},
+			{false, 501, 113, psh, "200"},
+		},
+		conns: []testConn{
+			{c2s: "GET /fooHTTP", s2c: "200 OK bye", closed: true},
+		},
+	},
+	// i=1: sequence number wraparound.
+	{
+		segs: []seg{
+			{true, 0xFFFFFFF8, 0, syn, ""},
+			{false, 7, 0xFFFFFFF9, synack, ""},
+			{true, 0x00000000, 8, psh, "world"},
+			{true, 0xFFFFFFF9, 8, psh, "hello, "},
+			{true, 0x00000005, 8, finack, "!"},
+			{false, 8, 8, finack, ""},
+		},
+		conns: []testConn{
+			{c2s: "hello, world!", closed: true},
+		},
+	},
+	// i=2: handshake not captured, SYN-ACK seen first.
+	{
+		segs: []seg{
+			{false, 500, 101, synack, ""},
+			{false, 501, 101, psh, "banner"},
+			{true, 101, 507, psh, "HELO"},
+		},
+		conns: []testConn{
+			{c2s: "HELO", s2c: "banner"},
+		},
+	},
+	// i=3: reset and reuse of the same endpoints.
+	{
+		segs: []seg{
+			{true, 100, 0, syn, ""},
+			{true, 101, 0, psh, "one"},
+			{false, 0, 0, rst, ""},
+			{true, 900, 0, syn, ""},
+			{true, 901, 0, psh, "two"},
+		},
+		conns: []testConn{
+			{c2s: "one", reset: true},
+			{c2s: "two"},
+		},
+	},
+	// i=4: missing data.
+	{
+		segs: []seg{
+			{true, 100, 0, syn, ""},
+			{true, 101, 0, psh, "abc"},
+			{true, 110, 0, psh, "xyz"},
+		},
+		conns: []testConn{
+			{c2s: "abcxyz", lost: 6},
+		},
+	},
+	// i=5: retransmitted SYN after data has been reassembled.
+	{
+		segs: []seg{
+			{true, 100, 0, syn, ""},
+			{true, 101, 0, psh, "abc"},
+			{true, 100, 0, syn, ""},
+			{true, 101, 0, psh, "abc"},
+			{true, 104, 0, psh, "def"},
+		},
+		conns: []testConn{
+			{c2s: "abcdef"},
+		},
+	},
+}
+
+func TestAssembler(t *testing.T) {
+	for i, g := range golden {
+		a := NewAssembler()
+		ts := time.Unix(0, 0)
+		for _, s := range g.segs {
+			a.Add(s.packet(), ts)
+			ts = ts.Add(time.Millisecond)
+		}
+		conns := a.Flush()
+		if len(conns) != len(g.conns) {
+			t.Errorf("i=%d: expected %d connections, got %d.", i, len(g.conns), len(conns))
+			continue
+		}
+		for j, want := range g.conns {
+			c := conns[j]
+			if c.Client.IP != client.String() || c.Client.Port != 49152 {
+				t.Errorf("i=%d, j=%d: expected client %v:49152, got %v.", i, j, client, c.Client)
+			}
+			c2s, _ := ioutil.ReadAll(c.ClientToServer)
+			s2c, _ := ioutil.ReadAll(c.ServerToClient)
+			if string(c2s) != want.c2s || string(s2c) != want.s2c {
+				t.Errorf("i=%d, j=%d: expected %q/%q, got %q/%q.", i, j, want.c2s, want.s2c, c2s, s2c)
+			}
+			if c.ClientToServer.Closed != want.closed || c.ServerToClient.Closed != want.closed {
+				t.Errorf("i=%d, j=%d: expected closed %v, got %v/%v.", i, j, want.closed, c.ClientToServer.Closed, c.ServerToClient.Closed)
+			}
+			if reset := c.ClientToServer.Reset || c.ServerToClient.Reset; reset != want.reset {
+				t.Errorf("i=%d, j=%d: expected reset %v, got %v.", i, j, want.reset, reset)
+			}
+			if c.ClientToServer.Lost != want.lost {
+				t.Errorf("i=%d, j=%d: expected %d lost bytes, got %d.", i, j, want.lost, c.ClientToServer.Lost)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package pcap
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// Data link types of the network field in the file header.
+const (
+	LinkTypeNull     = 0
+	LinkTypeEthernet = 1
+	LinkTypeRaw      = 101
+	LinkTypeLinuxSLL = 113
+	LinkTypeIPv4     = 228
+	LinkTypeIPv6     = 229
+)
+
+// A Writer writes packets to a pcap stream. The stream is encoded using little
+// endian byte order and microsecond timestamp resolution.
+type Writer struct {
+	// File header of the pcap stream.
+	hdr FileHeader
+	// Underlying writer.
+	w io.Writer
+}
+
+// NewWriter returns a new Writer writing to w, and writes the file header of
+// the pcap stream. Packets longer than snapLen bytes are truncated, and
+// linkType specifies the data link type of the packets.
+func NewWriter(w io.Writer, snapLen, linkType uint32) (pw *Writer, err error) {
+	pw = &Writer{
+		hdr: FileHeader{
+			Magic:    MagicLittleEndian,
+			MajorVer: 2,
+			MinorVer: 4,
+			SnapLen:  snapLen,
+			Network:  linkType,
+		},
+		w: w,
+	}
+	err = binary.Write(w, binary.LittleEndian, pw.hdr)
+	if err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+// Header returns the file header of the pcap stream.
+func (pw *Writer) Header() FileHeader {
+	return pw.hdr
+}
+
+// WritePackage writes a packet with the provided timestamp and content to the
+// pcap stream. origLen specifies the length of the packet on the wire; it is
+// increased to len(data) if smaller. The stored content is truncated to the
+// snapshot length of the stream.
+func (pw *Writer) WritePackage(ts time.Time, data []byte, origLen int) error {
+	if origLen < len(data) {
+		origLen = len(data)
+	}
+	if uint32(len(data)) > pw.hdr.SnapLen {
+		data = data[:pw.hdr.SnapLen]
+	}
+	hdr := PackageHeader{
+		Sec:     uint32(ts.Unix()),
+		Usec:    uint32(ts.Nanosecond() / 1000),
+		Len:     uint32(len(data)),
+		OrigLen: uint32(origLen),
+	}
+	err := binary.Write(pw.w, binary.LittleEndian, hdr)
+	if err != nil {
+		return err
+	}
+	_, err = pw.w.Write(data)
+	return err
+}
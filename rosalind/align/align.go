@@ -0,0 +1,259 @@
+// Package align implements pairwise sequence alignment of nucleotide and
+// protein sequences.
+//
+// Global alignment uses the Needleman-Wunsch algorithm and local alignment the
+// Smith-Waterman algorithm, both with the affine gap penalties of Gotoh; a gap
+// of length k is penalized by GapOpen + (k-1)*GapExtend. Linear gap penalties
+// are obtained by setting GapOpen equal to GapExtend, and constant gap
+// penalties by setting GapExtend to zero.
+//
+// Global and Local use O(mn) space for sequences of length m and n. Hirschberg
+// computes the same global alignments in O(m+n) space using the divide and
+// conquer algorithm of Hirschberg, as extended to affine gaps by Myers and
+// Miller.
+package align
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Scoring specifies how alignments are scored.
+type Scoring struct {
+	// Substitution matrix.
+	Matrix Matrix
+	// Penalty of the first position of a gap.
+	GapOpen int
+	// Penalty of each subsequent position of a gap.
+	GapExtend int
+}
+
+// gap returns the open and extension penalties of the scoring scheme, such
+// that a gap of length k is penalized by open + k*extend.
+func (s Scoring) gap() (open, extend int) {
+	return s.GapOpen - s.GapExtend, s.GapExtend
+}
+
+// An Alignment is a pairwise alignment of the sequences a and b.
+type Alignment struct {
+	// Alignment score.
+	Score int
+	// Aligned sequences, with '-' for gaps; the aligned regions of the
+	// sequences for local alignments.
+	A, B string
+	// Aligned regions of the sequences; a[AStart:AEnd] and b[BStart:BEnd]. The
+	// regions span the entire sequences for global alignments.
+	AStart, AEnd int
+	BStart, BEnd int
+	// CIGAR string of the alignment, with a as reference; 'M' for aligned
+	// residues, 'I' for residues of b aligned to gaps and 'D' for residues of a
+	// aligned to gaps.
+	CIGAR string
+}
+
+// Alignment operations.
+const (
+	opMatch  = 'M'
+	opInsert = 'I'
+	opDelete = 'D'
+)
+
+// newAlignment returns the alignment of a[aStart:] and b[bStart:] described by
+// the provided operations.
+func newAlignment(a, b []byte, aStart, bStart int, ops []byte, score int) *Alignment {
+	aln := &Alignment{Score: score, AStart: aStart, BStart: bStart}
+	var bufA, bufB, cigar bytes.Buffer
+	i, j := aStart, bStart
+	for k, op := range ops {
+		switch op {
+		case opMatch:
+			bufA.WriteByte(a[i])
+			bufB.WriteByte(b[j])
+			i++
+			j++
+		case opInsert:
+			bufA.WriteByte('-')
+			bufB.WriteByte(b[j])
+			j++
+		case opDelete:
+			bufA.WriteByte(a[i])
+			bufB.WriteByte('-')
+			i++
+		}
+		if k == len(ops)-1 || ops[k+1] != op {
+			n := 1
+			for n <= k && ops[k-n] == op {
+				n++
+			}
+			cigar.WriteString(strconv.Itoa(n))
+			cigar.WriteByte(op)
+		}
+	}
+	aln.A, aln.B = bufA.String(), bufB.String()
+	aln.AEnd, aln.BEnd = i, j
+	aln.CIGAR = cigar.String()
+	return aln
+}
+
+// negInf is used in place of minus infinity for unreachable states; it is small
+// enough not to overflow when penalties are subtracted.
+const negInf = -1 << 30
+
+// max returns the largest of the provided integers.
+func max(x int, ys ...int) int {
+	for _, y := range ys {
+		if y > x {
+			x = y
+		}
+	}
+	return x
+}
+
+// A dpMatrix holds the dynamic programming matrices of Gotoh's algorithm for
+// sequences of length m and n, each stored in row-major order.
+type dpMatrix struct {
+	n int
+	// Best score of alignments of a[:i] and b[:j] ending in any state.
+	h []int
+	// Best score of alignments of a[:i] and b[:j] ending with an insertion.
+	e []int
+	// Best score of alignments of a[:i] and b[:j] ending with a deletion.
+	f []int
+}
+
+// newDPMatrix returns new dynamic programming matrices for sequences of length
+// m and n.
+func newDPMatrix(m, n int) *dpMatrix {
+	size := (m + 1) * (n + 1)
+	return &dpMatrix{n: n, h: make([]int, size), e: make([]int, size), f: make([]int, size)}
+}
+
+// at returns the index of cell (i, j).
+func (dp *dpMatrix) at(i, j int) int {
+	return i*(dp.n+1) + j
+}
+
+// Global returns an optimal global alignment of a and b, using the
+// Needleman-Wunsch algorithm with affine gap penalties.
+func Global(a, b []byte, s Scoring) *Alignment {
+	return align(a, b, s, false)
+}
+
+// Local returns an optimal local alignment of a and b, using the
+// Smith-Waterman algorithm with affine gap penalties. The empty alignment is
+// returned if no pair of residues scores positive.
+func Local(a, b []byte, s Scoring) *Alignment {
+	return align(a, b, s, true)
+}
+
+// align returns an optimal global or local alignment of a and b.
+func align(a, b []byte, s Scoring, local bool) *Alignment {
+	open, extend := s.gap()
+	sub := func(i, j int) int {
+		return s.Matrix.Score(a[i], b[j])
+	}
+	ops, score, i, j := gotoh(len(a), len(b), sub, open, extend, local)
+	return newAlignment(a, b, i, j, ops, score)
+}
+
+// gotoh returns the operations and score of an optimal global or local
+// alignment of two sequences of length m and n, using Gotoh's algorithm. The
+// score of aligning the ith residue of the first sequence with the jth residue
+// of the second is sub(i, j), and a gap of length k is penalized by open +
+// k*extend. The alignment starts at residue i and j of the sequences.
+func gotoh(m, n int, sub func(i, j int) int, open, extend int, local bool) (ops []byte, score, i, j int) {
+	dp := newDPMatrix(m, n)
+	for i := 0; i <= m; i++ {
+		for j := 0; j <= n; j++ {
+			k := dp.at(i, j)
+			switch {
+			case i == 0 && j == 0:
+				dp.h[k], dp.e[k], dp.f[k] = 0, negInf, negInf
+				continue
+			case local && (i == 0 || j == 0):
+				dp.h[k], dp.e[k], dp.f[k] = 0, negInf, negInf
+				continue
+			}
+			e, f := negInf, negInf
+			if j > 0 {
+				left := dp.at(i, j-1)
+				e = max(dp.e[left], dp.h[left]-open) - extend
+			}
+			if i > 0 {
+				up := dp.at(i-1, j)
+				f = max(dp.f[up], dp.h[up]-open) - extend
+			}
+			h := max(e, f)
+			if i > 0 && j > 0 {
+				h = max(h, dp.h[dp.at(i-1, j-1)]+sub(i-1, j-1))
+			}
+			if local {
+				h = max(h, 0)
+			}
+			dp.h[k], dp.e[k], dp.f[k] = h, e, f
+		}
+	}
+
+	// Locate the end of the alignment.
+	endI, endJ := m, n
+	if local {
+		best := 0
+		for i := 0; i <= m; i++ {
+			for j := 0; j <= n; j++ {
+				if h := dp.h[dp.at(i, j)]; h > best {
+					best, endI, endJ = h, i, j
+				}
+			}
+		}
+		if best == 0 {
+			endI, endJ = 0, 0
+		}
+	}
+	score = dp.h[dp.at(endI, endJ)]
+
+	// Trace back the alignment.
+	i, j = endI, endJ
+	state := byte(opMatch)
+	for i > 0 || j > 0 {
+		k := dp.at(i, j)
+		if state == opMatch {
+			if local && dp.h[k] == 0 {
+				break
+			}
+			switch {
+			case i > 0 && j > 0 && dp.h[k] == dp.h[dp.at(i-1, j-1)]+sub(i-1, j-1):
+				ops = append(ops, opMatch)
+				i--
+				j--
+				continue
+			case dp.h[k] == dp.e[k]:
+				state = opInsert
+			default:
+				state = opDelete
+			}
+		}
+		switch state {
+		case opInsert:
+			ops = append(ops, opInsert)
+			j--
+			if dp.e[k] != dp.e[dp.at(i, j)]-extend {
+				state = opMatch
+			}
+		case opDelete:
+			ops = append(ops, opDelete)
+			i--
+			if dp.f[k] != dp.f[dp.at(i, j)]-extend {
+				state = opMatch
+			}
+		}
+	}
+	reverse(ops)
+	return ops, score, i, j
+}
+
+// reverse reverses the slice in place.
+func reverse(s []byte) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,141 @@
+package align
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestAlign(t *testing.T) {
+	golden := []struct {
+		a, b  string
+		s     Scoring
+		local bool
+		score int
+	}{
+		// i=0
+		// Rosalind GLOB; BLOSUM62 with linear gap penalty 5.
+		{a: "PLEASANTLY", b: "MEANLY", s: Scoring{Matrix: BLOSUM62, GapOpen: 5, GapExtend: 5}, score: 8},
+		// i=1
+		// Rosalind GCON; BLOSUM62 with constant gap penalty 5.
+		{a: "PLEASANTLY", b: "MEANLY", s: Scoring{Matrix: BLOSUM62, GapOpen: 5, GapExtend: 0}, score: 13},
+		// i=2
+		// Rosalind GAFF; BLOSUM62 with gap open penalty 11 and extension penalty 1.
+		{a: "PRTEINS", b: "PRTWPSEIN", s: Scoring{Matrix: BLOSUM62, GapOpen: 11, GapExtend: 1}, score: 8},
+		// i=3
+		// Rosalind LOCA; PAM250 with linear gap penalty 5. Any optimal alignment
+		// is accepted, e.g. LYPRTEINSTRIN and LYEINSTEIN.
+		{a: "MEANLYPRTEINSTRING", b: "PLEASANTLYEINSTEIN", s: Scoring{Matrix: PAM250, GapOpen: 5, GapExtend: 5}, local: true, score: 23},
+		// i=4
+		{a: "GATTACA", b: "gattaca", s: Scoring{Matrix: Simple{Match: 1, Mismatch: -1}, GapOpen: 2, GapExtend: 2}, score: 7},
+		// i=5
+		{a: "AAAA", b: "TTTT", s: Scoring{Matrix: Simple{Match: 1, Mismatch: -1}, GapOpen: 2, GapExtend: 2}, local: true, score: 0},
+	}
+	for i, g := range golden {
+		var alns []*Alignment
+		if g.local {
+			alns = append(alns, Local([]byte(g.a), []byte(g.b), g.s))
+		} else {
+			alns = append(alns, Global([]byte(g.a), []byte(g.b), g.s))
+			alns = append(alns, Hirschberg([]byte(g.a), []byte(g.b), g.s))
+		}
+		for _, aln := range alns {
+			if aln.Score != g.score {
+				t.Errorf("i=%d: expected score %d, got %d.", i, g.score, aln.Score)
+			}
+			if got := rescore(aln.A, aln.B, g.s); got != aln.Score {
+				t.Errorf("i=%d: score %d does not match score %d of alignment %q/%q.", i, aln.Score, got, aln.A, aln.B)
+			}
+			if got := strings.Replace(aln.A, "-", "", -1); got != g.a[aln.AStart:aln.AEnd] {
+				t.Errorf("i=%d: aligned sequence %q does not match %q.", i, aln.A, g.a[aln.AStart:aln.AEnd])
+			}
+			if got := strings.Replace(aln.B, "-", "", -1); got != g.b[aln.BStart:aln.BEnd] {
+				t.Errorf("i=%d: aligned sequence %q does not match %q.", i, aln.B, g.b[aln.BStart:aln.BEnd])
+			}
+		}
+	}
+}
+
+func TestHirschberg(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	schemes := []Scoring{
+		{Matrix: Simple{Match: 2, Mismatch: -3}, GapOpen: 5, GapExtend: 2},
+		{Matrix: Simple{Match: 1, Mismatch: -1}, GapOpen: 1, GapExtend: 1},
+		{Matrix: BLOSUM62, GapOpen: 11, GapExtend: 1},
+		{Matrix: BLOSUM62, GapOpen: 5, GapExtend: 0},
+	}
+	for i := 0; i < 500; i++ {
+		s := schemes[i%len(schemes)]
+		alphabet := "ACGT"
+		if s.Matrix == BLOSUM62 {
+			alphabet = "ACDEFGHIKLMNPQRSTVWY"
+		}
+		a := randSeq(r, alphabet, r.Intn(40))
+		b := randSeq(r, alphabet, r.Intn(40))
+		want := Global(a, b, s)
+		got := Hirschberg(a, b, s)
+		if got.Score != want.Score {
+			t.Errorf("i=%d: %q/%q: expected score %d, got %d (%q/%q).", i, a, b, want.Score, got.Score, got.A, got.B)
+		}
+	}
+}
+
+func TestCIGAR(t *testing.T) {
+	s := Scoring{Matrix: BLOSUM62, GapOpen: 11, GapExtend: 1}
+	aln := Global([]byte("PRTEINS"), []byte("PRTWPSEIN"), s)
+	const want = "3M3I3M1D"
+	if aln.CIGAR != want {
+		t.Errorf("expected CIGAR %q, got %q (%q/%q).", want, aln.CIGAR, aln.A, aln.B)
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	const residues = "ARNDCQEGHILKMFPSTWYVBZX*"
+	for _, m := range []*Table{BLOSUM62, PAM250} {
+		for i := 0; i < len(residues); i++ {
+			for j := 0; j < len(residues); j++ {
+				a, b := residues[i], residues[j]
+				if m.Score(a, b) != m.Score(b, a) {
+					t.Errorf("%s: asymmetric scores of %c and %c; %d and %d.", m.Name, a, b, m.Score(a, b), m.Score(b, a))
+				}
+			}
+		}
+	}
+	if got := BLOSUM62.Score('w', 'W'); got != 11 {
+		t.Errorf("expected BLOSUM62 score 11 of w and W, got %d.", got)
+	}
+	if got := BLOSUM62.Score('J', 'A'); got != -4 {
+		t.Errorf("expected BLOSUM62 score -4 of unknown residue, got %d.", got)
+	}
+	if _, err := ParseMatrix("bad", strings.NewReader("  A C\nA 1\n")); err == nil {
+		t.Errorf("expected error for short row, got nil.")
+	}
+}
+
+// randSeq returns a random sequence of length n over the alphabet.
+func randSeq(r *rand.Rand, alphabet string, n int) []byte {
+	s := make([]byte, n)
+	for i := range s {
+		s[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return s
+}
+
+func BenchmarkGlobal(b *testing.B) {
+	benchmarkAlign(b, Global)
+}
+
+func BenchmarkHirschberg(b *testing.B) {
+	benchmarkAlign(b, Hirschberg)
+}
+
+func benchmarkAlign(b *testing.B, f func(a, b []byte, s Scoring) *Alignment) {
+	r := rand.New(rand.NewSource(1))
+	x := randSeq(r, "ACGT", 2000)
+	y := randSeq(r, "ACGT", 2000)
+	s := Scoring{Matrix: Simple{Match: 2, Mismatch: -3}, GapOpen: 5, GapExtend: 2}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f(x, y, s)
+	}
+}
@@ -0,0 +1,62 @@
+package align
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+func ExampleGlobal() {
+	s := Scoring{Matrix: BLOSUM62, GapOpen: 11, GapExtend: 1}
+	aln := Global([]byte("PRTEINS"), []byte("PRTWPSEIN"), s)
+	fmt.Println(aln.Score)
+	fmt.Println(aln.A)
+	fmt.Println(aln.B)
+	fmt.Println(aln.CIGAR)
+	// Output:
+	// 8
+	// PRT---EINS
+	// PRTWPSEIN-
+	// 3M3I3M1D
+}
+
+func ExampleLocal() {
+	s := Scoring{Matrix: Simple{Match: 2, Mismatch: -3}, GapOpen: 3, GapExtend: 1}
+	aln := Local([]byte("TTTTGATTACATTTT"), []byte("CCGATTTACACC"), s)
+	fmt.Println(aln.Score, aln.AStart, aln.AEnd, aln.BStart, aln.BEnd)
+	fmt.Println(aln.A)
+	fmt.Println(aln.B)
+	fmt.Println(aln.CIGAR)
+	// Output:
+	// 11 4 11 2 10
+	// GA-TTACA
+	// GATTTACA
+	// 2M1I5M
+}
+
+func ExampleProgressive() {
+	ids := []string{"a", "b", "c", "d"}
+	seqs := [][]byte{
+		[]byte("ATTGCCATT"),
+		[]byte("ATGGCCATT"),
+		[]byte("ATCCAATTTT"),
+		[]byte("ATCTTCTT"),
+	}
+	s := Scoring{Matrix: Simple{Match: 2, Mismatch: -3}, GapOpen: 5, GapExtend: 2}
+	msa, err := Progressive(ids, seqs, s)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := msa.WriteFASTA(os.Stdout); err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// >a
+	// ATTGCCA---TT
+	// >b
+	// ATGGCCA---TT
+	// >c
+	// AT--CCAATTTT
+	// >d
+	// AT--C--TTCTT
+}
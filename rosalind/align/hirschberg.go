@@ -0,0 +1,185 @@
+package align
+
+// Hirschberg returns an optimal global alignment of a and b, like Global, but
+// uses space linear in the length of the sequences rather than quadratic, at
+// roughly twice the running time.
+func Hirschberg(a, b []byte, s Scoring) *Alignment {
+	open, extend := s.gap()
+	n := len(b) + 1
+	h := &hirschberg{
+		s:      s,
+		open:   open,
+		extend: extend,
+		cc:     make([]int, n),
+		dd:     make([]int, n),
+		rr:     make([]int, n),
+		ss:     make([]int, n),
+	}
+	h.diff(a, b, open, open)
+	aln := newAlignment(a, b, 0, 0, h.ops, 0)
+	aln.Score = rescore(aln.A, aln.B, s)
+	return aln
+}
+
+// hirschberg holds the state of the linear space alignment algorithm of Myers
+// and Miller.
+type hirschberg struct {
+	// Scoring scheme.
+	s Scoring
+	// Gap penalties; a gap of length k is penalized by open + k*extend.
+	open, extend int
+	// Last rows of the forward pass; best score of alignments in any state,
+	// and ending with a deletion.
+	cc, dd []int
+	// Last rows of the reverse pass; best score of alignments in any state,
+	// and starting with a deletion.
+	rr, ss []int
+	// Alignment operations, in order.
+	ops []byte
+}
+
+// diff appends the operations of an optimal alignment of a and b, where the
+// open penalties of deletions adjoining the start and end of a are tb and te
+// respectively; deletions adjoining a gap of the enclosing alignment continue
+// that gap, and are not penalized for opening.
+func (h *hirschberg) diff(a, b []byte, tb, te int) {
+	m, n := len(a), len(b)
+	switch {
+	case n == 0:
+		h.emit(opDelete, m)
+		return
+	case m == 0:
+		h.emit(opInsert, n)
+		return
+	case m == 1:
+		// Either delete a[0] and insert b, or align a[0] with a residue of b
+		// and insert the remaining residues.
+		t := tb
+		if te < t {
+			t = te
+		}
+		best := -(t + h.extend) - h.gap(n)
+		bestJ := -1
+		for j := 0; j < n; j++ {
+			score := h.s.Matrix.Score(a[0], b[j]) - h.gap(j) - h.gap(n-1-j)
+			if score > best {
+				best, bestJ = score, j
+			}
+		}
+		switch {
+		case bestJ != -1:
+			h.emit(opInsert, bestJ)
+			h.emit(opMatch, 1)
+			h.emit(opInsert, n-1-bestJ)
+		case tb <= te:
+			h.emit(opDelete, 1)
+			h.emit(opInsert, n)
+		default:
+			h.emit(opInsert, n)
+			h.emit(opDelete, 1)
+		}
+		return
+	}
+
+	// Locate the column j at which an optimal alignment crosses the middle row,
+	// and whether it does so in a deletion which spans the middle row.
+	mid := m / 2
+	h.pass(a[:mid], b, tb, false, h.cc, h.dd)
+	h.pass(a[mid:], b, te, true, h.rr, h.ss)
+	best, bestJ, spans := negInf, 0, false
+	for j := 0; j <= n; j++ {
+		if score := h.cc[j] + h.rr[n-j]; score > best {
+			best, bestJ, spans = score, j, false
+		}
+		// Joining two deletions saves one open penalty.
+		if score := h.dd[j] + h.ss[n-j] + h.open; score > best {
+			best, bestJ, spans = score, j, true
+		}
+	}
+	if !spans {
+		h.diff(a[:mid], b[:bestJ], tb, h.open)
+		h.diff(a[mid:], b[bestJ:], h.open, te)
+		return
+	}
+	h.diff(a[:mid-1], b[:bestJ], tb, 0)
+	h.emit(opDelete, 2)
+	h.diff(a[mid+1:], b[bestJ:], 0, te)
+}
+
+// pass computes the last row of Gotoh's algorithm for the alignment of a and b
+// in linear space, storing the best score of alignments in any state in cc and
+// of alignments ending with a deletion in dd; cc[j] and dd[j] hold the scores
+// of alignments with the first j residues of b. If reverse is set, the
+// sequences are aligned from their ends, and cc[j] and dd[j] hold the scores of
+// alignments with the last j residues of b. The open penalty of deletions
+// adjoining the start of the alignment is t.
+func (h *hirschberg) pass(a, b []byte, t int, reverse bool, cc, dd []int) {
+	m, n := len(a), len(b)
+	cc[0], dd[0] = 0, -t
+	for j := 1; j <= n; j++ {
+		cc[j] = -h.gap(j)
+		dd[j] = cc[j] - h.open
+	}
+	for i := 1; i <= m; i++ {
+		ai := a[i-1]
+		if reverse {
+			ai = a[m-i]
+		}
+		diag := cc[0]
+		dd[0] = max(dd[0], cc[0]-h.open) - h.extend
+		cc[0] = dd[0]
+		e := negInf
+		for j := 1; j <= n; j++ {
+			bj := b[j-1]
+			if reverse {
+				bj = b[n-j]
+			}
+			e = max(e, cc[j-1]-h.open) - h.extend
+			dd[j] = max(dd[j], cc[j]-h.open) - h.extend
+			c := max(dd[j], e, diag+h.s.Matrix.Score(ai, bj))
+			diag = cc[j]
+			cc[j] = c
+		}
+	}
+}
+
+// gap returns the penalty of a gap of length k.
+func (h *hirschberg) gap(k int) int {
+	if k == 0 {
+		return 0
+	}
+	return h.open + h.extend*k
+}
+
+// emit appends n operations of the provided kind.
+func (h *hirschberg) emit(op byte, n int) {
+	for i := 0; i < n; i++ {
+		h.ops = append(h.ops, op)
+	}
+}
+
+// rescore returns the score of the aligned sequences.
+func rescore(a, b string, s Scoring) int {
+	score := 0
+	var prev byte
+	for i := 0; i < len(a); i++ {
+		var op byte
+		switch {
+		case a[i] == '-':
+			op = opInsert
+		case b[i] == '-':
+			op = opDelete
+		default:
+			score += s.Matrix.Score(a[i], b[i])
+		}
+		switch {
+		case op == 0:
+		case op == prev:
+			score -= s.GapExtend
+		default:
+			score -= s.GapOpen
+		}
+		prev = op
+	}
+	return score
+}
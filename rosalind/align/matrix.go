@@ -0,0 +1,221 @@
+package align
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A Matrix scores the alignment of two residues; positive scores favour the
+// substitution of one residue for the other.
+type Matrix interface {
+	// Score returns the score of aligning residue a with residue b.
+	Score(a, b byte) int
+}
+
+// A Table is a substitution matrix which scores residue pairs by table lookup,
+// regardless of case. Residue pairs not present in the table score as the
+// lowest score of the table, or 0 if no score is negative.
+type Table struct {
+	// Name of the substitution matrix.
+	Name string
+	// Scores indexed by residue pair.
+	scores [256][256]int
+}
+
+// Score returns the score of aligning residue a with residue b.
+func (t *Table) Score(a, b byte) int {
+	return t.scores[a][b]
+}
+
+// ParseMatrix parses a substitution matrix in the NCBI text format; a header
+// line of residues followed by one line per residue, starting with the residue
+// and followed by its scores. Lines starting with '#' are ignored.
+func ParseMatrix(name string, r io.Reader) (*Table, error) {
+	t := &Table{Name: name}
+	var cols []byte
+	var rows []byte
+	var scores [][]int
+	s := bufio.NewScanner(r)
+	line := 0
+	for s.Scan() {
+		line++
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if cols == nil {
+			for _, field := range fields {
+				if len(field) != 1 {
+					return nil, fmt.Errorf("align.ParseMatrix: line %d: invalid residue %q", line, field)
+				}
+				cols = append(cols, field[0])
+			}
+			continue
+		}
+		if len(fields[0]) != 1 {
+			return nil, fmt.Errorf("align.ParseMatrix: line %d: invalid residue %q", line, fields[0])
+		}
+		if len(fields)-1 != len(cols) {
+			return nil, fmt.Errorf("align.ParseMatrix: line %d: expected %d scores, got %d", line, len(cols), len(fields)-1)
+		}
+		row := make([]int, len(cols))
+		for i, field := range fields[1:] {
+			score, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("align.ParseMatrix: line %d: %v", line, err)
+			}
+			row[i] = score
+		}
+		rows = append(rows, fields[0][0])
+		scores = append(scores, row)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if cols == nil {
+		return nil, fmt.Errorf("align.ParseMatrix: missing header line")
+	}
+
+	// Residue pairs not present in the table score as the lowest score of the
+	// table.
+	min := 0
+	for _, row := range scores {
+		for _, score := range row {
+			if score < min {
+				min = score
+			}
+		}
+	}
+	for a := range t.scores {
+		for b := range t.scores[a] {
+			t.scores[a][b] = min
+		}
+	}
+	for i, a := range rows {
+		for j, b := range cols {
+			t.set(a, b, scores[i][j])
+		}
+	}
+	return t, nil
+}
+
+// set sets the score of the residue pair, for all combinations of case.
+func (t *Table) set(a, b byte, score int) {
+	for _, x := range cases(a) {
+		for _, y := range cases(b) {
+			t.scores[x][y] = score
+		}
+	}
+}
+
+// cases returns the uppercase and lowercase forms of the ASCII letter c, or c
+// if it is not a letter.
+func cases(c byte) []byte {
+	switch {
+	case 'A' <= c && c <= 'Z':
+		return []byte{c, c + 'a' - 'A'}
+	case 'a' <= c && c <= 'z':
+		return []byte{c - ('a' - 'A'), c}
+	}
+	return []byte{c}
+}
+
+// Simple is a substitution matrix for nucleotides, which scores identical
+// residues as Match and others as Mismatch, regardless of case.
+type Simple struct {
+	// Match score; typically positive.
+	Match int
+	// Mismatch score; typically negative.
+	Mismatch int
+}
+
+// Score returns the score of aligning residue a with residue b.
+func (m Simple) Score(a, b byte) int {
+	if a == b || a^b == 'a'-'A' && isLetter(a) {
+		return m.Match
+	}
+	return m.Mismatch
+}
+
+// isLetter reports whether c is an ASCII letter.
+func isLetter(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z'
+}
+
+// Built-in substitution matrices for proteins.
+var (
+	// BLOSUM62 is the BLOSUM62 matrix of Henikoff and Henikoff.
+	BLOSUM62 = mustParseMatrix("BLOSUM62", blosum62)
+	// PAM250 is the PAM250 matrix of Dayhoff et al.
+	PAM250 = mustParseMatrix("PAM250", pam250)
+)
+
+// mustParseMatrix parses the built-in substitution matrix and panics on error.
+func mustParseMatrix(name, s string) *Table {
+	t, err := ParseMatrix(name, strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+const blosum62 = `
+#  Matrix made by matblas from blosum62.iij
+   A  R  N  D  C  Q  E  G  H  I  L  K  M  F  P  S  T  W  Y  V  B  Z  X  *
+A  4 -1 -2 -2  0 -1 -1  0 -2 -1 -1 -1 -1 -2 -1  1  0 -3 -2  0 -2 -1  0 -4
+R -1  5  0 -2 -3  1  0 -2  0 -3 -2  2 -1 -3 -2 -1 -1 -3 -2 -3 -1  0 -1 -4
+N -2  0  6  1 -3  0  0  0  1 -3 -3  0 -2 -3 -2  1  0 -4 -2 -3  3  0 -1 -4
+D -2 -2  1  6 -3  0  2 -1 -1 -3 -4 -1 -3 -3 -1  0 -1 -4 -3 -3  4  1 -1 -4
+C  0 -3 -3 -3  9 -3 -4 -3 -3 -1 -1 -3 -1 -2 -3 -1 -1 -2 -2 -1 -3 -3 -2 -4
+Q -1  1  0  0 -3  5  2 -2  0 -3 -2  1  0 -3 -1  0 -1 -2 -1 -2  0  3 -1 -4
+E -1  0  0  2 -4  2  5 -2  0 -3 -3  1 -2 -3 -1  0 -1 -3 -2 -2  1  4 -1 -4
+G  0 -2  0 -1 -3 -2 -2  6 -2 -4 -4 -2 -3 -3 -2  0 -2 -2 -3 -3 -1 -2 -1 -4
+H -2  0  1 -1 -3  0  0 -2  8 -3 -3 -1 -2 -1 -2 -1 -2 -2  2 -3  0  0 -1 -4
+I -1 -3 -3 -3 -1 -3 -3 -4 -3  4  2 -3  1  0 -3 -2 -1 -3 -1  3 -3 -3 -1 -4
+L -1 -2 -3 -4 -1 -2 -3 -4 -3  2  4 -2  2  0 -3 -2 -1 -2 -1  1 -4 -3 -1 -4
+K -1  2  0 -1 -3  1  1 -2 -1 -3 -2  5 -1 -3 -1  0 -1 -3 -2 -2  0  1 -1 -4
+M -1 -1 -2 -3 -1  0 -2 -3 -2  1  2 -1  5  0 -2 -1 -1 -1 -1  1 -3 -1 -1 -4
+F -2 -3 -3 -3 -2 -3 -3 -3 -1  0  0 -3  0  6 -4 -2 -2  1  3 -1 -3 -3 -1 -4
+P -1 -2 -2 -1 -3 -1 -1 -2 -2 -3 -3 -1 -2 -4  7 -1 -1 -4 -3 -2 -2 -1 -2 -4
+S  1 -1  1  0 -1  0  0  0 -1 -2 -2  0 -1 -2 -1  4  1 -3 -2 -2  0  0  0 -4
+T  0 -1  0 -1 -1 -1 -1 -2 -2 -1 -1 -1 -1 -2 -1  1  5 -2 -2  0 -1 -1  0 -4
+W -3 -3 -4 -4 -2 -2 -3 -2 -2 -3 -2 -3 -1  1 -4 -3 -2 11  2 -3 -4 -3 -2 -4
+Y -2 -2 -2 -3 -2 -1 -2 -3  2 -1 -1 -2 -1  3 -3 -2 -2  2  7 -1 -3 -2 -1 -4
+V  0 -3 -3 -3 -1 -2 -2 -3 -3  3  1 -2  1 -1 -2 -2  0 -3 -1  4 -3 -2 -1 -4
+B -2 -1  3  4 -3  0  1 -1  0 -3 -4  0 -3 -3 -2  0 -1 -4 -3 -3  4  1 -1 -4
+Z -1  0  0  1 -3  3  4 -2  0 -3 -3  1 -1 -3 -1  0 -1 -3 -2 -2  1  4 -1 -4
+X  0 -1 -1 -1 -2 -1 -1 -1 -1 -1 -1 -1 -1 -1 -2  0  0 -2 -1 -1 -1 -1 -1 -4
+* -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4  1
+`
+
+const pam250 = `
+#  PAM 250 substitution matrix
+   A  R  N  D  C  Q  E  G  H  I  L  K  M  F  P  S  T  W  Y  V  B  Z  X  *
+A  2 -2  0  0 -2  0  0  1 -1 -1 -2 -1 -1 -3  1  1  1 -6 -3  0  0  0  0 -8
+R -2  6  0 -1 -4  1 -1 -3  2 -2 -3  3  0 -4  0  0 -1  2 -4 -2 -1  0 -1 -8
+N  0  0  2  2 -4  1  1  0  2 -2 -3  1 -2 -3  0  1  0 -4 -2 -2  2  1  0 -8
+D  0 -1  2  4 -5  2  3  1  1 -2 -4  0 -3 -6 -1  0  0 -7 -4 -2  3  3 -1 -8
+C -2 -4 -4 -5 12 -5 -5 -3 -3 -2 -6 -5 -5 -4 -3  0 -2 -8  0 -2 -4 -5 -3 -8
+Q  0  1  1  2 -5  4  2 -1  3 -2 -2  1 -1 -5  0 -1 -1 -5 -4 -2  1  3 -1 -8
+E  0 -1  1  3 -5  2  4  0  1 -2 -3  0 -2 -5 -1  0  0 -7 -4 -2  3  3 -1 -8
+G  1 -3  0  1 -3 -1  0  5 -2 -3 -4 -2 -3 -5  0  1  0 -7 -5 -1  0  0 -1 -8
+H -1  2  2  1 -3  3  1 -2  6 -2 -2  0 -2 -2  0 -1 -1 -3  0 -2  1  2 -1 -8
+I -1 -2 -2 -2 -2 -2 -2 -3 -2  5  2 -2  2  1 -2 -1  0 -5 -1  4 -2 -2 -1 -8
+L -2 -3 -3 -4 -6 -2 -3 -4 -2  2  6 -3  4  2 -3 -3 -2 -2 -1  2 -3 -3 -1 -8
+K -1  3  1  0 -5  1  0 -2  0 -2 -3  5  0 -5 -1  0  0 -3 -4 -2  1  0 -1 -8
+M -1  0 -2 -3 -5 -1 -2 -3 -2  2  4  0  6  0 -2 -2 -1 -4 -2  2 -2 -2 -1 -8
+F -3 -4 -3 -6 -4 -5 -5 -5 -2  1  2 -5  0  9 -5 -3 -3  0  7 -1 -4 -5 -2 -8
+P  1  0  0 -1 -3  0 -1  0  0 -2 -3 -1 -2 -5  6  1  0 -6 -5 -1 -1  0 -1 -8
+S  1  0  1  0  0 -1  0  1 -1 -1 -3  0 -2 -3  1  2  1 -2 -3 -1  0  0  0 -8
+T  1 -1  0  0 -2 -1  0  0 -1  0 -2  0 -1 -3  0  1  3 -5 -3  0  0 -1  0 -8
+W -6  2 -4 -7 -8 -5 -7 -7 -3 -5 -2 -3 -4  0 -6 -2 -5 17  0 -6 -5 -6 -4 -8
+Y -3 -4 -2 -4  0 -4 -4 -5  0 -1 -1 -4 -2  7 -5 -3 -3  0 10 -2 -3 -4 -2 -8
+V  0 -2 -2 -2 -2 -2 -2 -1 -2  4  2 -2  2 -1 -1 -1  0 -6 -2  4 -2 -2 -1 -8
+B  0 -1  2  3 -4  1  3  0  1 -2 -3  1 -2 -4 -1  0  0 -5 -3 -2  3  2 -1 -8
+Z  0  0  1  3 -5  3  3  0  2 -2 -3  0 -2 -5  0  0 -1 -6 -4 -2  2  3 -1 -8
+X  0 -1  0 -1 -3 -1 -1 -1 -1 -1 -1 -1 -1 -2 -1  0  0 -4 -2 -1 -1 -1 -1 -8
+* -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8  1
+`
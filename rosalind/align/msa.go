@@ -0,0 +1,259 @@
+package align
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+// An MSA is a multiple sequence alignment.
+type MSA struct {
+	// IDs of the sequences.
+	IDs []string
+	// Aligned sequences, with '-' for gaps; all of the same length.
+	Seqs [][]byte
+}
+
+// Progressive returns a multiple sequence alignment of the sequences, using
+// progressive alignment. A guide tree is constructed by UPGMA clustering of the
+// pairwise distances of the sequences, which is the fraction of mismatches in
+// their global alignment. The sequences are then aligned in the order of the
+// guide tree, by profile-profile alignment of the clusters which are joined.
+func Progressive(ids []string, seqs [][]byte, s Scoring) (*MSA, error) {
+	if len(seqs) == 0 {
+		return nil, errors.New("align.Progressive: no sequences provided")
+	}
+	if len(ids) != len(seqs) {
+		return nil, fmt.Errorf("align.Progressive: number of IDs (%d) differs from number of sequences (%d)", len(ids), len(seqs))
+	}
+	msa := &MSA{IDs: ids, Seqs: make([][]byte, len(seqs))}
+	for i, seq := range seqs {
+		msa.Seqs[i] = append([]byte(nil), seq...)
+	}
+
+	// Pairwise distances of the sequences.
+	n := len(seqs)
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d := distance(Global(seqs[i], seqs[j], s))
+			dist[i][j], dist[j][i] = d, d
+		}
+	}
+
+	// Join the closest pair of clusters until a single cluster remains, using
+	// the UPGMA distance between clusters; the mean distance between their
+	// sequences.
+	var clusters [][]int
+	for i := 0; i < n; i++ {
+		clusters = append(clusters, []int{i})
+	}
+	for len(clusters) > 1 {
+		bestI, bestJ, best := 0, 1, math.Inf(1)
+		for i := range clusters {
+			for j := i + 1; j < len(clusters); j++ {
+				if d := dist[i][j]; d < best {
+					bestI, bestJ, best = i, j, d
+				}
+			}
+		}
+		x, y := clusters[bestI], clusters[bestJ]
+		msa.alignProfiles(x, y, s)
+		// Replace cluster i with the joined cluster, and remove cluster j.
+		for k := range clusters {
+			if k == bestI || k == bestJ {
+				continue
+			}
+			d := (dist[bestI][k]*float64(len(x)) + dist[bestJ][k]*float64(len(y))) / float64(len(x)+len(y))
+			dist[bestI][k], dist[k][bestI] = d, d
+		}
+		clusters[bestI] = append(x, y...)
+		clusters = append(clusters[:bestJ], clusters[bestJ+1:]...)
+		dist = append(dist[:bestJ], dist[bestJ+1:]...)
+		for k := range dist {
+			dist[k] = append(dist[k][:bestJ], dist[k][bestJ+1:]...)
+		}
+	}
+	return msa, nil
+}
+
+// distance returns the fraction of mismatches among the aligned residues of the
+// pairwise alignment, or 1 if no residues are aligned.
+func distance(aln *Alignment) float64 {
+	aligned, mismatches := 0, 0
+	for i := 0; i < len(aln.A); i++ {
+		a, b := aln.A[i], aln.B[i]
+		if a == '-' || b == '-' {
+			continue
+		}
+		aligned++
+		if upperCase(a) != upperCase(b) {
+			mismatches++
+		}
+	}
+	if aligned == 0 {
+		return 1
+	}
+	return float64(mismatches) / float64(aligned)
+}
+
+// profScale is the scale of the fixed point scores of profile-profile
+// alignment.
+const profScale = 100
+
+// alignProfiles aligns the aligned sequences of the clusters x and y, which are
+// given by their indices into the multiple sequence alignment. The score of
+// aligning two columns is the mean substitution score of their pairs of
+// residues, where pairs with gaps score 0.
+func (msa *MSA) alignProfiles(x, y []int, s Scoring) {
+	px, py := msa.profile(x), msa.profile(y)
+	pairs := float64(len(x) * len(y))
+	sub := func(i, j int) int {
+		sum := 0
+		for _, a := range px[i] {
+			for _, b := range py[j] {
+				sum += a.n * b.n * s.Matrix.Score(a.c, b.c)
+			}
+		}
+		return int(math.Round(profScale * float64(sum) / pairs))
+	}
+	open, extend := s.gap()
+	ops, _, _, _ := gotoh(len(px), len(py), sub, profScale*open, profScale*extend, false)
+
+	// Insert gap columns into the aligned sequences of each cluster.
+	for _, k := range x {
+		msa.Seqs[k] = applyOps(msa.Seqs[k], ops, opInsert)
+	}
+	for _, k := range y {
+		msa.Seqs[k] = applyOps(msa.Seqs[k], ops, opDelete)
+	}
+}
+
+// applyOps returns the aligned sequence with gap columns inserted for each
+// operation of the provided kind.
+func applyOps(seq, ops []byte, gap byte) []byte {
+	buf := make([]byte, 0, len(ops))
+	i := 0
+	for _, op := range ops {
+		if op == gap {
+			buf = append(buf, '-')
+			continue
+		}
+		buf = append(buf, seq[i])
+		i++
+	}
+	return buf
+}
+
+// A residueCount records the number of occurrences of a residue in a column.
+type residueCount struct {
+	c byte
+	n int
+}
+
+// profile returns the residue counts of each column of the aligned sequences
+// of the cluster, excluding gaps.
+func (msa *MSA) profile(cluster []int) [][]residueCount {
+	cols := make([][]residueCount, len(msa.Seqs[cluster[0]]))
+	for i := range cols {
+	next:
+		for _, k := range cluster {
+			c := msa.Seqs[k][i]
+			if c == '-' {
+				continue
+			}
+			for j := range cols[i] {
+				if cols[i][j].c == c {
+					cols[i][j].n++
+					continue next
+				}
+			}
+			cols[i] = append(cols[i], residueCount{c: c, n: 1})
+		}
+	}
+	return cols
+}
+
+// upperCase returns the uppercase form of the ASCII letter c.
+func upperCase(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - ('a' - 'A')
+	}
+	return c
+}
+
+// clustalWidth is the number of columns per block of CLUSTAL files.
+const clustalWidth = 60
+
+// WriteCLUSTAL writes the alignment to w in CLUSTAL format, in blocks of 60
+// columns. Each block is followed by a conservation line, where '*' marks
+// columns in which all sequences have the same residue.
+func (msa *MSA) WriteCLUSTAL(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "CLUSTAL multiple sequence alignment\n\n")
+	width := 0
+	for _, id := range msa.IDs {
+		if len(id) > width {
+			width = len(id)
+		}
+	}
+	width += 6
+	n := 0
+	if len(msa.Seqs) > 0 {
+		n = len(msa.Seqs[0])
+	}
+	for start := 0; start < n; start += clustalWidth {
+		end := start + clustalWidth
+		if end > n {
+			end = n
+		}
+		fmt.Fprintln(bw)
+		for i, seq := range msa.Seqs {
+			fmt.Fprintf(bw, "%-*s%s\n", width, msa.IDs[i], seq[start:end])
+		}
+		fmt.Fprintf(bw, "%*s", width, "")
+		for col := start; col < end; col++ {
+			if msa.conserved(col) {
+				bw.WriteByte('*')
+			} else {
+				bw.WriteByte(' ')
+			}
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// conserved reports whether all sequences have the same residue in the column,
+// regardless of case.
+func (msa *MSA) conserved(col int) bool {
+	c := upperCase(msa.Seqs[0][col])
+	if c == '-' {
+		return false
+	}
+	for _, seq := range msa.Seqs[1:] {
+		if upperCase(seq[col]) != c {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteFASTA writes the aligned sequences to w in FASTA format, with '-' for
+// gaps.
+func (msa *MSA) WriteFASTA(w io.Writer) error {
+	fw := rosa.NewFASTAWriter(w)
+	for i, seq := range msa.Seqs {
+		if err := fw.Write(&rosa.Record{ID: msa.IDs[i], Seq: seq}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package align
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestProgressive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := Scoring{Matrix: Simple{Match: 2, Mismatch: -3}, GapOpen: 5, GapExtend: 2}
+	for i := 0; i < 50; i++ {
+		// Derive sequences from a common ancestor by point mutations, insertions
+		// and deletions.
+		ancestor := randSeq(r, "ACGT", 20+r.Intn(30))
+		var ids []string
+		var seqs [][]byte
+		for j := 0; j < 2+r.Intn(6); j++ {
+			seq := append([]byte(nil), ancestor...)
+			for k := 0; k < 3; k++ {
+				pos := r.Intn(len(seq))
+				switch r.Intn(3) {
+				case 0:
+					seq[pos] = "ACGT"[r.Intn(4)]
+				case 1:
+					seq = append(seq[:pos], append([]byte{"ACGT"[r.Intn(4)]}, seq[pos:]...)...)
+				case 2:
+					seq = append(seq[:pos], seq[pos+1:]...)
+				}
+			}
+			ids = append(ids, string(rune('a'+j)))
+			seqs = append(seqs, seq)
+		}
+		msa, err := Progressive(ids, seqs, s)
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		for j, seq := range msa.Seqs {
+			if len(seq) != len(msa.Seqs[0]) {
+				t.Errorf("i=%d: length %d of aligned sequence %d differs from length %d of first sequence.", i, len(seq), j, len(msa.Seqs[0]))
+			}
+			if got := bytes.Replace(seq, []byte("-"), nil, -1); !bytes.Equal(got, seqs[j]) {
+				t.Errorf("i=%d: aligned sequence %q does not match %q.", i, seq, seqs[j])
+			}
+		}
+	}
+	if _, err := Progressive(nil, nil, s); err == nil {
+		t.Errorf("expected error for no sequences, got nil.")
+	}
+}
+
+func TestWriteCLUSTAL(t *testing.T) {
+	msa := &MSA{
+		IDs:  []string{"seq1", "sequence2"},
+		Seqs: [][]byte{[]byte("ACGT-A"), []byte("ACCTTA")},
+	}
+	buf := new(bytes.Buffer)
+	if err := msa.WriteCLUSTAL(buf); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"CLUSTAL multiple sequence alignment",
+		"",
+		"",
+		"seq1           ACGT-A",
+		"sequence2      ACCTTA",
+		"               ** * *",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("expected %q, got %q.", want, got)
+	}
+}
@@ -0,0 +1,154 @@
+package assembly
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func TestEulerianPath(t *testing.T) {
+	golden := []struct {
+		reads []string
+		k     int
+		want  string
+		err   bool
+	}{
+		// i=0
+		{reads: []string{"GATTACA"}, k: 3, want: "GATTACA"},
+		// i=1
+		// Cycle; Rosalind PCOV.
+		{reads: []string{"ATTAC", "TACAG", "GATTA", "ACAGA", "CAGAT", "TTACA", "AGATT"}, k: 5, want: "ACAGATTACAG"},
+		// i=2
+		{reads: []string{"ACG", "TTT"}, k: 3, err: true},
+	}
+	for i, g := range golden {
+		graph, err := NewGraph(g.reads, g.k, false, 1)
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		path, err := graph.EulerianPath()
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error, got nil.", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if got := Spell(path); got != g.want {
+			t.Errorf("i=%d: expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
+
+func TestN50(t *testing.T) {
+	contigs := []string{strings.Repeat("A", 2), strings.Repeat("A", 3), strings.Repeat("A", 4), strings.Repeat("A", 5), strings.Repeat("A", 6)}
+	// Total length 20; 6+5 = 11 >= 10.
+	if got := N50(contigs); got != 5 {
+		t.Errorf("expected N50 5, got %d.", got)
+	}
+}
+
+// simulate returns reads of the provided length sampled uniformly from both
+// strands of the reference, at the provided coverage. Each base is substituted
+// with probability errRate.
+func simulate(r *rand.Rand, ref string, readLen int, coverage float64, errRate float64) []string {
+	n := int(coverage * float64(len(ref)) / float64(readLen))
+	var reads []string
+	for i := 0; i < n; i++ {
+		pos := r.Intn(len(ref) - readLen + 1)
+		read := []byte(ref[pos : pos+readLen])
+		for j := range read {
+			if r.Float64() < errRate {
+				read[j] = "ACGT"[r.Intn(4)]
+			}
+		}
+		s := string(read)
+		if r.Intn(2) == 0 {
+			s = rosa.RevComp(s)
+		}
+		reads = append(reads, s)
+	}
+	return reads
+}
+
+func TestAssemble(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	buf := make([]byte, 5000)
+	for i := range buf {
+		buf[i] = "ACGT"[r.Intn(4)]
+	}
+	ref := string(buf)
+	revc := rosa.RevComp(ref)
+	golden := []struct {
+		errRate float64
+		// Specifies whether the reference is circular.
+		circular bool
+		// Minimum N50 of the contigs.
+		n50 int
+	}{
+		// i=0
+		{errRate: 0, n50: len(ref) * 9 / 10},
+		// i=1
+		{errRate: 0.005, n50: len(ref) / 2},
+		// i=2; the two strands of a circular reference form a single contig.
+		{errRate: 0, circular: true, n50: len(ref)},
+	}
+	for i, g := range golden {
+		var reads []string
+		if g.circular {
+			reads = simulate(r, ref+ref[:99], 100, 30, g.errRate)
+		} else {
+			reads = simulate(r, ref, 100, 30, g.errRate)
+		}
+		graph, err := NewGraph(reads, 31, true, 1)
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		graph.Clean(2 * graph.K)
+		contigs := graph.Contigs()
+		if n50 := N50(contigs); n50 < g.n50 {
+			t.Errorf("i=%d: expected N50 of at least %d, got %d (%d contigs).", i, g.n50, n50, len(contigs))
+		}
+		if g.circular {
+			if len(contigs) != 1 {
+				t.Errorf("i=%d: expected 1 contig, got %d.", i, len(contigs))
+				continue
+			}
+			// The contig is a rotation of either strand of the reference.
+			period := contigs[0][:len(ref)]
+			if !strings.Contains(ref+ref, period) && !strings.Contains(revc+revc, period) {
+				t.Errorf("i=%d: contig not a rotation of the reference.", i)
+			}
+			continue
+		}
+		// The contigs originate from the reference.
+		for _, contig := range contigs {
+			if len(contig) >= 2*graph.K && !strings.Contains(ref, contig) && !strings.Contains(revc, contig) {
+				t.Errorf("i=%d: contig of length %d not present in reference.", i, len(contig))
+			}
+		}
+	}
+}
+
+func TestNewGraphAmbiguous(t *testing.T) {
+	// K-mers spanning the ambiguous base are omitted on both strands; the
+	// reverse complement of the read is GGGANCTTT.
+	g, err := NewGraph([]string{"AAAGNTCCC"}, 3, true, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range g.Edges() {
+		got = append(got, e.From+e.To[len(e.To)-1:])
+	}
+	want := []string{"AAA", "AAG", "CCC", "CTT", "GGA", "GGG", "TCC", "TTT"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected k-mers %v, got %v.", want, got)
+	}
+}
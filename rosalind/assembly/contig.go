@@ -0,0 +1,142 @@
+package assembly
+
+import (
+	"sort"
+
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+// Contigs returns the contigs of the graph; the sequences spelled by its
+// maximal unbranched paths, longest first. If the graph contains both strands,
+// only one contig of each reverse complementary pair is returned. Contigs of
+// isolated cycles start at the lexicographically smallest rotation of the
+// cycle.
+func (g *Graph) Contigs() []string {
+	var contigs []string
+	used := make(map[string]bool)
+	add := func(path []string, cycle bool) {
+		for _, kmer := range path {
+			used[kmer] = true
+		}
+		contig := spellEdges(path)
+		if cycle {
+			// The two strands of a cycle are spelled from different k-mers,
+			// so compare the canonical rotations of the cycle and its reverse
+			// complement.
+			period := contig[:len(path)]
+			fwd := minRotation(period)
+			if g.BothStrands && minRotation(string(seq.DNA(period).RevComp())) < fwd {
+				return
+			}
+			contig = wrap(fwd, len(contig))
+		} else if g.BothStrands && string(seq.DNA(contig).RevComp()) < contig {
+			// Keep the lexicographically smaller contig of each pair.
+			return
+		}
+		contigs = append(contigs, contig)
+	}
+
+	// Paths which start at branching nodes.
+	for _, kmer := range g.kmers() {
+		if used[kmer] || g.simple(kmer[:len(kmer)-1]) {
+			continue
+		}
+		path := []string{kmer}
+		for node := kmer[1:]; g.simple(node); {
+			kmer := g.out(node)[0]
+			path = append(path, kmer)
+			node = kmer[1:]
+		}
+		add(path, false)
+	}
+
+	// Isolated cycles, which consist of simple nodes only.
+	for _, kmer := range g.kmers() {
+		if used[kmer] {
+			continue
+		}
+		path := []string{kmer}
+		for next := g.out(kmer[1:])[0]; next != kmer; next = g.out(next[1:])[0] {
+			path = append(path, next)
+		}
+		add(path, true)
+	}
+
+	sort.SliceStable(contigs, func(i, j int) bool {
+		if len(contigs[i]) != len(contigs[j]) {
+			return len(contigs[i]) > len(contigs[j])
+		}
+		return contigs[i] < contigs[j]
+	})
+	return contigs
+}
+
+// spellEdges returns the sequence spelled by the path of edges.
+func spellEdges(path []string) string {
+	buf := []byte(path[0])
+	for _, kmer := range path[1:] {
+		buf = append(buf, kmer[len(kmer)-1])
+	}
+	return string(buf)
+}
+
+// minRotation returns the lexicographically smallest rotation of s, using
+// Booth's algorithm.
+func minRotation(s string) string {
+	n := len(s)
+	ss := s + s
+	// fail is the failure function of the rotation starting at k.
+	fail := make([]int, 2*n)
+	for i := range fail {
+		fail[i] = -1
+	}
+	k := 0
+	for j := 1; j < 2*n; j++ {
+		i := fail[j-k-1]
+		for i != -1 && ss[j] != ss[k+i+1] {
+			if ss[j] < ss[k+i+1] {
+				k = j - i - 1
+			}
+			i = fail[i]
+		}
+		if i == -1 && ss[j] != ss[k] {
+			if ss[j] < ss[k] {
+				k = j
+			}
+			fail[j-k] = -1
+		} else {
+			fail[j-k] = i + 1
+		}
+	}
+	return ss[k : k+n]
+}
+
+// wrap returns the first n bytes of the cyclic sequence s, repeated as needed.
+func wrap(s string, n int) string {
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = s[i%len(s)]
+	}
+	return string(buf)
+}
+
+// N50 returns the N50 statistic of the contigs; the length of the shortest
+// contig among the longest contigs which together cover at least half of the
+// total length.
+func N50(contigs []string) int {
+	lengths := make([]int, len(contigs))
+	total := 0
+	for i, contig := range contigs {
+		lengths[i] = len(contig)
+		total += len(contig)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(lengths)))
+	sum := 0
+	for _, n := range lengths {
+		sum += n
+		if 2*sum >= total {
+			return n
+		}
+	}
+	return 0
+}
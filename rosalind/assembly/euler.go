@@ -0,0 +1,140 @@
+package assembly
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EulerianPath returns an Eulerian path of the graph, which traverses each edge
+// exactly once, as a sequence of nodes. The path is a cycle which starts and
+// ends at the lexicographically smallest node if the in-degree of each node
+// equals its out-degree, and otherwise starts at the node whose out-degree
+// exceeds its in-degree by one.
+func (g *Graph) EulerianPath() ([]string, error) {
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	start := nodes[0]
+	starts, ends := 0, 0
+	for _, node := range nodes {
+		in, out := len(g.in(node)), len(g.out(node))
+		switch {
+		case out == in+1:
+			start = node
+			starts++
+		case in == out+1:
+			ends++
+		case in != out:
+			return nil, fmt.Errorf("assembly.Graph.EulerianPath: unbalanced node %q; in-degree %d and out-degree %d", node, in, out)
+		}
+	}
+	if starts > 1 || ends > 1 || starts != ends {
+		return nil, errors.New("assembly.Graph.EulerianPath: no Eulerian path; too many unbalanced nodes")
+	}
+
+	// Hierholzer's algorithm; follow unused edges from the top of the stack,
+	// and emit nodes in reverse when they have no unused edges left.
+	used := make(map[string]bool)
+	next := make(map[string]int)
+	stack := []string{start}
+	var path []string
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		out := g.out(node)
+		for next[node] < len(out) && used[out[next[node]]] {
+			next[node]++
+		}
+		if next[node] == len(out) {
+			path = append(path, node)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		kmer := out[next[node]]
+		used[kmer] = true
+		stack = append(stack, kmer[1:])
+	}
+	if len(used) != len(g.edges) {
+		return nil, errors.New("assembly.Graph.EulerianPath: no Eulerian path; graph is disconnected")
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+// Spell returns the sequence spelled by the path of nodes.
+func Spell(path []string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	buf := []byte(path[0])
+	for _, node := range path[1:] {
+		buf = append(buf, node[len(node)-1])
+	}
+	return string(buf)
+}
+
+// SpellCycle returns the cyclic sequence spelled by the cycle of nodes, where
+// the last node equals the first.
+func SpellCycle(cycle []string) string {
+	if len(cycle) == 0 {
+		return ""
+	}
+	buf := make([]byte, 0, len(cycle)-1)
+	for _, node := range cycle[:len(cycle)-1] {
+		buf = append(buf, node[0])
+	}
+	return string(buf)
+}
+
+// Cycles returns the cyclic sequences spelled by the graph if it consists of
+// disjoint cycles; that is, if each node has exactly one incoming and one
+// outgoing edge. The boolean return value is false otherwise.
+func (g *Graph) Cycles() ([]string, bool) {
+	nodes := g.Nodes()
+	for _, node := range nodes {
+		if !g.simple(node) {
+			return nil, false
+		}
+	}
+	var cycles []string
+	seen := make(map[string]bool)
+	for _, start := range nodes {
+		if seen[start] {
+			continue
+		}
+		cycle := []string{start}
+		seen[start] = true
+		for node := g.out(start)[0][1:]; ; node = g.out(node)[0][1:] {
+			cycle = append(cycle, node)
+			if node == start {
+				break
+			}
+			seen[node] = true
+		}
+		cycles = append(cycles, SpellCycle(cycle))
+	}
+	return cycles, true
+}
+
+// Circular returns a cyclic superstring of minimal length which contains each
+// read or its reverse complement. The superstring is spelled by one of the two
+// cycles of the De Bruijn graph of the reads and their reverse complements,
+// using the largest k-mer length at which the graph consists of exactly two
+// cycles; one per strand.
+func Circular(reads []string) (string, error) {
+	if len(reads) == 0 {
+		return "", errors.New("assembly.Circular: no reads provided")
+	}
+	for k := len(reads[0]); k >= 2; k-- {
+		g, err := NewGraph(reads, k, true, 1)
+		if err != nil {
+			return "", err
+		}
+		if cycles, ok := g.Cycles(); ok && len(cycles) == 2 {
+			return cycles[0], nil
+		}
+	}
+	return "", errors.New("assembly.Circular: unable to assemble reads; no k-mer length yields two cycles")
+}
@@ -0,0 +1,295 @@
+// Package assembly implements De Bruijn graph genome assembly of short reads.
+//
+// The k-mers of the reads form the edges of the De Bruijn graph, which connect
+// the (k-1)-mer prefix and suffix of each k-mer. Sequencing errors give rise to
+// tips, short dead-end paths, and bubbles, short alternative paths between two
+// nodes, which are removed before the unbranched paths of the graph are output
+// as contigs.
+package assembly
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+// bases holds the nucleotides of the graph, in lexicographic order.
+const bases = "ACGT"
+
+// A Graph is a De Bruijn graph of k-mers over the bases A, C, G and T.
+type Graph struct {
+	// K is the length of the k-mers which form the edges of the graph; nodes
+	// are (k-1)-mers.
+	K int
+	// BothStrands specifies whether the graph contains the k-mers of the reverse
+	// complement of each read.
+	BothStrands bool
+	// edges maps from k-mer to its number of occurrences in the reads.
+	edges map[string]int
+}
+
+// An Edge is an edge of a De Bruijn graph.
+type Edge struct {
+	// Prefix and suffix of the k-mer.
+	From, To string
+	// Number of occurrences of the k-mer in the reads.
+	Count int
+}
+
+// NewGraph returns the De Bruijn graph of the k-mers of the reads, and of their
+// reverse complements if bothStrands is set. K-mers which occur less than
+// minCount times are omitted, to filter out sequencing errors.
+func NewGraph(reads []string, k int, bothStrands bool, minCount int) (*Graph, error) {
+	if k < 2 {
+		return nil, fmt.Errorf("assembly.NewGraph: invalid k-mer length %d; expected at least 2", k)
+	}
+	seqs := reads
+	if bothStrands {
+		seqs = make([]string, 0, 2*len(reads))
+		for _, read := range reads {
+			seqs = append(seqs, read, string(seq.DNA(read).RevComp()))
+		}
+	}
+	idx, err := rosa.NewKmerIndex(seqs, k, false)
+	if err != nil {
+		return nil, err
+	}
+	g := &Graph{K: k, BothStrands: bothStrands, edges: make(map[string]int)}
+	for _, kmer := range idx.Kmers() {
+		if n := idx.Count(kmer); n >= minCount {
+			g.edges[kmer] = n
+		}
+	}
+	return g, nil
+}
+
+// Edges returns the edges of the graph, in lexicographic order of their k-mers.
+func (g *Graph) Edges() []Edge {
+	var edges []Edge
+	for _, kmer := range g.kmers() {
+		edges = append(edges, Edge{From: kmer[:len(kmer)-1], To: kmer[1:], Count: g.edges[kmer]})
+	}
+	return edges
+}
+
+// kmers returns the k-mers of the graph in lexicographic order.
+func (g *Graph) kmers() []string {
+	kmers := make([]string, 0, len(g.edges))
+	for kmer := range g.edges {
+		kmers = append(kmers, kmer)
+	}
+	sort.Strings(kmers)
+	return kmers
+}
+
+// Nodes returns the nodes of the graph, in lexicographic order.
+func (g *Graph) Nodes() []string {
+	seen := make(map[string]bool)
+	var nodes []string
+	for kmer := range g.edges {
+		for _, node := range []string{kmer[:len(kmer)-1], kmer[1:]} {
+			if !seen[node] {
+				seen[node] = true
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// out returns the outgoing edges of the node, in lexicographic order.
+func (g *Graph) out(node string) []string {
+	var kmers []string
+	for i := 0; i < len(bases); i++ {
+		if kmer := node + bases[i:i+1]; g.edges[kmer] > 0 {
+			kmers = append(kmers, kmer)
+		}
+	}
+	return kmers
+}
+
+// in returns the incoming edges of the node, in lexicographic order.
+func (g *Graph) in(node string) []string {
+	var kmers []string
+	for i := 0; i < len(bases); i++ {
+		if kmer := bases[i:i+1] + node; g.edges[kmer] > 0 {
+			kmers = append(kmers, kmer)
+		}
+	}
+	return kmers
+}
+
+// simple reports whether the node has exactly one incoming and one outgoing
+// edge.
+func (g *Graph) simple(node string) bool {
+	return len(g.in(node)) == 1 && len(g.out(node)) == 1
+}
+
+// remove removes the edges from the graph.
+func (g *Graph) remove(kmers []string) {
+	for _, kmer := range kmers {
+		delete(g.edges, kmer)
+	}
+}
+
+// RemoveTips removes tips from the graph; unbranched paths of at most maxLen
+// edges which start at a node without incoming edges, or end at a node without
+// outgoing edges, and join a node with other incoming or outgoing edges
+// respectively. Tips are only removed if one of the other edges has a higher
+// k-mer count than the mean of the tip, to preserve the ends of the genome. It
+// returns the number of removed edges.
+func (g *Graph) RemoveTips(maxLen int) int {
+	removed := 0
+	for _, node := range g.Nodes() {
+		// Tips starting at node.
+		if in, out := g.in(node), g.out(node); len(in) == 0 && len(out) == 1 {
+			path, end := g.walk(out[0], maxLen)
+			if len(path) <= maxLen && g.covered(g.in(end), path) {
+				g.remove(path)
+				removed += len(path)
+			}
+		}
+		// Tips ending at node.
+		if in, out := g.in(node), g.out(node); len(in) == 1 && len(out) == 0 {
+			path, start := g.walkBack(in[0], maxLen)
+			if len(path) <= maxLen && g.covered(g.out(start), path) {
+				g.remove(path)
+				removed += len(path)
+			}
+		}
+	}
+	return removed
+}
+
+// covered reports whether any of the edges, excluding those of the path, has a
+// higher k-mer count than the mean k-mer count of the path.
+func (g *Graph) covered(edges, path []string) bool {
+	cov := g.coverage(path)
+	for _, kmer := range edges {
+		if kmer != path[0] && kmer != path[len(path)-1] && float64(g.edges[kmer]) > cov {
+			return true
+		}
+	}
+	return false
+}
+
+// walk follows the unbranched path starting with the edge, for at most maxLen+1
+// edges. It returns the edges of the path and the node at which it ends; the
+// first node which is not simple.
+func (g *Graph) walk(kmer string, maxLen int) (path []string, end string) {
+	path = append(path, kmer)
+	node := kmer[1:]
+	for g.simple(node) && len(path) <= maxLen {
+		kmer = g.out(node)[0]
+		if kmer == path[0] {
+			// Cycle.
+			break
+		}
+		path = append(path, kmer)
+		node = kmer[1:]
+	}
+	return path, node
+}
+
+// walkBack follows the unbranched path ending with the edge backwards, for at
+// most maxLen+1 edges. It returns the edges of the path and the node at which
+// it starts; the first node which is not simple.
+func (g *Graph) walkBack(kmer string, maxLen int) (path []string, start string) {
+	path = append(path, kmer)
+	node := kmer[:len(kmer)-1]
+	for g.simple(node) && len(path) <= maxLen {
+		kmer = g.in(node)[0]
+		if kmer == path[0] {
+			// Cycle.
+			break
+		}
+		path = append(path, kmer)
+		node = kmer[:len(kmer)-1]
+	}
+	return path, node
+}
+
+// RemoveBubbles removes bubbles from the graph; unbranched paths of at most
+// maxLen edges from a node to another, which is also reachable within maxLen
+// edges through another outgoing edge of the first node. Of two such
+// alternatives, the path with the lower mean k-mer count is removed. It returns
+// the number of removed edges.
+func (g *Graph) RemoveBubbles(maxLen int) int {
+	removed := 0
+	for _, node := range g.Nodes() {
+		out := g.out(node)
+		if len(out) < 2 {
+			continue
+		}
+		for _, kmer := range out {
+			path, end := g.walk(kmer, maxLen)
+			if len(path) > maxLen || end == node {
+				continue
+			}
+			cov := g.coverage(path)
+			for _, alt := range out {
+				if alt == kmer || g.edges[alt] == 0 {
+					continue
+				}
+				if n := g.edges[alt]; float64(n) < cov || float64(n) == cov && alt > kmer {
+					continue
+				}
+				if g.reaches(alt, end, maxLen) {
+					g.remove(path)
+					removed += len(path)
+					break
+				}
+			}
+		}
+	}
+	return removed
+}
+
+// reaches reports whether the node is reachable within maxLen edges of a path
+// starting with the provided edge.
+func (g *Graph) reaches(kmer, node string, maxLen int) bool {
+	depth := map[string]int{kmer[1:]: 1}
+	queue := []string{kmer[1:]}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == node {
+			return true
+		}
+		if depth[cur] >= maxLen {
+			continue
+		}
+		for _, next := range g.out(cur) {
+			if _, ok := depth[next[1:]]; !ok {
+				depth[next[1:]] = depth[cur] + 1
+				queue = append(queue, next[1:])
+			}
+		}
+	}
+	return false
+}
+
+// Clean repeatedly removes tips and bubbles of at most maxLen edges from the
+// graph, until none remain. It returns the number of removed edges.
+func (g *Graph) Clean(maxLen int) int {
+	total := 0
+	for {
+		removed := g.RemoveTips(maxLen) + g.RemoveBubbles(maxLen)
+		if removed == 0 {
+			return total
+		}
+		total += removed
+	}
+}
+
+// coverage returns the mean k-mer count of the edges of the path.
+func (g *Graph) coverage(path []string) float64 {
+	sum := 0
+	for _, kmer := range path {
+		sum += g.edges[kmer]
+	}
+	return float64(sum) / float64(len(path))
+}
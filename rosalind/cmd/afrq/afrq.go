@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/prob"
+)
+
+func main() {
+	// Get input from stdin; the fraction of homozygous recessive organisms of
+	// each population.
+	buf, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var fracs []float64
+	for _, field := range strings.Fields(string(buf)) {
+		x, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fracs = append(fracs, x)
+	}
+
+	// Calculate the probability that a randomly selected organism of each
+	// population carries at least one recessive allele.
+	ps, err := CarrierProbs(fracs)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(strings.Join(ps, " "))
+}
+
+// CarrierProbs returns the probability that a randomly selected organism of
+// each population in Hardy-Weinberg equilibrium carries at least one recessive
+// allele, given the fraction of homozygous recessive organisms of each
+// population.
+func CarrierProbs(fracs []float64) ([]string, error) {
+	var ps []string
+	for _, x := range fracs {
+		p, err := prob.CarrierProb(x)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, fmt.Sprintf("%.3f", p))
+	}
+	return ps, nil
+}
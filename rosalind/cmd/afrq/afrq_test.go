@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleCarrierProbs() {
+	// Calculate the probability that a randomly selected organism of each
+	// population carries at least one recessive allele.
+	fracs := []float64{0.1, 0.25, 0.5}
+	ps, err := CarrierProbs(fracs)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(ps)
+	// Output: [0.532 0.750 0.914]
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/assembly"
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+var (
+	// flagK corresponds to the k-mer length.
+	flagK int
+	// flagMinCount corresponds to the minimum number of occurrences of k-mers.
+	flagMinCount int
+	// flagMaxLen corresponds to the maximum length of removed tips and bubbles.
+	flagMaxLen int
+	// flagWidth corresponds to the line width of FASTA sequences.
+	flagWidth int
+)
+
+func init() {
+	flag.IntVar(&flagK, "k", 31, "K-mer length.")
+	flag.IntVar(&flagMinCount, "mincount", 2, "Minimum number of occurrences of k-mers.")
+	flag.IntVar(&flagMaxLen, "maxlen", 0, "Maximum length in edges of removed tips and bubbles; 2k if 0.")
+	flag.IntVar(&flagWidth, "width", 60, "Line width of FASTA sequences; no wrapping if 0.")
+}
+
+func main() {
+	flag.Parse()
+
+	// Read reads from stdin.
+	reads, err := ReadReads(os.Stdin)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Assemble the reads.
+	maxLen := flagMaxLen
+	if maxLen == 0 {
+		maxLen = 2 * flagK
+	}
+	contigs, removed, err := Assemble(reads, flagK, flagMinCount, maxLen)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Write contigs to stdout.
+	w := bufio.NewWriter(os.Stdout)
+	fw := rosa.NewFASTAWriter(w)
+	fw.Width = flagWidth
+	total := 0
+	for i, contig := range contigs {
+		rec := &rosa.Record{
+			ID:   fmt.Sprintf("contig_%d", i+1),
+			Desc: fmt.Sprintf("length=%d", len(contig)),
+			Seq:  []byte(contig),
+		}
+		if err := fw.Write(rec); err != nil {
+			log.Fatalln(err)
+		}
+		total += len(contig)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Fprintf(os.Stderr, "reads:          %d\n", len(reads))
+	fmt.Fprintf(os.Stderr, "removed k-mers: %d\n", removed)
+	fmt.Fprintf(os.Stderr, "contigs:        %d\n", len(contigs))
+	fmt.Fprintf(os.Stderr, "total length:   %d\n", total)
+	fmt.Fprintf(os.Stderr, "N50:            %d\n", assembly.N50(contigs))
+}
+
+// ReadReads reads the sequences of reads from r, in FASTA or FASTQ format.
+func ReadReads(r io.Reader) ([]string, error) {
+	br := bufio.NewReader(r)
+	first, err := br.Peek(1)
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var reads []string
+	if first[0] == '@' {
+		qr := rosa.NewFASTQReader(br, rosa.Phred33)
+		for {
+			rec, err := qr.Read()
+			if err != nil {
+				if err == io.EOF {
+					return reads, nil
+				}
+				return nil, err
+			}
+			reads = append(reads, string(rec.Seq))
+		}
+	}
+	fr := rosa.NewFASTAReader(br)
+	for {
+		rec, err := fr.Read()
+		if err != nil {
+			if err == io.EOF {
+				return reads, nil
+			}
+			return nil, err
+		}
+		reads = append(reads, string(rec.Seq))
+	}
+}
+
+// Assemble assembles the reads into contigs, using a De Bruijn graph of the
+// k-mers of both strands which occur at least minCount times. Tips and bubbles
+// of at most maxLen edges are removed before the contigs are located. The
+// number of removed edges is returned along with the contigs, longest first.
+func Assemble(reads []string, k, minCount, maxLen int) (contigs []string, removed int, err error) {
+	g, err := assembly.NewGraph(reads, k, true, minCount)
+	if err != nil {
+		return nil, 0, err
+	}
+	removed = g.Clean(maxLen)
+	return g.Contigs(), removed, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+func ExampleAssemble() {
+	const s = `@r1
+ATTAGACCTGCCGGAA
++
+IIIIIIIIIIIIIIII
+@r2
+GACCTGCCGGAATACG
++
+IIIIIIIIIIIIIIII
+@r3
+GCCGGAATACGTTAGC
++
+IIIIIIIIIIIIIIII
+`
+	reads, err := ReadReads(strings.NewReader(s))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	contigs, _, err := Assemble(reads, 7, 1, 14)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(contigs)
+	// Output: [ATTAGACCTGCCGGAATACGTTAGC]
+}
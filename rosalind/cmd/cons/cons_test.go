@@ -3,6 +3,9 @@ package main
 import (
 	"fmt"
 	"log"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
 )
 
 var seqs = []string{
@@ -40,3 +43,37 @@ func ExampleProfile_Cons() {
 	fmt.Println(cons)
 	// Output: ATGCAACT
 }
+
+func ExampleAlign() {
+	const s = `>Rosalind_1
+ATCCAGCT
+>Rosalind_2
+ATCAGCT
+>Rosalind_3
+ATCCAGCTT`
+	fas, err := rosa.ParseFASTA(strings.NewReader(s))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	msa, err := Align(fas)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var seqs []string
+	for _, seq := range msa.Seqs {
+		seqs = append(seqs, string(seq))
+	}
+	profile, err := NewProfile(seqs, true)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(profile.Cons())
+	fmt.Println(profile)
+	// Output:
+	// ATCCAGCT
+	// A: 3 0 0 0 3 0 0 0 0
+	// C: 0 0 2 3 0 0 3 0 0
+	// G: 0 0 0 0 0 3 0 0 0
+	// T: 0 3 0 0 0 0 0 1 3
+	// -: 0 0 1 0 0 0 0 2 0
+}
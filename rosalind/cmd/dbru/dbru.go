@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/assembly"
+)
+
+func main() {
+	// Get k-mers from stdin, one per line.
+	var kmers []string
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		if kmer := strings.TrimSpace(s.Text()); len(kmer) > 0 {
+			kmers = append(kmers, kmer)
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Fatalln(err)
+	}
+
+	// Print the adjacency list of the De Bruijn graph.
+	edges, err := Dbru(kmers)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, edge := range edges {
+		fmt.Printf("(%s, %s)\n", edge.From, edge.To)
+	}
+}
+
+// Dbru returns the edges of the De Bruijn graph of the k-mers and their reverse
+// complements, in lexicographic order.
+func Dbru(kmers []string) ([]assembly.Edge, error) {
+	if len(kmers) == 0 {
+		return nil, nil
+	}
+	g, err := assembly.NewGraph(kmers, len(kmers[0]), true, 1)
+	if err != nil {
+		return nil, err
+	}
+	return g.Edges(), nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleDbru() {
+	kmers := []string{"TGAT", "CATG", "TCAT", "ATGC", "CATC", "CATC"}
+	edges, err := Dbru(kmers)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, edge := range edges {
+		fmt.Printf("(%s, %s)\n", edge.From, edge.To)
+	}
+	// Output:
+	// (ATC, TCA)
+	// (ATG, TGA)
+	// (ATG, TGC)
+	// (CAT, ATC)
+	// (CAT, ATG)
+	// (GAT, ATG)
+	// (GCA, CAT)
+	// (TCA, CAT)
+	// (TGA, GAT)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/restrict"
+	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+var (
+	// flagEnzymes corresponds to a comma-separated list of enzyme names.
+	flagEnzymes string
+	// flagCircular specifies whether the DNA sequences are circular.
+	flagCircular bool
+	// flagRows corresponds to the number of rows of the gel.
+	flagRows int
+	// flagList specifies whether to list the enzymes of the database.
+	flagList bool
+)
+
+func init() {
+	flag.StringVar(&flagEnzymes, "enzymes", "EcoRI,BamHI,HindIII", "Comma-separated list of enzyme names.")
+	flag.BoolVar(&flagCircular, "circular", false, "Treat DNA sequences as circular.")
+	flag.IntVar(&flagRows, "rows", 20, "Number of rows of the gel.")
+	flag.BoolVar(&flagList, "list", false, "List the enzymes of the database.")
+}
+
+func main() {
+	flag.Parse()
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	if flagList {
+		for _, e := range restrict.Enzymes() {
+			fmt.Fprintf(w, "%-8s %s\n", e.Name, e)
+		}
+		return
+	}
+	var enzymes []*restrict.Enzyme
+	for _, name := range strings.Split(flagEnzymes, ",") {
+		e, err := restrict.Lookup(strings.TrimSpace(name))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		enzymes = append(enzymes, e)
+	}
+
+	// Parse FASTA from stdin.
+	fas, err := rosa.ParseFASTA(os.Stdin)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Digest each DNA sequence with each enzyme and with all enzymes combined.
+	for i, rec := range fas.Records {
+		dna, err := seq.ParseDNA(string(rec.Seq))
+		if err != nil {
+			log.Fatalf("%s: %v", rec.ID, err)
+		}
+		if i != 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d bp)\n\n", rec.ID, len(dna))
+		sites := restrict.Map(dna, enzymes, flagCircular)
+		for _, site := range sites {
+			fmt.Fprintf(w, "%-8s %-16s site %d%c, cut %d\n", site.Enzyme.Name, site.Enzyme, site.Pos+1, site.Strand, site.Cut)
+		}
+		fmt.Fprintln(w)
+		if err := restrict.WriteTable(w, restrict.Digest(len(dna), sites, flagCircular)); err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Fprintln(w)
+		if err := restrict.WriteGel(w, Lanes(dna, enzymes, flagCircular), flagRows); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
+
+// Lanes returns the gel lanes of the digests of the DNA sequence by each
+// enzyme, followed by a lane of the digest by all enzymes combined if more than
+// one enzyme is provided.
+func Lanes(dna seq.DNA, enzymes []*restrict.Enzyme, circular bool) []restrict.Lane {
+	var lanes []restrict.Lane
+	lane := func(name string, enzymes []*restrict.Enzyme) restrict.Lane {
+		var sizes []int
+		for _, frag := range restrict.Digest(len(dna), restrict.Map(dna, enzymes, circular), circular) {
+			sizes = append(sizes, frag.Len)
+		}
+		return restrict.Lane{Name: name, Sizes: sizes}
+	}
+	for _, e := range enzymes {
+		lanes = append(lanes, lane(e.Name, []*restrict.Enzyme{e}))
+	}
+	if len(enzymes) > 1 {
+		lanes = append(lanes, lane("all", enzymes))
+	}
+	return lanes
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/mewmew/playground/rosalind/restrict"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+func ExampleLanes() {
+	dna, err := seq.ParseDNA("GGATCCAAAAGAATTCAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAGAATTCAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var enzymes []*restrict.Enzyme
+	for _, name := range []string{"EcoRI", "BamHI"} {
+		e, err := restrict.Lookup(name)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		enzymes = append(enzymes, e)
+	}
+	for _, lane := range Lanes(dna, enzymes, false) {
+		fmt.Println(lane.Name, lane.Sizes)
+	}
+	// Output:
+	// EcoRI [11 38 61]
+	// BamHI [1 109]
+	// all [1 10 38 61]
+}
@@ -5,6 +5,9 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/seq"
 )
 
 func main() {
@@ -13,26 +16,17 @@ func main() {
 	if err != nil {
 		log.Fatalln(err)
 	}
-	dna := string(buf)
+	dna, err := seq.ParseDNA(strings.TrimSpace(string(buf)))
+	if err != nil {
+		log.Fatalln(err)
+	}
 
 	// Count the nucleotide occurrences within the DNA sequence.
 	fmt.Println(BaseCount(dna))
 }
 
 // BaseCount returns the respective number of times that the nucleotides 'A',
-// 'C', 'G' and 'T' occurs in the provided DNA string.
-func BaseCount(dna string) (a, c, g, t int) {
-	for _, base := range dna {
-		switch base {
-		case 'A':
-			a++
-		case 'C':
-			c++
-		case 'G':
-			g++
-		case 'T':
-			t++
-		}
-	}
-	return a, c, g, t
+// 'C', 'G' and 'T' occurs in the provided DNA sequence, regardless of case.
+func BaseCount(dna seq.DNA) (a, c, g, t int) {
+	return dna.Count()
 }
@@ -2,10 +2,16 @@ package main
 
 import (
 	"fmt"
+	"log"
+
+	"github.com/mewmew/playground/rosalind/seq"
 )
 
 func ExampleBaseCount() {
-	dna := "AGCTTTTCATTCTGACTGCAACGGGCAATATGTCTCTGTGTGGATTAAAAAAAGAGTGTCTGATAGCAGC"
+	dna, err := seq.ParseDNA("AGCTTTTCATTCTGACTGCAACGGGCAATATGTCTCTGTGTGGATTAAAAAAAGAGTGTCTGATAGCAGC")
+	if err != nil {
+		log.Fatalln(err)
+	}
 	fmt.Println(BaseCount(dna))
 	// Output: 20 12 17 21
 }
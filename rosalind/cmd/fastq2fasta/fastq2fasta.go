@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+var (
+	// flagPhred corresponds to the quality encoding offset; 33 or 64.
+	flagPhred int
+	// flagTrim corresponds to the quality trimming threshold.
+	flagTrim int
+	// flagWidth corresponds to the line width of FASTA sequences.
+	flagWidth int
+	// flagStats specifies whether to print per-position mean quality scores.
+	flagStats bool
+)
+
+func init() {
+	flag.IntVar(&flagPhred, "phred", 33, "Quality encoding offset (33 or 64).")
+	flag.IntVar(&flagTrim, "trim", 0, "Quality trimming threshold; no trimming if 0.")
+	flag.IntVar(&flagWidth, "width", 60, "Line width of FASTA sequences; no wrapping if 0.")
+	flag.BoolVar(&flagStats, "stats", false, "Print per-position mean quality scores to stderr.")
+}
+
+func main() {
+	flag.Parse()
+	var enc rosa.QualEncoding
+	switch flagPhred {
+	case 33:
+		enc = rosa.Phred33
+	case 64:
+		enc = rosa.Phred64
+	default:
+		log.Fatalf("invalid quality encoding offset %d; expected 33 or 64", flagPhred)
+	}
+
+	// Convert FASTQ from stdin to FASTA on stdout.
+	w := bufio.NewWriter(os.Stdout)
+	qs, err := Convert(w, os.Stdin, enc, flagTrim, flagWidth)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalln(err)
+	}
+	if flagStats {
+		for i, mean := range qs.Mean() {
+			fmt.Fprintf(os.Stderr, "%d\t%.2f\n", i+1, mean)
+		}
+	}
+}
+
+// Convert converts the FASTQ records read from r to FASTA records written to w,
+// wrapping sequence lines at the provided width. Reads are quality trimmed
+// before conversion if threshold is non-zero. The quality statistics of the
+// untrimmed reads are returned.
+func Convert(w io.Writer, r io.Reader, enc rosa.QualEncoding, threshold, width int) (*rosa.QualStats, error) {
+	qr := rosa.NewFASTQReader(r, enc)
+	fw := rosa.NewFASTAWriter(w)
+	fw.Width = width
+	qs := new(rosa.QualStats)
+	for {
+		rec, err := qr.Read()
+		if err != nil {
+			if err == io.EOF {
+				return qs, nil
+			}
+			return nil, err
+		}
+		qs.Add(rec)
+		if threshold > 0 {
+			rec.Trim(threshold)
+		}
+		if err := fw.Write(&rec.Record); err != nil {
+			return nil, err
+		}
+	}
+}
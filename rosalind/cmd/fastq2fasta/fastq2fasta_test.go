@@ -0,0 +1,24 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+const s = `@SEQ_ID
+GATTTGGGGTTCAAAGCAGTATCGATCAAATAGTAAATCCATTTGTTCAACTCACAGTTT
++
+!''*((((***+))%%%++)(%%%%).1***-+*''))**55CCF>>>>>>CCCCCCC65`
+
+func ExampleConvert() {
+	_, err := Convert(os.Stdout, strings.NewReader(s), rosa.Phred33, 0, 60)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// >SEQ_ID
+	// GATTTGGGGTTCAAAGCAGTATCGATCAAATAGTAAATCCATTTGTTCAACTCACAGTTT
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/assembly"
+)
+
+func main() {
+	// Get reads from stdin, one per line.
+	var reads []string
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		if read := strings.TrimSpace(s.Text()); len(read) > 0 {
+			reads = append(reads, read)
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Fatalln(err)
+	}
+
+	// Assemble the circular chromosome.
+	chrom, err := Gasm(reads)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(chrom)
+}
+
+// Gasm returns a cyclic superstring of minimal length which contains each read
+// or its reverse complement.
+func Gasm(reads []string) (string, error) {
+	return assembly.Circular(reads)
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleGasm() {
+	reads := []string{"AATCT", "TGTAA", "GATTA", "ACAGA"}
+	chrom, err := Gasm(reads)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Any rotation of GATTACA or of its reverse complement is a valid answer.
+	fmt.Println(chrom)
+	// Output: AATCTGT
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
 )
 
 func main() {
@@ -16,33 +17,33 @@ func main() {
 	}
 
 	// Locate the DNA sequence with the highest GC-content.
-	label, gc := MaxGC(fas)
+	label, gc, err := MaxGC(fas)
+	if err != nil {
+		log.Fatalln(err)
+	}
 	fmt.Println(label)
 	fmt.Printf("%.6f\n", gc)
 }
 
 // MaxGC returns the label and GC-content of the DNA sequence with the highest
 // GC-content in fas.
-func MaxGC(fas *rosa.FASTA) (maxLabel string, maxGC float64) {
-	for label, dna := range fas.Seqs {
+func MaxGC(fas *rosa.FASTA) (maxLabel string, maxGC float64, err error) {
+	for label, s := range fas.Seqs {
+		dna, err := seq.ParseDNA(s)
+		if err != nil {
+			return "", 0, fmt.Errorf("%s: %v", label, err)
+		}
 		gc := GC(dna)
 		if gc > maxGC {
 			maxGC = gc
 			maxLabel = label
 		}
 	}
-	return maxLabel, maxGC
+	return maxLabel, maxGC, nil
 }
 
 // GC returns the percentage of the provided DNA sequence's bases that are
 // either guanine or cytosine.
-func GC(dna string) (gc float64) {
-	for _, base := range dna {
-		switch base {
-		case 'C', 'G':
-			gc++
-		}
-	}
-	gc = 100 * gc / float64(len(dna))
-	return gc
+func GC(dna seq.DNA) (gc float64) {
+	return 100 * dna.GC()
 }
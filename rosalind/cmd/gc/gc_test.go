@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
 )
 
 const s = `>Rosalind_6404
@@ -25,7 +26,10 @@ func ExampleMaxGC() {
 		log.Fatalln(err)
 	}
 
-	label, gc := MaxGC(fas)
+	label, gc, err := MaxGC(fas)
+	if err != nil {
+		log.Fatalln(err)
+	}
 	fmt.Println(label)
 	fmt.Printf("%.6f\n", gc)
 	// Output:
@@ -34,7 +38,11 @@ func ExampleMaxGC() {
 }
 
 func ExampleGC() {
-	gc := GC("CCATCGGTAGCGCATCCTTAGTCCAATTAAGTCCCTATCCAGGCGCTCCGCCGAAGGTCTATATCCATTTGTCAGCAGACACGC")
+	dna, err := seq.ParseDNA("CCATCGGTAGCGCATCCTTAGTCCAATTAAGTCCCTATCCAGGCGCTCCGCCGAAGGTCTATATCCATTTGTCAGCAGACACGC")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	gc := GC(dna)
 	fmt.Printf("%.6f\n", gc)
 	// Output: 53.571429
 }
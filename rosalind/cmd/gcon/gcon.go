@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/align"
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func main() {
+	// Parse FASTA from stdin.
+	fas, err := rosa.ParseFASTA(os.Stdin)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if len(fas.Records) != 2 {
+		log.Fatalf("invalid number of protein strings; expected 2, got %d", len(fas.Records))
+	}
+
+	// Calculate the maximum global alignment score of the protein strings.
+	fmt.Println(Gcon(fas.Records[0].Seq, fas.Records[1].Seq))
+}
+
+// Gcon returns the maximum global alignment score of the protein strings a and
+// b, using the BLOSUM62 scoring matrix and a constant gap penalty of 5.
+func Gcon(a, b []byte) int {
+	s := align.Scoring{Matrix: align.BLOSUM62, GapOpen: 5, GapExtend: 0}
+	return align.Hirschberg(a, b, s).Score
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func ExampleGcon() {
+	const s = `>Rosalind_79
+PLEASANTLY
+>Rosalind_41
+MEANLY`
+	fas, err := rosa.ParseFASTA(strings.NewReader(s))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(Gcon(fas.Records[0].Seq, fas.Records[1].Seq))
+	// Output: 13
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func ExampleGlob() {
+	const s = `>Rosalind_67
+PLEASANTLY
+>Rosalind_17
+MEANLY`
+	fas, err := rosa.ParseFASTA(strings.NewReader(s))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(Glob(fas.Records[0].Seq, fas.Records[1].Seq))
+	// Output: 8
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/mewkiz/pkg/bufioutil"
+	"github.com/mewmew/playground/rosalind/rosa"
 )
 
 func main() {
@@ -30,13 +31,5 @@ func main() {
 
 // HamDist calculates the Hamming distance between a and b.
 func HamDist(a, b string) (n int, err error) {
-	if len(a) != len(b) {
-		return 0, fmt.Errorf("HamDist: length mismatch; len(a)=%d, len(b)=%d", len(a), len(b))
-	}
-	for i := 0; i < len(a); i++ {
-		if a[i] != b[i] {
-			n++
-		}
-	}
-	return n, nil
+	return rosa.HamDist(a, b)
 }
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/prob"
+)
+
+func main() {
+	// Get input from stdin; the number of couples of each genotype pairing.
+	var couples [6]int
+	_, err := fmt.Fscan(os.Stdin, &couples[0], &couples[1], &couples[2], &couples[3], &couples[4], &couples[5])
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Calculate the expected number of offspring displaying the dominant
+	// phenotype, where each couple has two offspring.
+	fmt.Println(ExpectedOffspring(couples))
+}
+
+// ExpectedOffspring returns the expected number of offspring displaying the
+// dominant phenotype, where each couple has two offspring and couples holds the
+// number of couples of each genotype pairing: AA-AA, AA-Aa, AA-aa, Aa-Aa, Aa-aa
+// and aa-aa.
+func ExpectedOffspring(couples [6]int) float64 {
+	x, _ := prob.ExpectedOffspring(couples, 2).Float64()
+	return x
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"fmt"
+)
+
+func ExampleExpectedOffspring() {
+	// Calculate the expected number of offspring displaying the dominant
+	// phenotype.
+	couples := [6]int{1, 0, 0, 1, 0, 1}
+	fmt.Println(ExpectedOffspring(couples))
+	// Output: 3.5
+}
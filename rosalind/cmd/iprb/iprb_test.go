@@ -2,13 +2,17 @@ package main
 
 import (
 	"fmt"
+	"log"
 )
 
 func ExampleDominantProb() {
 	// Calculate the probability that two randomly selected mating organisms will
 	// produce an individual possessing a dominant allele.
 	k, m, n := 2, 2, 2
-	prob := DominantProb(k, m, n)
-	fmt.Printf("%.5f\n", prob)
+	p, err := DominantProb(k, m, n)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("%.5f\n", p)
 	// Output: 0.78333
 }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func main() {
+	// Parse FASTA from stdin.
+	fas, err := rosa.ParseFASTA(os.Stdin)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if len(fas.Records) != 1 {
+		log.Fatalf("invalid number of DNA sequences; expected 1, got %d", len(fas.Records))
+	}
+
+	// Print the 4-mer composition of the DNA sequence.
+	counts, err := Kmer(string(fas.Records[0].Seq))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(counts)
+}
+
+// Kmer returns the 4-mer composition of the DNA sequence; the number of
+// occurrences of each 4-mer in lexicographic order, separated by spaces.
+func Kmer(dna string) (string, error) {
+	counts, err := rosa.KmerComposition(dna, 4)
+	if err != nil {
+		return "", err
+	}
+	strs := make([]string, len(counts))
+	for i, count := range counts {
+		strs[i] = fmt.Sprint(count)
+	}
+	return strings.Join(strs, " "), nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func main() {
+	// Parse FASTA from stdin.
+	fas, err := rosa.ParseFASTA(os.Stdin)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Locate a longest common substring of the DNA sequences.
+	fmt.Println(Lcsm(fas))
+}
+
+// Lcsm returns a longest common substring of the DNA sequences in fas.
+func Lcsm(fas *rosa.FASTA) string {
+	var seqs []string
+	for _, rec := range fas.Records {
+		seqs = append(seqs, string(rec.Seq))
+	}
+	return rosa.LongestCommonSubstring(seqs)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func ExampleLcsm() {
+	const s = `>Rosalind_1
+GATTACA
+>Rosalind_2
+TAGACCA
+>Rosalind_3
+ATACA`
+	fas, err := rosa.ParseFASTA(strings.NewReader(s))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Any of AC, CA and TA is a valid answer.
+	fmt.Println(Lcsm(fas))
+	// Output: AC
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/prob"
+)
+
+func main() {
+	// Get input from stdin.
+	var k, n int
+	_, err := fmt.Fscan(os.Stdin, &k, &n)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Calculate the probability that at least n organisms of generation k are
+	// heterozygous in two factors.
+	p, err := IndependentAlleles(k, n)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(p)
+}
+
+// IndependentAlleles returns the probability that at least n organisms of
+// generation k are heterozygous in two factors (AaBb), with three decimals.
+func IndependentAlleles(k, n int) (string, error) {
+	p, err := prob.IndependentAlleles(k, n)
+	if err != nil {
+		return "", err
+	}
+	return p.FloatString(3), nil
+}
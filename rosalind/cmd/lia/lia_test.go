@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleIndependentAlleles() {
+	// Calculate the probability that at least n organisms of generation k are
+	// heterozygous in two factors.
+	k, n := 2, 1
+	p, err := IndependentAlleles(k, n)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(p)
+	// Output: 0.684
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/align"
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func main() {
+	// Parse FASTA from stdin.
+	fas, err := rosa.ParseFASTA(os.Stdin)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if len(fas.Records) != 2 {
+		log.Fatalf("invalid number of protein strings; expected 2, got %d", len(fas.Records))
+	}
+
+	// Locate the maximum scoring local alignment of the protein strings.
+	score, subA, subB := Loca(fas.Records[0].Seq, fas.Records[1].Seq)
+	fmt.Println(score)
+	fmt.Println(subA)
+	fmt.Println(subB)
+}
+
+// Loca returns the maximum local alignment score of the protein strings a and
+// b, and the aligned substrings of a and b, using the PAM250 scoring matrix and
+// a linear gap penalty of 5.
+func Loca(a, b []byte) (score int, subA, subB string) {
+	s := align.Scoring{Matrix: align.PAM250, GapOpen: 5, GapExtend: 5}
+	aln := align.Local(a, b, s)
+	return aln.Score, string(a[aln.AStart:aln.AEnd]), string(b[aln.BStart:aln.BEnd])
+}
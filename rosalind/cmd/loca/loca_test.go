@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func ExampleLoca() {
+	const s = `>Rosalind_80
+MEANLYPRTEINSTRING
+>Rosalind_21
+PLEASANTLYEINSTEIN`
+	fas, err := rosa.ParseFASTA(strings.NewReader(s))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	score, subA, subB := Loca(fas.Records[0].Seq, fas.Records[1].Seq)
+	fmt.Println(score)
+	fmt.Println(subA)
+	fmt.Println(subB)
+	// Output:
+	// 23
+	// MEANLYPRTEINSTRIN
+	// LEASANTLYEINSTEIN
+}
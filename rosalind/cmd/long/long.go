@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func main() {
+	// Parse FASTA from stdin.
+	fas, err := rosa.ParseFASTA(os.Stdin)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Assemble the reads into the shortest superstring.
+	var reads []string
+	for _, rec := range fas.Records {
+		reads = append(reads, string(rec.Seq))
+	}
+	s, err := Long(reads)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(s)
+}
+
+// Long returns the shortest superstring of the reads, which is assembled by
+// gluing together pairs of reads that overlap by more than half their length.
+func Long(reads []string) (string, error) {
+	return rosa.Superstring(reads)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleLong() {
+	reads := []string{
+		"ATTAGACCTG",
+		"CCTGCCGGAA",
+		"AGACCTGCCG",
+		"GCCGGAATAC",
+	}
+	s, err := Long(reads)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(s)
+	// Output: ATTAGACCTGCCGGAATAC
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleNkew() {
+	d1, err := Nkew("(dog:42,cat:33);", "cat", "dog")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	d2, err := Nkew("((dog:4,cat:3):74,robot:98,elephant:58);", "dog", "elephant")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(d1, d2)
+	// Output: 75 136
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func main() {
+	// Get input from stdin; pairs of a Newick tree and two node names,
+	// separated by blank lines.
+	var lines []string
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if len(line) == 0 {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := s.Err(); err != nil {
+		log.Fatalln(err)
+	}
+	if len(lines)%2 != 0 {
+		log.Fatalf("invalid number of lines; expected pairs of trees and node names, got %d lines", len(lines))
+	}
+
+	// Print the distance between the nodes of each tree.
+	var ds []string
+	for i := 0; i < len(lines); i += 2 {
+		names := strings.Fields(lines[i+1])
+		if len(names) != 2 {
+			log.Fatalf("invalid number of node names; expected 2, got %d", len(names))
+		}
+		d, err := Nwck(lines[i], names[0], names[1])
+		if err != nil {
+			log.Fatalln(err)
+		}
+		ds = append(ds, fmt.Sprint(d))
+	}
+	fmt.Println(strings.Join(ds, " "))
+}
+
+// Nwck returns the number of edges on the path between the nodes a and b of the
+// tree in Newick format.
+func Nwck(tree, a, b string) (int, error) {
+	root, err := rosa.ParseNewick(tree)
+	if err != nil {
+		return 0, err
+	}
+	d, err := root.Distance(a, b, false)
+	if err != nil {
+		return 0, err
+	}
+	return int(d), nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleNwck() {
+	d1, err := Nwck("(cat)dog;", "dog", "cat")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	d2, err := Nwck("(dog,cat);", "dog", "cat")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(d1, d2)
+	// Output: 1 2
+}
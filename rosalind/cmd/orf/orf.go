@@ -1,80 +1,119 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"sort"
-	"strings"
 
+	"github.com/mewmew/playground/rosalind/gff"
+	"github.com/mewmew/playground/rosalind/orf"
 	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
 )
 
+var (
+	// flagCode corresponds to the NCBI translation table ID of the genetic code.
+	flagCode int
+	// flagAltStarts specifies whether alternative start codons start ORFs.
+	flagAltStarts bool
+	// flagNested specifies whether nested ORFs are located.
+	flagNested bool
+	// flagMinLen corresponds to the minimum length of ORFs in nucleotides.
+	flagMinLen int
+	// flagFormat corresponds to the output format; rosalind, gff3 or bed.
+	flagFormat string
+)
+
+func init() {
+	flag.IntVar(&flagCode, "code", 1, "NCBI translation table ID of the genetic code.")
+	flag.BoolVar(&flagAltStarts, "altstarts", false, "Start ORFs at alternative start codons of the genetic code.")
+	flag.BoolVar(&flagNested, "nested", true, "Locate nested ORFs, starting at each in-frame start codon.")
+	flag.IntVar(&flagMinLen, "minlen", 0, "Minimum length of ORFs in nucleotides, including the stop codon.")
+	flag.StringVar(&flagFormat, "format", "rosalind", "Output format (rosalind, gff3 or bed).")
+}
+
 func main() {
+	flag.Parse()
+
 	// Parse FASTA from stdin.
 	fas, err := rosa.ParseFASTA(os.Stdin)
 	if err != nil {
 		log.Fatalln(err)
 	}
-	label, err := fas.Label(0)
+	code, err := seq.Code(flagCode)
 	if err != nil {
 		log.Fatalln(err)
 	}
-	dna := fas.Seqs[label]
-	revc := rosa.RevComp(dna)
+	opts := orf.Options{
+		Code:      code,
+		AltStarts: flagAltStarts,
+		Nested:    flagNested,
+		MinLen:    flagMinLen,
+	}
 
-	// Locates each open reading frame (ORF) of the DNA-sequence and its reverse
-	// complement. Use a map for the store unique proteins.
-	orfs := ORFs(dna)
-	orfs = append(orfs, ORFs(revc)...)
-	uniq := make(map[string]bool)
-	for _, orf := range orfs {
-		rna := rosa.Trans(orf)
-		prot, err := rosa.Prot(rna)
+	// Locate the open reading frames (ORFs) of each DNA sequence.
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	gw := gff.NewWriter(w)
+	var orfs []*orf.ORF
+	for _, rec := range fas.Records {
+		dna, err := seq.ParseDNA(string(rec.Seq))
+		if err != nil {
+			log.Fatalf("%s: %v", rec.ID, err)
+		}
+		recORFs := orf.Find(dna, opts)
+		switch flagFormat {
+		case "rosalind":
+			orfs = append(orfs, recORFs...)
+		case "gff3":
+			err = orf.WriteGFF3(gw, rec.ID, recORFs)
+		case "bed":
+			err = orf.WriteBED(w, rec.ID, recORFs)
+		default:
+			log.Fatalf("invalid output format %q; expected rosalind, gff3 or bed", flagFormat)
+		}
 		if err != nil {
 			log.Fatalln(err)
 		}
-		uniq[prot] = true
 	}
+	switch flagFormat {
+	case "rosalind":
+		// Print the distinct proteins, sorted.
+		for _, prot := range Proteins(orfs) {
+			fmt.Fprintln(w, prot)
+		}
+	case "gff3":
+		if err := gw.Flush(); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
 
-	// Print sorted proteins.
+// Proteins returns the distinct proteins encoded by the ORFs, sorted.
+func Proteins(orfs []*orf.ORF) []string {
+	uniq := make(map[string]bool)
+	for _, o := range orfs {
+		uniq[o.Protein.String()] = true
+	}
 	var prots []string
 	for prot := range uniq {
 		prots = append(prots, prot)
 	}
 	sort.Strings(prots)
-	for _, prot := range prots {
-		fmt.Println(prot)
-	}
+	return prots
 }
 
-// startSeq specifies the DNA-sequence of the start codon.
-const startSeq = "ATG"
-
-// stopSeqs specifies the DNA-sequences of the stop codons.
-var stopSeqs = []string{"TAG", "TGA", "TAA"}
-
-// ORFs locates each open reading frame (ORF) of dna. An ORF starts from the
+// ORFs locates each open reading frame (ORF) of dna, excluding its reverse
+// complement, and returns their nucleotide sequences. An ORF starts from the
 // start codon (ATG) and ends by a stop codon (TAG, TGA, TAA), without any other
 // stop codons in between.
 func ORFs(dna string) (orfs []string) {
-	const codonSeqLen = 3
-loop:
-	for {
-		pos := strings.Index(dna, startSeq)
-		if pos == -1 {
-			break
-		}
-		s := dna[pos:]
-		dna = dna[pos+codonSeqLen:]
-		for i := 2 * codonSeqLen; i <= len(s); i += codonSeqLen {
-			orf := s[:i]
-			for _, stopSeq := range stopSeqs {
-				if strings.HasSuffix(orf, stopSeq) {
-					orfs = append(orfs, orf)
-					continue loop
-				}
-			}
+	for _, o := range orf.Find(seq.DNA(dna), orf.Options{Nested: true}) {
+		if o.Strand == '+' {
+			orfs = append(orfs, o.Seq.String())
 		}
 	}
 	return orfs
@@ -5,7 +5,9 @@ import (
 	"log"
 	"sort"
 
+	"github.com/mewmew/playground/rosalind/orf"
 	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
 )
 
 func ExampleORFs() {
@@ -41,3 +43,18 @@ func ExampleORFs() {
 	// MLLGSFRLIPKETLIQVAGSSPCNLS
 	// MTPRLGLESLLE
 }
+
+func ExampleProteins() {
+	dna, err := seq.ParseDNA("AGCCATGTAGCTAACTCAGGTTACATGGGGATGACCCCGCGACTTGGATTAGAGTCTCTTTTGGAATAAGCCTGAATGATCCGAGTAGCATCTCAG")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, prot := range Proteins(orf.Find(dna, orf.Options{Nested: true})) {
+		fmt.Println(prot)
+	}
+	// Output:
+	// M
+	// MGMTPRLGLESLLE
+	// MLLGSFRLIPKETLIQVAGSSPCNLS
+	// MTPRLGLESLLE
+}
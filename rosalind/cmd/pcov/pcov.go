@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/assembly"
+)
+
+func main() {
+	// Get k-mers from stdin, one per line.
+	var kmers []string
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		if kmer := strings.TrimSpace(s.Text()); len(kmer) > 0 {
+			kmers = append(kmers, kmer)
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Fatalln(err)
+	}
+
+	// Assemble the circular chromosome.
+	chrom, err := Pcov(kmers)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(chrom)
+}
+
+// Pcov returns the cyclic superstring of minimal length of the k-mers, which
+// perfectly cover a circular chromosome. The superstring is spelled by an
+// Eulerian cycle of the De Bruijn graph of the k-mers.
+func Pcov(kmers []string) (string, error) {
+	if len(kmers) == 0 {
+		return "", nil
+	}
+	g, err := assembly.NewGraph(kmers, len(kmers[0]), false, 1)
+	if err != nil {
+		return "", err
+	}
+	cycle, err := g.EulerianPath()
+	if err != nil {
+		return "", err
+	}
+	return assembly.SpellCycle(cycle), nil
+}
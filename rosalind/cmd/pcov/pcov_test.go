@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExamplePcov() {
+	kmers := []string{"ATTAC", "TACAG", "GATTA", "ACAGA", "CAGAT", "TTACA", "AGATT"}
+	chrom, err := Pcov(kmers)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Any rotation of GATTACA is a valid answer.
+	fmt.Println(chrom)
+	// Output: ACAGATT
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func main() {
+	// Parse FASTA from stdin.
+	fas, err := rosa.ParseFASTA(os.Stdin)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Print the p-distance matrix of the DNA sequences.
+	dist, err := Pdst(fas)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Print(dist)
+}
+
+// Pdst returns the p-distance matrix of the DNA sequences in the FASTA file,
+// with one row per line and five decimal places per entry.
+func Pdst(fas *rosa.FASTA) (string, error) {
+	var seqs []string
+	for _, rec := range fas.Records {
+		seqs = append(seqs, string(rec.Seq))
+	}
+	dist, err := rosa.PDistance(seqs)
+	if err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	for _, row := range dist {
+		for j, d := range row {
+			if j != 0 {
+				buf.WriteByte(' ')
+			}
+			fmt.Fprintf(buf, "%.5f", d)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String(), nil
+}
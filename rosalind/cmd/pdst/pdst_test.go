@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func ExamplePdst() {
+	const s = `>Rosalind_9499
+TTTCCATTTA
+>Rosalind_0942
+GATTCATTTC
+>Rosalind_6568
+TTTCCATTTT
+>Rosalind_1833
+GTTCCATTTA`
+	fas, err := rosa.ParseFASTA(strings.NewReader(s))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	dist, err := Pdst(fas)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Print(dist)
+	// Output:
+	// 0.00000 0.40000 0.10000 0.10000
+	// 0.40000 0.00000 0.40000 0.30000
+	// 0.10000 0.40000 0.00000 0.20000
+	// 0.10000 0.30000 0.20000 0.00000
+}
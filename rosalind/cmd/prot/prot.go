@@ -6,19 +6,23 @@ import (
 	"os"
 
 	"github.com/mewkiz/pkg/bufioutil"
-	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
 )
 
 func main() {
 	// Get input from stdin.
 	br := bufioutil.NewReader(os.Stdin)
-	rna, err := br.ReadLine()
+	line, err := br.ReadLine()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	rna, err := seq.ParseRNA(line)
 	if err != nil {
 		log.Fatalln(err)
 	}
 
 	// Translate the RNA sequence into a protein.
-	prot, err := rosa.Prot(rna)
+	prot, err := rna.Translate()
 	if err != nil {
 		log.Fatalln(err)
 	}
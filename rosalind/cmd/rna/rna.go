@@ -5,8 +5,9 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 
-	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
 )
 
 func main() {
@@ -15,8 +16,11 @@ func main() {
 	if err != nil {
 		log.Fatalln(err)
 	}
-	dna := string(buf)
+	dna, err := seq.ParseDNA(strings.TrimSpace(string(buf)))
+	if err != nil {
+		log.Fatalln(err)
+	}
 
 	// Transcribe the DNA sequence into an RNA sequence.
-	fmt.Println(rosa.Trans(dna))
+	fmt.Println(dna.Transcribe())
 }
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/problem"
+)
+
+var (
+	// flagList specifies whether to list the registered problems.
+	flagList bool
+	// flagCheck specifies whether to check the solutions of sample datasets.
+	flagCheck bool
+	// flagTestdata corresponds to the directory of sample datasets.
+	flagTestdata string
+)
+
+func init() {
+	flag.BoolVar(&flagList, "list", false, "List registered problems.")
+	flag.BoolVar(&flagCheck, "check", false, "Check the solutions of sample datasets of the given problems, or of all problems.")
+	flag.StringVar(&flagTestdata, "testdata", "", "Directory of sample datasets; required by -check.")
+	flag.Usage = usage
+}
+
+func usage() {
+	const use = `
+Solve Rosalind problems, or check the solutions of sample datasets against
+their expected output.
+
+Usage:
+
+	rosalind [OPTION]... ID [FILE]
+	rosalind -check -testdata DIR [ID]...
+
+The sample datasets of the registered problems are located in the testdata
+directory of the problem package; e.g. run
+
+	rosalind -check -testdata rosalind/problem/testdata
+
+from the root of the repository.
+
+Flags:
+`
+	fmt.Fprintln(os.Stderr, use[1:])
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Parse()
+	switch {
+	case flagList:
+		for _, p := range problem.Problems() {
+			fmt.Printf("%-6s %s\n", p.ID, p.Title)
+		}
+	case flagCheck:
+		if flagTestdata == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+		if !Check(os.Stdout, flag.Args(), flagTestdata) {
+			os.Exit(1)
+		}
+	default:
+		if flag.NArg() < 1 || flag.NArg() > 2 {
+			flag.Usage()
+			os.Exit(1)
+		}
+		r := io.Reader(os.Stdin)
+		if flag.NArg() == 2 {
+			f, err := os.Open(flag.Arg(1))
+			if err != nil {
+				log.Fatalln(err)
+			}
+			defer f.Close()
+			r = f
+		}
+		output, err := Solve(flag.Arg(0), r)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Print(output)
+	}
+}
+
+// Solve solves the problem with the provided ID for the dataset read from r.
+// The output ends with a new line.
+func Solve(id string, r io.Reader) (string, error) {
+	p, err := problem.Lookup(id)
+	if err != nil {
+		return "", err
+	}
+	output, err := p.Run(r)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	return output, nil
+}
+
+// Check checks the solutions of the sample datasets located in dir of the
+// problems with the provided IDs, or of all registered problems if ids is
+// empty, and reports the result of each problem to w. It returns true if all
+// solutions are correct.
+func Check(w io.Writer, ids []string, dir string) bool {
+	var ps []*problem.Problem
+	if len(ids) == 0 {
+		ps = problem.Problems()
+	}
+	for _, id := range ids {
+		p, err := problem.Lookup(id)
+		if err != nil {
+			fmt.Fprintf(w, "FAIL %v\n", err)
+			return false
+		}
+		ps = append(ps, p)
+	}
+	ok := true
+	for _, p := range ps {
+		if err := p.CheckSample(dir); err != nil {
+			fmt.Fprintf(w, "FAIL %v\n", err)
+			ok = false
+			continue
+		}
+		fmt.Fprintf(w, "ok   %s\n", p.ID)
+	}
+	return ok
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+func ExampleSolve() {
+	output, err := Solve("iprb", strings.NewReader("2 2 2"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Print(output)
+	// Output: 0.78333
+}
+
+func ExampleCheck() {
+	ok := Check(os.Stdout, []string{"fib", "IPRB"}, "../../problem/testdata")
+	fmt.Println(ok)
+	// Output:
+	// ok   fib
+	// ok   iprb
+	// true
+}
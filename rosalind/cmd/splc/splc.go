@@ -1,15 +1,32 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"strings"
 
+	"github.com/mewmew/playground/rosalind/gene"
+	"github.com/mewmew/playground/rosalind/gff"
 	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
 )
 
+var (
+	// flagFormat corresponds to the output format; rosalind or gff3.
+	flagFormat string
+	// flagStrict specifies whether introns must have GT...AG splice sites.
+	flagStrict bool
+)
+
+func init() {
+	flag.StringVar(&flagFormat, "format", "rosalind", "Output format (rosalind or gff3).")
+	flag.BoolVar(&flagStrict, "strict", false, "Require the GT...AG splice site consensus of introns.")
+}
+
 func main() {
+	flag.Parse()
+
 	// Parse FASTA from stdin.
 	fas, err := rosa.ParseFASTA(os.Stdin)
 	if err != nil {
@@ -18,35 +35,82 @@ func main() {
 
 	// The first sequence in the FASTA file is the DNA sequence and all other
 	// sequences are introns.
-	dnaLabel, err := fas.Label(0)
+	if len(fas.Records) == 0 {
+		log.Fatalln("missing DNA sequence")
+	}
+	rec := fas.Records[0]
+	dna := string(rec.Seq)
+	var introns []string
+	for _, intron := range fas.Records[1:] {
+		introns = append(introns, string(intron.Seq))
+	}
+	g, err := Gene(rec.ID, dna, introns)
 	if err != nil {
 		log.Fatalln(err)
 	}
-	dna := fas.Seqs[dnaLabel]
-	introns := make([]string, len(fas.Seqs)-1)
-	for label, s := range fas.Seqs {
-		if label == dnaLabel {
-			continue
+	if flagStrict {
+		if err := g.CheckSpliceSites(seq.DNA(dna)); err != nil {
+			log.Fatalln(err)
 		}
-		introns = append(introns, s)
 	}
 
-	// Splice the DNA, transcribe it into RNA and translate it into a protein.
-	rna := rosa.Trans(Splice(dna, introns))
-	prot, err := rosa.Prot(rna)
+	switch flagFormat {
+	case "rosalind":
+		// Splice the DNA, transcribe it into RNA and translate it into a protein.
+		prot, err := g.Protein(seq.DNA(dna), nil)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Println(prot)
+	case "gff3":
+		gw := gff.NewWriter(os.Stdout)
+		if err := gene.WriteGFF3(gw, []*gene.Gene{g}); err != nil {
+			log.Fatalln(err)
+		}
+		if err := gw.Flush(); err != nil {
+			log.Fatalln(err)
+		}
+	default:
+		log.Fatalf("invalid output format %q; expected rosalind or gff3", flagFormat)
+	}
+}
+
+// Gene returns the gene model of the provided DNA sequence, in which each
+// intron is located once; at its first occurrence which does not overlap the
+// introns before it. The coding region spans the spliced sequence.
+func Gene(id, dna string, introns []string) (*gene.Gene, error) {
+	ivs, err := gene.Locate(dna, introns)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
-	fmt.Println(prot)
+	return gene.FromIntrons(id, id, '+', len(dna), ivs)
 }
 
-// Splice splices the provided DNA sequence by removing any occurrence of the
-// provided introns.
-func Splice(dna string, introns []string) string {
-	oldnew := make([]string, 2*len(introns))
-	for i := range introns {
-		oldnew[i*2] = introns[i]
+// Splc splices the provided DNA sequence by removing the provided introns, and
+// translates it into a protein.
+func Splc(dna string, introns []string) (string, error) {
+	g, err := Gene("", dna, introns)
+	if err != nil {
+		return "", err
+	}
+	prot, err := g.Protein(seq.DNA(dna), nil)
+	if err != nil {
+		return "", err
+	}
+	return prot.String(), nil
+}
+
+// Splice splices the provided DNA sequence by removing the provided introns.
+// Unlike string replacement, each intron is removed at a single location even
+// if it occurs several times.
+func Splice(dna string, introns []string) (string, error) {
+	g, err := Gene("", dna, introns)
+	if err != nil {
+		return "", err
+	}
+	s, err := g.Transcript(seq.DNA(dna))
+	if err != nil {
+		return "", err
 	}
-	r := strings.NewReplacer(oldnew...)
-	return r.Replace(dna)
+	return s.String(), nil
 }
@@ -3,16 +3,13 @@ package main
 import (
 	"fmt"
 	"log"
-
-	"github.com/mewmew/playground/rosalind/rosa"
 )
 
 func ExampleSplc() {
 	// Splice the DNA, transcribe it into RNA and translate it into a protein.
 	dna := "ATGGTCTACATAGCTGACAAACAGCACGTAGCAATCGGTCGAATCTCGAGAGGCATATGGTCACATGATCGGTCGAGCGTGTTTCAAAGTTTGCGCCTAG"
 	introns := []string{"ATCGGTCGAA", "ATCGGTCGAGCGTGT"}
-	rna := rosa.Trans(Splice(dna, introns))
-	prot, err := rosa.Prot(rna)
+	prot, err := Splc(dna, introns)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -23,6 +20,22 @@ func ExampleSplc() {
 func ExampleSplice() {
 	dna := "ATGGTCTACATAGCTGACAAACAGCACGTAGCAATCGGTCGAATCTCGAGAGGCATATGGTCACATGATCGGTCGAGCGTGTTTCAAAGTTTGCGCCTAG"
 	introns := []string{"ATCGGTCGAA", "ATCGGTCGAGCGTGT"}
-	fmt.Println(Splice(dna, introns))
+	s, err := Splice(dna, introns)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(s)
 	// Output: ATGGTCTACATAGCTGACAAACAGCACGTAGCATCTCGAGAGGCATATGGTCACATGTTCAAAGTTTGCGCCTAG
 }
+
+func ExampleSplice_repeat() {
+	// The intron GTAG also occurs in the second exon, which is left intact.
+	dna := "ATGGTAGCCCGTAGTAA"
+	introns := []string{"GTAG"}
+	s, err := Splice(dna, introns)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(s)
+	// Output: ATGCCCGTAGTAA
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strings"
 
 	"github.com/mewmew/playground/rosalind/rosa"
 )
@@ -43,16 +42,5 @@ func main() {
 // SubSeq returns the first location of each character in sep as a subsequence
 // of s, or nil if no match was found.
 func SubSeq(s, sep string) (locs []int) {
-	var i int
-	for j := 0; j < len(sep); j++ {
-		base := sep[j]
-		pos := strings.IndexByte(s[i:], base)
-		if pos == -1 {
-			return nil
-		}
-		loc := i + pos
-		locs = append(locs, loc)
-		i += pos + 1
-	}
-	return locs
+	return rosa.SubSeq(s, sep)
 }
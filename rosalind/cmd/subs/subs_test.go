@@ -2,6 +2,11 @@ package main
 
 import (
 	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/playground/rosalind/rosa"
 )
 
 func ExampleSubs() {
@@ -19,3 +24,71 @@ func ExampleSubs() {
 	fmt.Println()
 	// Output: 2 4 10
 }
+
+// genome returns a pseudo-random DNA sequence of the provided length.
+func genome(n int) string {
+	r := rand.New(rand.NewSource(1))
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = "ACGT"[r.Intn(4)]
+	}
+	return string(buf)
+}
+
+// benchSize is the length in bases of the benchmark genome.
+const benchSize = 1 << 20
+
+func BenchmarkSubs(b *testing.B) {
+	s := genome(benchSize)
+	t := s[1000:1008]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Subs(s, t)
+	}
+}
+
+func BenchmarkSuffixArrayLookup(b *testing.B) {
+	s := genome(benchSize)
+	t := s[1000:1008]
+	sa := rosa.NewSuffixArray(s)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sa.Lookup(t)
+	}
+}
+
+func BenchmarkKmerIndexPositions(b *testing.B) {
+	s := genome(benchSize)
+	t := s[1000:1008]
+	idx, err := rosa.NewKmerIndex([]string{s}, len(t), false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Positions(t)
+	}
+}
+
+func TestSubsIndex(t *testing.T) {
+	s := genome(1 << 16)
+	sa := rosa.NewSuffixArray(s)
+	idx, err := rosa.NewKmerIndex([]string{s}, 6, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		pattern := s[i*97 : i*97+6]
+		want := Subs(s, pattern)
+		if got := sa.Lookup(pattern); !reflect.DeepEqual(got, want) {
+			t.Errorf("i=%d: expected suffix array locations %v, got %v.", i, want, got)
+		}
+		var got []int
+		for _, pos := range idx.Positions(pattern) {
+			got = append(got, pos.Pos)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("i=%d: expected k-mer index locations %v, got %v.", i, want, got)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func main() {
+	// Get input from stdin; the number of nodes followed by an adjacency list,
+	// one edge per line.
+	s := bufio.NewScanner(os.Stdin)
+	if !s.Scan() {
+		log.Fatalln("missing number of nodes")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s.Text()))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var edges [][2]int
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			log.Fatalf("invalid edge %q; expected 2 nodes, got %d", s.Text(), len(fields))
+		}
+		var edge [2]int
+		for i, field := range fields {
+			if edge[i], err = strconv.Atoi(field); err != nil {
+				log.Fatalln(err)
+			}
+		}
+		edges = append(edges, edge)
+	}
+	if err := s.Err(); err != nil {
+		log.Fatalln(err)
+	}
+
+	// Print the minimum number of edges required to produce a tree.
+	m, err := Tree(n, edges)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(m)
+}
+
+// Tree returns the minimum number of edges which must be added to the acyclic
+// graph of n nodes, numbered from 1 to n, to produce a tree.
+func Tree(n int, edges [][2]int) (int, error) {
+	return rosa.TreeEdges(n, edges)
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleTree() {
+	edges := [][2]int{{1, 2}, {2, 8}, {4, 10}, {5, 9}, {6, 10}, {7, 9}}
+	n, err := Tree(10, edges)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(n)
+	// Output: 3
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/prob"
+)
+
+var (
+	// flagTrials corresponds to the number of simulations used to estimate the
+	// probability, or 0 to calculate it exactly.
+	flagTrials int
+	// flagSeed corresponds to the seed of the random number generator used by
+	// simulations.
+	flagSeed int64
+)
+
+func init() {
+	flag.IntVar(&flagTrials, "trials", 0, "Number of simulations used to estimate the probability (0 to calculate it exactly).")
+	flag.Int64Var(&flagSeed, "seed", 1, "Seed of the random number generator used by simulations.")
+}
+
+func main() {
+	flag.Parse()
+
+	// Get input from stdin.
+	var n, m, g, k int
+	_, err := fmt.Fscan(os.Stdin, &n, &m, &g, &k)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Calculate the probability of at least k copies of the recessive allele
+	// after g generations.
+	var p float64
+	if flagTrials > 0 {
+		rng := rand.New(rand.NewSource(flagSeed))
+		p, err = Estimate(rng, n, m, g, k, flagTrials)
+	} else {
+		p, err = prob.RecessiveProb(n, m, g, k)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("%.3f\n", p)
+}
+
+// Estimate estimates the probability of at least k copies of a recessive allele
+// after g generations of a Wright-Fisher population of n diploid organisms,
+// which initially holds m copies of the dominant allele, as the fraction of the
+// given number of simulations in which it occurs.
+func Estimate(rng *rand.Rand, n, m, g, k, trials int) (float64, error) {
+	hits := 0
+	for i := 0; i < trials; i++ {
+		counts, err := prob.Simulate(rng, 2*n, 2*n-m, g)
+		if err != nil {
+			return 0, err
+		}
+		if counts[g] >= k {
+			hits++
+		}
+	}
+	return float64(hits) / float64(trials), nil
+}
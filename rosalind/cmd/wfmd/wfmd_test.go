@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+
+	"github.com/mewmew/playground/rosalind/prob"
+)
+
+func ExampleEstimate() {
+	// Estimate the probability of at least one copy of the recessive allele
+	// after two generations, and compare it to the exact probability.
+	n, m, g, k := 4, 6, 2, 1
+	rng := rand.New(rand.NewSource(1))
+	est, err := Estimate(rng, n, m, g, k, 100000)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	p, err := prob.RecessiveProb(n, m, g, k)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("%.3f %v\n", p, math.Abs(est-p) < 0.01)
+	// Output: 0.772 true
+}
@@ -0,0 +1,64 @@
+package gene_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/gene"
+	"github.com/mewmew/playground/rosalind/gff"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+func ExampleGene() {
+	dna, err := seq.ParseDNA("CCATGAAAGTCCCCAGCCCTAAGG")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	exons := []gene.Interval{{Start: 0, End: 8}, {Start: 16, End: 24}}
+	g, err := gene.New("g1", "chr1", '+', exons, gene.Interval{Start: 2, End: 22})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := g.CheckSpliceSites(dna); err != nil {
+		log.Fatalln(err)
+	}
+	mrna, err := g.MRNA(dna)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	prot, err := g.Protein(dna, nil)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(g.Introns())
+	fmt.Println(mrna)
+	fmt.Println(prot)
+	// Output:
+	// [[8, 16)]
+	// CCAUGAAACCCUAAGG
+	// MKP
+}
+
+func ExampleWriteGFF3() {
+	exons := []gene.Interval{{Start: 0, End: 9}, {Start: 16, End: 24}}
+	g, err := gene.New("g1", "chr1", '-', exons, gene.Interval{Start: 2, End: 22})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	gw := gff.NewWriter(os.Stdout)
+	if err := gene.WriteGFF3(gw, []*gene.Gene{g}); err != nil {
+		log.Fatalln(err)
+	}
+	if err := gw.Flush(); err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// ##gff-version 3
+	// chr1	gene	gene	1	24	.	-	.	ID=g1
+	// chr1	gene	mRNA	1	24	.	-	.	ID=g1.mRNA;Parent=g1
+	// chr1	gene	exon	1	9	.	-	.	ID=g1.exon1;Parent=g1.mRNA
+	// chr1	gene	exon	17	24	.	-	.	ID=g1.exon2;Parent=g1.mRNA
+	// chr1	gene	CDS	3	9	.	-	0	ID=g1.cds;Parent=g1.mRNA
+	// chr1	gene	CDS	17	22	.	-	0	ID=g1.cds;Parent=g1.mRNA
+}
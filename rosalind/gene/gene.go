@@ -0,0 +1,301 @@
+// Package gene implements coordinate-based gene models, which splice
+// transcripts by the intervals of their exons rather than by sequence.
+//
+// Intervals are 0-based and half-open, and always refer to the forward strand
+// of the sequence; the features of genes on the reverse strand are read from
+// the reverse complement of their intervals.
+package gene
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+// An Interval is the half-open interval [Start, End) of a sequence, starting at
+// 0.
+type Interval struct {
+	// Start and end offset of the interval.
+	Start, End int
+}
+
+// Len returns the length of the interval.
+func (iv Interval) Len() int {
+	return iv.End - iv.Start
+}
+
+func (iv Interval) String() string {
+	return fmt.Sprintf("[%d, %d)", iv.Start, iv.End)
+}
+
+// A Gene is a gene model of a transcript; the exons of a gene on one strand of
+// a sequence, and the coding region within them.
+type Gene struct {
+	// ID of the gene.
+	ID string
+	// ID of the sequence (e.g. chromosome) of the gene.
+	SeqID string
+	// Strand of the gene; '+' or '-'.
+	Strand byte
+	// Exons of the gene, sorted by start offset and non-overlapping.
+	Exons []Interval
+	// Coding region of the gene, from the first base of the start codon to the
+	// last base of the stop codon, or the empty interval if non-coding.
+	CDS Interval
+}
+
+// New returns a new gene model on the given strand of the sequence, with the
+// provided exons and coding region. The exons are sorted by start offset.
+func New(id, seqID string, strand byte, exons []Interval, cds Interval) (*Gene, error) {
+	g := &Gene{
+		ID:     id,
+		SeqID:  seqID,
+		Strand: strand,
+		Exons:  append([]Interval(nil), exons...),
+		CDS:    cds,
+	}
+	sort.Slice(g.Exons, func(i, j int) bool { return g.Exons[i].Start < g.Exons[j].Start })
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// FromIntrons returns a new gene model on the given strand of a sequence of
+// length n, which is transcribed in full except for the provided introns. The
+// coding region spans the entire transcript.
+func FromIntrons(id, seqID string, strand byte, n int, introns []Interval) (*Gene, error) {
+	ivs := append([]Interval(nil), introns...)
+	sort.Slice(ivs, func(i, j int) bool { return ivs[i].Start < ivs[j].Start })
+	var exons []Interval
+	start := 0
+	for _, intron := range ivs {
+		if intron.Start < start {
+			return nil, fmt.Errorf("gene.FromIntrons: intron %v overlaps preceding intron", intron)
+		}
+		if intron.Start > start {
+			exons = append(exons, Interval{Start: start, End: intron.Start})
+		}
+		start = intron.End
+	}
+	if start < n {
+		exons = append(exons, Interval{Start: start, End: n})
+	}
+	if len(exons) == 0 {
+		return nil, fmt.Errorf("gene.FromIntrons: no exons in sequence of length %d", n)
+	}
+	cds := Interval{Start: exons[0].Start, End: exons[len(exons)-1].End}
+	return New(id, seqID, strand, exons, cds)
+}
+
+// Validate reports whether the gene model is well-formed; i.e. its strand is
+// known, its exons are non-empty and non-overlapping, and its coding region
+// starts and ends within exons.
+func (g *Gene) Validate() error {
+	if g.Strand != '+' && g.Strand != '-' {
+		return fmt.Errorf("gene.Gene.Validate: %s: invalid strand %q", g.ID, g.Strand)
+	}
+	if len(g.Exons) == 0 {
+		return fmt.Errorf("gene.Gene.Validate: %s: no exons", g.ID)
+	}
+	for i, exon := range g.Exons {
+		if exon.Start < 0 || exon.Len() <= 0 {
+			return fmt.Errorf("gene.Gene.Validate: %s: invalid exon %v", g.ID, exon)
+		}
+		if i > 0 && exon.Start < g.Exons[i-1].End {
+			return fmt.Errorf("gene.Gene.Validate: %s: exon %v overlaps exon %v", g.ID, exon, g.Exons[i-1])
+		}
+	}
+	if g.CDS.Len() == 0 {
+		return nil
+	}
+	if g.CDS.Len() < 0 || g.exon(g.CDS.Start) == -1 || g.exon(g.CDS.End-1) == -1 {
+		return fmt.Errorf("gene.Gene.Validate: %s: coding region %v not within exons", g.ID, g.CDS)
+	}
+	return nil
+}
+
+// exon returns the index of the exon containing the offset, or -1.
+func (g *Gene) exon(pos int) int {
+	for i, exon := range g.Exons {
+		if exon.Start <= pos && pos < exon.End {
+			return i
+		}
+	}
+	return -1
+}
+
+// Span returns the interval from the start of the first exon to the end of the
+// last exon.
+func (g *Gene) Span() Interval {
+	return Interval{Start: g.Exons[0].Start, End: g.Exons[len(g.Exons)-1].End}
+}
+
+// Introns returns the introns of the gene; the intervals between consecutive
+// exons, sorted by start offset.
+func (g *Gene) Introns() []Interval {
+	var introns []Interval
+	for i := 1; i < len(g.Exons); i++ {
+		introns = append(introns, Interval{Start: g.Exons[i-1].End, End: g.Exons[i].Start})
+	}
+	return introns
+}
+
+// CDSExons returns the coding parts of the exons, sorted by start offset.
+func (g *Gene) CDSExons() []Interval {
+	var ivs []Interval
+	for _, exon := range g.Exons {
+		iv := exon
+		if g.CDS.Start > iv.Start {
+			iv.Start = g.CDS.Start
+		}
+		if g.CDS.End < iv.End {
+			iv.End = g.CDS.End
+		}
+		if iv.Len() > 0 {
+			ivs = append(ivs, iv)
+		}
+	}
+	return ivs
+}
+
+// Transcript returns the spliced transcript of the gene in the DNA sequence;
+// the exons joined in order of transcription, on the strand of the gene.
+func (g *Gene) Transcript(dna seq.DNA) (seq.DNA, error) {
+	return g.splice(dna, g.Exons)
+}
+
+// MRNA returns the spliced mRNA of the gene in the DNA sequence.
+func (g *Gene) MRNA(dna seq.DNA) (seq.RNA, error) {
+	t, err := g.splice(dna, g.Exons)
+	if err != nil {
+		return nil, err
+	}
+	return t.Transcribe(), nil
+}
+
+// CodingSeq returns the coding sequence of the gene in the DNA sequence; the
+// coding parts of its exons joined in order of transcription, on the strand of
+// the gene.
+func (g *Gene) CodingSeq(dna seq.DNA) (seq.DNA, error) {
+	if g.CDS.Len() == 0 {
+		return nil, fmt.Errorf("gene.Gene.CodingSeq: %s: non-coding gene", g.ID)
+	}
+	return g.splice(dna, g.CDSExons())
+}
+
+// Protein translates the coding sequence of the gene in the DNA sequence into a
+// protein using the provided genetic code, or the standard code if nil.
+// Translation stops at the first stop codon, and the first codon is translated
+// as methionine if it is a start codon of the genetic code.
+func (g *Gene) Protein(dna seq.DNA, code *seq.GeneticCode) (seq.Protein, error) {
+	cds, err := g.CodingSeq(dna)
+	if err != nil {
+		return nil, err
+	}
+	return cds.TranslateWith(seq.TranslateOptions{Code: code, Start: true})
+}
+
+// splice joins the intervals of the DNA sequence on the strand of the gene.
+func (g *Gene) splice(dna seq.DNA, ivs []Interval) (seq.DNA, error) {
+	var buf seq.DNA
+	for _, iv := range ivs {
+		if iv.End > len(dna) {
+			return nil, fmt.Errorf("gene.Gene: %s: interval %v out of bounds for sequence of length %d", g.ID, iv, len(dna))
+		}
+		buf = append(buf, dna[iv.Start:iv.End]...)
+	}
+	if g.Strand == '-' {
+		return buf.RevComp(), nil
+	}
+	return buf, nil
+}
+
+// A SpliceSiteError reports an intron which lacks the GT...AG consensus of
+// canonical splice sites.
+type SpliceSiteError struct {
+	// ID of the gene.
+	ID string
+	// Intron, in forward strand coordinates.
+	Intron Interval
+	// Donor (5') and acceptor (3') dinucleotides of the intron, on the strand of
+	// the gene.
+	Donor, Acceptor string
+}
+
+func (e *SpliceSiteError) Error() string {
+	return fmt.Sprintf("gene: %s: intron %v has splice sites %s...%s; expected GT...AG", e.ID, e.Intron, e.Donor, e.Acceptor)
+}
+
+// CheckSpliceSites checks that each intron of the gene in the DNA sequence
+// starts with the donor dinucleotide GT and ends with the acceptor dinucleotide
+// AG on the strand of the gene, regardless of case. The first intron which
+// does not is reported as a *SpliceSiteError.
+func (g *Gene) CheckSpliceSites(dna seq.DNA) error {
+	for _, intron := range g.Introns() {
+		s, err := g.splice(dna, []Interval{intron})
+		if err != nil {
+			return err
+		}
+		n := 2
+		if len(s) < n {
+			n = len(s)
+		}
+		donor, acceptor := upper(s[:n]), upper(s[len(s)-n:])
+		if len(s) < 4 || donor != "GT" || acceptor != "AG" {
+			return &SpliceSiteError{ID: g.ID, Intron: intron, Donor: donor, Acceptor: acceptor}
+		}
+	}
+	return nil
+}
+
+// upper returns the uppercase form of the nucleotides.
+func upper(s seq.DNA) string {
+	buf := make([]byte, len(s))
+	for i, c := range s {
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		buf[i] = c
+	}
+	return string(buf)
+}
+
+// Locate locates each of the provided subsequences (e.g. introns) in the DNA
+// sequence, using the first occurrence which does not overlap the subsequences
+// located before it. Unlike removal by string replacement, each subsequence is
+// thereby mapped to exactly one interval.
+func Locate(dna string, subs []string) ([]Interval, error) {
+	var ivs []Interval
+	for _, sub := range subs {
+		if len(sub) == 0 {
+			return nil, errors.New("gene.Locate: empty subsequence")
+		}
+		found := false
+		for start := 0; start+len(sub) <= len(dna); start++ {
+			iv := Interval{Start: start, End: start + len(sub)}
+			if dna[iv.Start:iv.End] != sub || overlaps(iv, ivs) {
+				continue
+			}
+			ivs = append(ivs, iv)
+			found = true
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("gene.Locate: unable to locate %q", sub)
+		}
+	}
+	return ivs, nil
+}
+
+// overlaps reports whether the interval overlaps any of the provided intervals.
+func overlaps(iv Interval, ivs []Interval) bool {
+	for _, other := range ivs {
+		if iv.Start < other.End && other.Start < iv.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package gene
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/playground/rosalind/gff"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+// fwd is a sequence with a gene on the forward strand, which consists of two
+// exons separated by a GT...AG intron.
+const fwd = "CCATGAAAGTCCCCAGCCCTAAGG"
+
+// mirror returns the interval on the reverse strand of a sequence of length n.
+func mirror(iv Interval, n int) Interval {
+	return Interval{Start: n - iv.End, End: n - iv.Start}
+}
+
+func TestGene(t *testing.T) {
+	rev := string(seq.DNA(fwd).RevComp())
+	n := len(fwd)
+	exons := []Interval{{0, 8}, {16, 24}}
+	cds := Interval{2, 22}
+	golden := []struct {
+		dna        string
+		strand     byte
+		exons      []Interval
+		cds        Interval
+		introns    []Interval
+		mrna       string
+		codingSeq  string
+		protein    string
+		spliceSite bool
+	}{
+		// i=0
+		{dna: fwd, strand: '+', exons: exons, cds: cds, introns: []Interval{{8, 16}}, mrna: "CCAUGAAACCCUAAGG", codingSeq: "ATGAAACCCTAA", protein: "MKP", spliceSite: true},
+		// i=1
+		{dna: rev, strand: '-', exons: []Interval{mirror(exons[1], n), mirror(exons[0], n)}, cds: mirror(cds, n), introns: []Interval{{8, 16}}, mrna: "CCAUGAAACCCUAAGG", codingSeq: "ATGAAACCCTAA", protein: "MKP", spliceSite: true},
+		// i=2 The intron lacks the GT...AG consensus on the reverse strand.
+		{dna: fwd, strand: '-', exons: exons, cds: cds, introns: []Interval{{8, 16}}, mrna: "CCUUAGGGUUUCAUGG", codingSeq: "TTAGGGTTTCAT", protein: "LGFH", spliceSite: false},
+	}
+	for i, g := range golden {
+		gene, err := New("g1", "chr1", g.strand, g.exons, g.cds)
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if got := gene.Introns(); !reflect.DeepEqual(got, g.introns) {
+			t.Errorf("i=%d: introns mismatch; expected %v, got %v.", i, g.introns, got)
+		}
+		dna := seq.DNA(g.dna)
+		mrna, err := gene.MRNA(dna)
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if got := mrna.String(); got != g.mrna {
+			t.Errorf("i=%d: mRNA mismatch; expected %v, got %v.", i, g.mrna, got)
+		}
+		codingSeq, err := gene.CodingSeq(dna)
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if got := codingSeq.String(); got != g.codingSeq {
+			t.Errorf("i=%d: CDS mismatch; expected %v, got %v.", i, g.codingSeq, got)
+		}
+		protein, err := gene.Protein(dna, nil)
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if got := protein.String(); got != g.protein {
+			t.Errorf("i=%d: protein mismatch; expected %v, got %v.", i, g.protein, got)
+		}
+		err = gene.CheckSpliceSites(dna)
+		if got := err == nil; got != g.spliceSite {
+			t.Errorf("i=%d: splice site mismatch; expected %v, got %v.", i, g.spliceSite, err)
+		}
+		if _, ok := err.(*SpliceSiteError); err != nil && !ok {
+			t.Errorf("i=%d: expected *SpliceSiteError, got %T.", i, err)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	golden := []struct {
+		strand byte
+		exons  []Interval
+		cds    Interval
+		valid  bool
+	}{
+		// i=0 Exons are sorted.
+		{strand: '+', exons: []Interval{{16, 24}, {0, 8}}, cds: Interval{2, 22}, valid: true},
+		// i=1 Non-coding gene.
+		{strand: '-', exons: []Interval{{0, 8}}, valid: true},
+		// i=2
+		{strand: '.', exons: []Interval{{0, 8}}, valid: false},
+		// i=3
+		{strand: '+', valid: false},
+		// i=4
+		{strand: '+', exons: []Interval{{0, 8}, {6, 10}}, valid: false},
+		// i=5
+		{strand: '+', exons: []Interval{{4, 4}}, valid: false},
+		// i=6 The coding region starts within an intron.
+		{strand: '+', exons: []Interval{{0, 8}, {16, 24}}, cds: Interval{10, 22}, valid: false},
+	}
+	for i, g := range golden {
+		_, err := New("g1", "chr1", g.strand, g.exons, g.cds)
+		if got := err == nil; got != g.valid {
+			t.Errorf("i=%d: expected valid %v, got %v.", i, g.valid, err)
+		}
+	}
+}
+
+func TestLocate(t *testing.T) {
+	golden := []struct {
+		dna  string
+		subs []string
+		want []Interval
+		err  bool
+	}{
+		// i=0 Each subsequence is located once, although it occurs twice.
+		{dna: "AAGTAGCCGTAG", subs: []string{"GTAG"}, want: []Interval{{2, 6}}},
+		// i=1 Identical subsequences are located at distinct intervals.
+		{dna: "AAGTAGCCGTAG", subs: []string{"GTAG", "GTAG"}, want: []Interval{{2, 6}, {8, 12}}},
+		// i=2
+		{dna: "AAGTAGCCGTAG", subs: []string{"GTAG", "GTAG", "GTAG"}, err: true},
+		// i=3
+		{dna: "AAGTAGCCGTAG", subs: []string{""}, err: true},
+	}
+	for i, g := range golden {
+		got, err := Locate(g.dna, g.subs)
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error, got nil.", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
+
+func TestGFF3(t *testing.T) {
+	golden := []*Gene{
+		// i=0
+		{ID: "g1", SeqID: "chr1", Strand: '+', Exons: []Interval{{0, 9}, {16, 24}}, CDS: Interval{2, 22}},
+		// i=1
+		{ID: "g2", SeqID: "chr1", Strand: '-', Exons: []Interval{{0, 9}, {16, 24}}, CDS: Interval{2, 22}},
+		// i=2
+		{ID: "g3", SeqID: "chr2", Strand: '+', Exons: []Interval{{0, 9}}},
+	}
+	wantPhases := [][]int{{0, 2}, {0, 0}, nil}
+	for i, g := range golden {
+		var phases []int
+		for _, f := range g.Features() {
+			if f.Type == "CDS" {
+				phases = append(phases, f.Phase)
+			}
+		}
+		if !reflect.DeepEqual(phases, wantPhases[i]) {
+			t.Errorf("i=%d: phase mismatch; expected %v, got %v.", i, wantPhases[i], phases)
+		}
+	}
+	buf := new(bytes.Buffer)
+	gw := gff.NewWriter(buf)
+	if err := WriteGFF3(gw, golden); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	fs, err := gff.ReadAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := FromFeatures(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, golden) {
+		t.Errorf("round trip mismatch; expected %v, got %v.", golden, got)
+	}
+}
@@ -0,0 +1,149 @@
+package gene
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/gff"
+)
+
+// Features returns the GFF3 features of the gene; a gene feature, an mRNA
+// feature with the ID of the gene followed by ".mRNA", and its exon and CDS
+// features. The phase of each CDS feature is the number of bases of its first
+// incomplete codon.
+func (g *Gene) Features() []*gff.Feature {
+	span := g.Span()
+	mrnaID := g.ID + ".mRNA"
+	fs := []*gff.Feature{
+		g.feature("gene", span, -1, gff.Attr{Tag: "ID", Value: g.ID}),
+		g.feature("mRNA", span, -1, gff.Attr{Tag: "ID", Value: mrnaID}, gff.Attr{Tag: "Parent", Value: g.ID}),
+	}
+	for i, exon := range g.Exons {
+		id := g.ID + ".exon" + strconv.Itoa(i+1)
+		fs = append(fs, g.feature("exon", exon, -1, gff.Attr{Tag: "ID", Value: id}, gff.Attr{Tag: "Parent", Value: mrnaID}))
+	}
+	cdss := g.CDSExons()
+	phases := make([]int, len(cdss))
+	n := 0
+	for i := range cdss {
+		// Phases are assigned in order of transcription.
+		j := i
+		if g.Strand == '-' {
+			j = len(cdss) - 1 - i
+		}
+		phases[j] = (3 - n%3) % 3
+		n += cdss[j].Len()
+	}
+	for i, cds := range cdss {
+		fs = append(fs, g.feature("CDS", cds, phases[i], gff.Attr{Tag: "ID", Value: g.ID + ".cds"}, gff.Attr{Tag: "Parent", Value: mrnaID}))
+	}
+	return fs
+}
+
+// feature returns a feature of the gene of the given type and interval.
+func (g *Gene) feature(typ string, iv Interval, phase int, attrs ...gff.Attr) *gff.Feature {
+	return &gff.Feature{
+		SeqID:  g.SeqID,
+		Source: "gene",
+		Type:   typ,
+		Start:  iv.Start + 1,
+		End:    iv.End,
+		Strand: g.Strand,
+		Phase:  phase,
+		Attrs:  attrs,
+	}
+}
+
+// WriteGFF3 writes the features of the genes as GFF3.
+func WriteGFF3(gw *gff.Writer, genes []*Gene) error {
+	for _, g := range genes {
+		for _, f := range g.Features() {
+			if err := gw.Write(f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FromFeatures returns the gene models of the GFF3 features, in order of
+// occurrence. Exon and CDS features are grouped into one gene model per
+// transcript by their Parent attribute, and the ID of each gene model is the
+// parent of its transcript feature (e.g. the gene of an mRNA), if present, or
+// the ID of the transcript otherwise. The exons of transcripts without exon
+// features are those of their CDS features.
+func FromFeatures(fs []*gff.Feature) ([]*Gene, error) {
+	// parents maps from transcript ID to the parent of the transcript.
+	parents := make(map[string]string)
+	for _, f := range fs {
+		if id := f.Attr("ID"); len(id) > 0 {
+			if parent := f.Attr("Parent"); len(parent) > 0 {
+				parents[id] = parent
+			}
+		}
+	}
+	type transcript struct {
+		exons, cdss []*gff.Feature
+	}
+	var ids []string
+	ts := make(map[string]*transcript)
+	for _, f := range fs {
+		if f.Type != "exon" && f.Type != "CDS" {
+			continue
+		}
+		parent := f.Attr("Parent")
+		if len(parent) == 0 {
+			return nil, fmt.Errorf("gene.FromFeatures: %s feature at %s:%d-%d without parent", f.Type, f.SeqID, f.Start, f.End)
+		}
+		for _, id := range strings.Split(parent, ",") {
+			t, ok := ts[id]
+			if !ok {
+				t = new(transcript)
+				ts[id] = t
+				ids = append(ids, id)
+			}
+			if f.Type == "exon" {
+				t.exons = append(t.exons, f)
+			} else {
+				t.cdss = append(t.cdss, f)
+			}
+		}
+	}
+	var genes []*Gene
+	for _, id := range ids {
+		t := ts[id]
+		exons := t.exons
+		if len(exons) == 0 {
+			exons = t.cdss
+		}
+		geneID, seqID, strand := id, exons[0].SeqID, exons[0].Strand
+		if parent, ok := parents[id]; ok {
+			geneID = parent
+		}
+		for _, f := range append(exons, t.cdss...) {
+			if f.SeqID != seqID || f.Strand != strand {
+				return nil, fmt.Errorf("gene.FromFeatures: %s: features of transcript on different sequences or strands", id)
+			}
+		}
+		var ivs []Interval
+		for _, f := range exons {
+			ivs = append(ivs, Interval{Start: f.Start - 1, End: f.End})
+		}
+		var cds Interval
+		for i, f := range t.cdss {
+			if i == 0 || f.Start-1 < cds.Start {
+				cds.Start = f.Start - 1
+			}
+			if i == 0 || f.End > cds.End {
+				cds.End = f.End
+			}
+		}
+		g, err := New(geneID, seqID, strand, ivs, cds)
+		if err != nil {
+			return nil, err
+		}
+		genes = append(genes, g)
+	}
+	return genes, nil
+}
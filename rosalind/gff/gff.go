@@ -0,0 +1,147 @@
+// Package gff implements reading and writing of genome annotations in the GFF3
+// file format.
+//
+// See https://github.com/The-Sequence-Ontology/Specifications/blob/master/gff3.md
+package gff
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A Feature is a genomic feature; one line of a GFF3 file.
+type Feature struct {
+	// ID of the landmark (e.g. chromosome) of the feature.
+	SeqID string
+	// Source of the feature, such as the program which predicted it.
+	Source string
+	// Type of the feature, as a Sequence Ontology term; e.g. "gene", "mRNA",
+	// "exon" or "CDS".
+	Type string
+	// Start and end position of the feature, starting at 1 and inclusive.
+	Start, End int
+	// Score of the feature, if HasScore is set.
+	Score float64
+	// HasScore specifies whether the score of the feature is known.
+	HasScore bool
+	// Strand of the feature; '+', '-', '.' if unstranded, or '?' if unknown.
+	Strand byte
+	// Phase of CDS features; the number of bases to skip to reach the first
+	// complete codon, or -1 if not applicable.
+	Phase int
+	// Attributes of the feature, in order of occurrence.
+	Attrs []Attr
+}
+
+// An Attr is a tag-value pair of a feature.
+type Attr struct {
+	// Tag of the attribute; e.g. "ID" or "Parent".
+	Tag string
+	// Value of the attribute, with escape sequences decoded.
+	Value string
+}
+
+// Attr returns the value of the first attribute of the feature with the
+// provided tag, or the empty string if not present.
+func (f *Feature) Attr(tag string) string {
+	for _, attr := range f.Attrs {
+		if attr.Tag == tag {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// String returns the feature as a line in GFF3 format, excluding the line
+// break.
+func (f *Feature) String() string {
+	score := "."
+	if f.HasScore {
+		score = strconv.FormatFloat(f.Score, 'g', -1, 64)
+	}
+	strand := "."
+	if f.Strand != 0 {
+		strand = string(f.Strand)
+	}
+	phase := "."
+	if f.Phase >= 0 {
+		phase = strconv.Itoa(f.Phase)
+	}
+	attrs := "."
+	if len(f.Attrs) > 0 {
+		var strs []string
+		for _, attr := range f.Attrs {
+			strs = append(strs, escape(attr.Tag)+"="+escape(attr.Value))
+		}
+		attrs = strings.Join(strs, ";")
+	}
+	source := f.Source
+	if len(source) == 0 {
+		source = "."
+	}
+	cols := []string{escape(f.SeqID), escape(source), escape(f.Type), strconv.Itoa(f.Start), strconv.Itoa(f.End), score, strand, phase, attrs}
+	return strings.Join(cols, "\t")
+}
+
+// escape percent-encodes the characters of s which have special meaning in
+// GFF3 columns and attributes, and control characters.
+func escape(s string) string {
+	buf := new(bytes.Buffer)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c == 0x7F || strings.IndexByte("%;=&,", c) != -1 {
+			fmt.Fprintf(buf, "%%%02X", c)
+			continue
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// A Writer writes features to a GFF3 file.
+type Writer struct {
+	// Underlying writer.
+	w *bufio.Writer
+	// Specifies whether the version directive has been written.
+	header bool
+}
+
+// NewWriter returns a new GFF3 writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write writes the feature to the GFF3 file. The "##gff-version 3" directive
+// is written before the first feature.
+func (gw *Writer) Write(f *Feature) error {
+	if err := gw.writeHeader(); err != nil {
+		return err
+	}
+	if _, err := gw.w.WriteString(f.String() + "\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeHeader writes the version directive, unless already written.
+func (gw *Writer) writeHeader() error {
+	if gw.header {
+		return nil
+	}
+	gw.header = true
+	_, err := gw.w.WriteString("##gff-version 3\n")
+	return err
+}
+
+// Flush writes any buffered data to the underlying writer. A GFF3 file without
+// features consists of the version directive only.
+func (gw *Writer) Flush() error {
+	if err := gw.writeHeader(); err != nil {
+		return err
+	}
+	return gw.w.Flush()
+}
@@ -0,0 +1,88 @@
+package gff
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFeatureString(t *testing.T) {
+	golden := []struct {
+		f    *Feature
+		want string
+	}{
+		// i=0
+		{
+			f:    &Feature{SeqID: "chr1", Source: "orf", Type: "ORF", Start: 3, End: 14, Strand: '+', Phase: -1, Attrs: []Attr{{Tag: "ID", Value: "orf1"}}},
+			want: "chr1\torf\tORF\t3\t14\t.\t+\t.\tID=orf1",
+		},
+		// i=1
+		{
+			f:    &Feature{SeqID: "chr1", Type: "CDS", Start: 1, End: 9, Score: 0.5, HasScore: true, Strand: '-', Phase: 2},
+			want: "chr1\t.\tCDS\t1\t9\t0.5\t-\t2\t.",
+		},
+		// i=2
+		{
+			f:    &Feature{SeqID: "chr 1", Type: "gene", Start: 1, End: 2, Phase: -1, Attrs: []Attr{{Tag: "Note", Value: "a=b;c,d%\t"}}},
+			want: "chr 1\t.\tgene\t1\t2\t.\t.\t.\tNote=a%3Db%3Bc%2Cd%25%09",
+		},
+	}
+	for i, g := range golden {
+		if got := g.f.String(); got != g.want {
+			t.Errorf("i=%d: expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	gw := NewWriter(buf)
+	if err := gw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "##gff-version 3\n"; got != want {
+		t.Errorf("expected %q, got %q.", want, got)
+	}
+}
+
+func TestReader(t *testing.T) {
+	const input = "##gff-version 3\n" +
+		"# comment\n" +
+		"\n" +
+		"chr%201\t.\tgene\t1\t2\t.\t.\t.\tID=g1;Note=a%3Db%3Bc%2Cd%25%09\n" +
+		"chr1\torf\tCDS\t3\t14\t0.5\t-\t2\tID=cds1;Parent=g1\n" +
+		"##FASTA\n" +
+		">chr1\n" +
+		"ACGT\n"
+	want := []*Feature{
+		{SeqID: "chr 1", Type: "gene", Start: 1, End: 2, Phase: -1, Attrs: []Attr{{Tag: "ID", Value: "g1"}, {Tag: "Note", Value: "a=b;c,d%\t"}}},
+		{SeqID: "chr1", Source: "orf", Type: "CDS", Start: 3, End: 14, Score: 0.5, HasScore: true, Strand: '-', Phase: 2, Attrs: []Attr{{Tag: "ID", Value: "cds1"}, {Tag: "Parent", Value: "g1"}}},
+	}
+	got, err := ReadAll(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v.", want, got)
+	}
+	invalid := []string{
+		// i=0
+		"chr1\t.\tgene\t1\t2\t.\t.\t.\n",
+		// i=1
+		"chr1\t.\tgene\t3\t2\t.\t.\t.\t.\n",
+		// i=2
+		"chr1\t.\tgene\t1\t2\t.\tx\t.\t.\n",
+		// i=3
+		"chr1\t.\tCDS\t1\t2\t.\t+\t.\t.\n",
+		// i=4
+		"chr1\t.\tgene\t1\t2\t.\t+\t.\tID\n",
+		// i=5
+		"chr1\t.\tgene\t1\t2\t.\t+\t.\tID=%zz\n",
+	}
+	for i, input := range invalid {
+		if _, err := ReadAll(strings.NewReader(input)); err == nil {
+			t.Errorf("i=%d: expected error, got nil.", i)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package gff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// A Reader reads features from a GFF3 file, one feature at a time.
+//
+// Blank lines, comments and directives are skipped, and reading stops at the
+// "##FASTA" directive which starts an embedded FASTA section.
+type Reader struct {
+	// Underlying scanner.
+	s *bufio.Scanner
+	// Current line number.
+	line int
+	// Sticky error.
+	err error
+}
+
+// NewReader returns a new GFF3 reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+	return &Reader{s: s}
+}
+
+// Read reads and returns the next feature. At the end of the file Read returns
+// io.EOF.
+func (gr *Reader) Read() (*Feature, error) {
+	if gr.err != nil {
+		return nil, gr.err
+	}
+	f, err := gr.read()
+	if err != nil {
+		gr.err = err
+		return nil, err
+	}
+	return f, nil
+}
+
+// read reads and returns the next feature.
+func (gr *Reader) read() (*Feature, error) {
+	for gr.s.Scan() {
+		gr.line++
+		line := strings.TrimRight(gr.s.Text(), "\r")
+		switch {
+		case strings.HasPrefix(line, "##FASTA"):
+			return nil, io.EOF
+		case len(strings.TrimSpace(line)) == 0 || line[0] == '#':
+			continue
+		}
+		return gr.parse(line)
+	}
+	if err := gr.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// parse parses the feature line.
+func (gr *Reader) parse(line string) (*Feature, error) {
+	cols := strings.Split(line, "\t")
+	if len(cols) != 9 {
+		return nil, gr.errorf("invalid number of columns; expected 9, got %d", len(cols))
+	}
+	var err error
+	f := &Feature{Phase: -1}
+	if f.SeqID, err = unescape(cols[0]); err != nil {
+		return nil, gr.errorf("invalid seqid; %v", err)
+	}
+	if cols[1] != "." {
+		if f.Source, err = unescape(cols[1]); err != nil {
+			return nil, gr.errorf("invalid source; %v", err)
+		}
+	}
+	if f.Type, err = unescape(cols[2]); err != nil {
+		return nil, gr.errorf("invalid type; %v", err)
+	}
+	if f.Start, err = strconv.Atoi(cols[3]); err != nil {
+		return nil, gr.errorf("invalid start; %v", err)
+	}
+	if f.End, err = strconv.Atoi(cols[4]); err != nil {
+		return nil, gr.errorf("invalid end; %v", err)
+	}
+	if f.Start < 1 || f.End < f.Start {
+		return nil, gr.errorf("invalid range %d-%d", f.Start, f.End)
+	}
+	if cols[5] != "." {
+		if f.Score, err = strconv.ParseFloat(cols[5], 64); err != nil {
+			return nil, gr.errorf("invalid score; %v", err)
+		}
+		f.HasScore = true
+	}
+	switch cols[6] {
+	case "+", "-", "?":
+		f.Strand = cols[6][0]
+	case ".":
+	default:
+		return nil, gr.errorf("invalid strand %q", cols[6])
+	}
+	switch cols[7] {
+	case "0", "1", "2":
+		f.Phase = int(cols[7][0] - '0')
+	case ".":
+	default:
+		return nil, gr.errorf("invalid phase %q", cols[7])
+	}
+	if f.Type == "CDS" && f.Phase == -1 {
+		return nil, gr.errorf("missing phase of CDS feature")
+	}
+	if cols[8] != "." {
+		for _, field := range strings.Split(cols[8], ";") {
+			if len(strings.TrimSpace(field)) == 0 {
+				continue
+			}
+			i := strings.IndexByte(field, '=')
+			if i == -1 {
+				return nil, gr.errorf("invalid attribute %q; missing '='", field)
+			}
+			tag, err := unescape(strings.TrimSpace(field[:i]))
+			if err != nil {
+				return nil, gr.errorf("invalid attribute tag; %v", err)
+			}
+			value, err := unescape(field[i+1:])
+			if err != nil {
+				return nil, gr.errorf("invalid value of attribute %q; %v", tag, err)
+			}
+			f.Attrs = append(f.Attrs, Attr{Tag: tag, Value: value})
+		}
+	}
+	return f, nil
+}
+
+// unescape decodes the percent-encoded characters of s.
+func unescape(s string) (string, error) {
+	if strings.IndexByte(s, '%') == -1 {
+		return s, nil
+	}
+	return url.PathUnescape(s)
+}
+
+// Line returns the line number of the last feature read.
+func (gr *Reader) Line() int {
+	return gr.line
+}
+
+// errorf returns an error at the current line.
+func (gr *Reader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("gff.Reader.Read: line %d: %s", gr.line, fmt.Sprintf(format, args...))
+}
+
+// ReadAll reads all remaining features from r.
+func ReadAll(r io.Reader) ([]*Feature, error) {
+	gr := NewReader(r)
+	var fs []*Feature
+	for {
+		f, err := gr.Read()
+		if err != nil {
+			if err == io.EOF {
+				return fs, nil
+			}
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+}
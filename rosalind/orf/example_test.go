@@ -0,0 +1,49 @@
+package orf_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/gff"
+	"github.com/mewmew/playground/rosalind/orf"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+func ExampleFind() {
+	dna, err := seq.ParseDNA("AGCCATGTAGCTAACTCAGGTTACATGGGGATGACCCCGCGACTTGGATTAGAGTCTCTTTTGGAATAAGCCTGAATGATCCGAGTAGCATCTCAG")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, o := range orf.Find(dna, orf.Options{Nested: true, MinLen: 30}) {
+		fmt.Printf("%c%d %d-%d %s\n", o.Strand, o.Frame, o.Start+1, o.End, o.Protein)
+	}
+	// Output:
+	// -3 11-91 MLLGSFRLIPKETLIQVAGSSPCNLS
+	// +1 25-69 MGMTPRLGLESLLE
+	// +1 31-69 MTPRLGLESLLE
+}
+
+func ExampleWriteGFF3() {
+	dna, err := seq.ParseDNA("CCATGAAACCCTAGGGTTACATCC")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	orfs := orf.Find(dna, orf.Options{})
+	gw := gff.NewWriter(os.Stdout)
+	if err := orf.WriteGFF3(gw, "chr1", orfs); err != nil {
+		log.Fatalln(err)
+	}
+	if err := gw.Flush(); err != nil {
+		log.Fatalln(err)
+	}
+	if err := orf.WriteBED(os.Stdout, "chr1", orfs); err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// ##gff-version 3
+	// chr1	orf	ORF	3	14	.	+	.	ID=chr1_orf1;frame=+3;protein=MKP
+	// chr1	orf	ORF	17	22	.	-	.	ID=chr1_orf2;frame=-3;protein=M
+	// chr1	2	14	chr1_orf1	0	+
+	// chr1	16	22	chr1_orf2	0	-
+}
@@ -0,0 +1,189 @@
+// Package orf locates open reading frames of DNA sequences.
+//
+// An open reading frame (ORF) starts with a start codon and ends with an
+// in-frame stop codon, without any other stop codons in between. Both strands
+// of the sequence are searched, in each of the three reading frames.
+package orf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/mewmew/playground/rosalind/gff"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+// An ORF is an open reading frame of a DNA sequence.
+type ORF struct {
+	// Strand of the ORF; '+' for the sequence and '-' for its reverse
+	// complement.
+	Strand byte
+	// Reading frame of the ORF on its strand, from 1 to 3; see seq.DNA.Frames.
+	Frame int
+	// Start and end offsets of the ORF in the sequence, regardless of strand,
+	// where the end offset is exclusive and includes the stop codon.
+	Start, End int
+	// Nucleotide sequence of the ORF on its strand, including the stop codon.
+	Seq seq.DNA
+	// Protein encoded by the ORF, excluding the stop codon. Codons containing
+	// ambiguity codes translate as described by seq.GeneticCode.Translate, and
+	// invalid codons as 'X'.
+	Protein seq.Protein
+}
+
+// Options specify how ORFs are located.
+type Options struct {
+	// Code is the genetic code used to identify start and stop codons and to
+	// translate ORFs; the standard code if nil.
+	Code *seq.GeneticCode
+	// AltStarts specifies whether the alternative start codons of the genetic
+	// code (e.g. GTG and TTG in bacteria) start ORFs, rather than only ATG.
+	// Alternative start codons translate as methionine.
+	AltStarts bool
+	// Nested specifies whether each in-frame start codon upstream of a stop
+	// codon starts an ORF, rather than only the first one. Nested ORFs share
+	// their stop codon with the longest ORF.
+	Nested bool
+	// MinLen specifies the minimum length of ORFs in nucleotides, including the
+	// stop codon.
+	MinLen int
+}
+
+// Find locates the ORFs of the DNA sequence on both strands, sorted by start
+// offset, end offset and strand. Codons containing ambiguity codes neither
+// start nor stop ORFs.
+func Find(dna seq.DNA, opts Options) []*ORF {
+	code := opts.Code
+	if code == nil {
+		code = seq.StandardCode()
+	}
+	var orfs []*ORF
+	for _, strand := range []byte{'+', '-'} {
+		s := dna
+		if strand == '-' {
+			s = dna.RevComp()
+		}
+		for frame := 0; frame < 3; frame++ {
+			// Offsets of the start codons since the last stop codon.
+			var starts []int
+			for i := frame; i+3 <= len(s); i += 3 {
+				codon := s[i : i+3]
+				switch {
+				case code.IsStop(codon):
+					for j, start := range starts {
+						if j > 0 && !opts.Nested {
+							break
+						}
+						end := i + 3
+						if end-start < opts.MinLen {
+							// Nested ORFs are shorter still.
+							break
+						}
+						orfs = append(orfs, newORF(s, strand, start, end, code))
+					}
+					starts = starts[:0]
+				case isStart(codon, code, opts.AltStarts):
+					starts = append(starts, i)
+				}
+			}
+		}
+	}
+	sort.Slice(orfs, func(i, j int) bool {
+		a, b := orfs[i], orfs[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.End != b.End {
+			return a.End < b.End
+		}
+		return a.Strand < b.Strand
+	})
+	return orfs
+}
+
+// isStart reports whether the codon is a start codon; ATG, or any start codon
+// of the genetic code if altStarts is set.
+func isStart(codon []byte, code *seq.GeneticCode, altStarts bool) bool {
+	if altStarts {
+		return code.IsStart(codon)
+	}
+	return upper(codon[0]) == 'A' && upper(codon[1]) == 'T' && upper(codon[2]) == 'G'
+}
+
+// newORF returns the ORF spanning s[start:end] on the given strand of the
+// sequence, where s is the sequence or its reverse complement.
+func newORF(s seq.DNA, strand byte, start, end int, code *seq.GeneticCode) *ORF {
+	orf := &ORF{
+		Strand: strand,
+		Frame:  start%3 + 1,
+		Start:  start,
+		End:    end,
+		Seq:    append(seq.DNA(nil), s[start:end]...),
+	}
+	if strand == '-' {
+		orf.Start, orf.End = len(s)-end, len(s)-start
+	}
+	// Translate all codons but the stop codon; the start codon translates as
+	// methionine.
+	orf.Protein = append(orf.Protein, 'M')
+	for i := start + 3; i+3 < end; i += 3 {
+		amino, ok := code.Translate(s[i : i+3])
+		if !ok {
+			amino = 'X'
+		}
+		orf.Protein = append(orf.Protein, amino)
+	}
+	return orf
+}
+
+// upper returns the uppercase form of the ASCII letter c.
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - ('a' - 'A')
+	}
+	return c
+}
+
+// Feature returns the ORF as a GFF3 feature of the sequence with the provided
+// ID.
+func (orf *ORF) Feature(seqID, id string) *gff.Feature {
+	return &gff.Feature{
+		SeqID:  seqID,
+		Source: "orf",
+		Type:   "ORF",
+		Start:  orf.Start + 1,
+		End:    orf.End,
+		Strand: orf.Strand,
+		Phase:  -1,
+		Attrs: []gff.Attr{
+			{Tag: "ID", Value: id},
+			{Tag: "frame", Value: fmt.Sprintf("%c%d", orf.Strand, orf.Frame)},
+			{Tag: "protein", Value: orf.Protein.String()},
+		},
+	}
+}
+
+// WriteGFF3 writes the ORFs of the sequence with the provided ID as GFF3
+// features. The ID of each ORF is the sequence ID followed by "_orf" and its
+// index, starting at 1.
+func WriteGFF3(gw *gff.Writer, seqID string, orfs []*ORF) error {
+	for i, orf := range orfs {
+		if err := gw.Write(orf.Feature(seqID, seqID+"_orf"+strconv.Itoa(i+1))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteBED writes the ORFs of the sequence with the provided ID to w in BED6
+// format, using the same ORF IDs as WriteGFF3. The score of each ORF is 0.
+func WriteBED(w io.Writer, seqID string, orfs []*ORF) error {
+	for i, orf := range orfs {
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%d\t%s_orf%d\t0\t%c\n", seqID, orf.Start, orf.End, seqID, i+1, orf.Strand); err != nil {
+			return err
+		}
+	}
+	return nil
+}
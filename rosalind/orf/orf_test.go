@@ -0,0 +1,84 @@
+package orf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+// sample is the sample dataset of the Rosalind problem ORF.
+const sample = "AGCCATGTAGCTAACTCAGGTTACATGGGGATGACCCCGCGACTTGGATTAGAGTCTCTTTTGGAATAAGCCTGAATGATCCGAGTAGCATCTCAG"
+
+func TestFind(t *testing.T) {
+	code2, err := seq.Code(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code11, err := seq.Code(11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := []struct {
+		dna  string
+		opts Options
+		want []string
+	}{
+		// i=0
+		{dna: sample, opts: Options{Nested: true}, want: []string{"M", "MLLGSFRLIPKETLIQVAGSSPCNLS", "M", "MGMTPRLGLESLLE", "MTPRLGLESLLE"}},
+		// i=1
+		{dna: sample, opts: Options{}, want: []string{"M", "MLLGSFRLIPKETLIQVAGSSPCNLS", "M", "MGMTPRLGLESLLE"}},
+		// i=2
+		{dna: sample, opts: Options{Nested: true, MinLen: 42}, want: []string{"MLLGSFRLIPKETLIQVAGSSPCNLS", "MGMTPRLGLESLLE"}},
+		// i=3
+		{dna: "ATGCCCTGACCCTAA", opts: Options{}, want: []string{"MP"}},
+		// i=4 TGA codes for tryptophan in vertebrate mitochondria.
+		{dna: "ATGCCCTGACCCTAA", opts: Options{Code: code2}, want: []string{"MPWP"}},
+		// i=5 AGA is a stop codon in vertebrate mitochondria.
+		{dna: "ATGCCCAGACCC", opts: Options{Code: code2}, want: []string{"MP"}},
+		// i=6 GTG is an alternative start codon in bacteria.
+		{dna: "GTGAAATAA", opts: Options{Code: code11}, want: nil},
+		// i=7
+		{dna: "GTGAAATAA", opts: Options{Code: code11, AltStarts: true}, want: []string{"MK"}},
+		// i=8 Lowercase and ambiguous codons.
+		{dna: "atgNNNcgnTAG", opts: Options{}, want: []string{"MXR"}},
+		// i=9 No stop codon.
+		{dna: "ATGAAAAAA", opts: Options{}, want: nil},
+	}
+	for i, g := range golden {
+		dna := seq.DNA(g.dna)
+		var got []string
+		for _, orf := range Find(dna, g.opts) {
+			got = append(got, orf.Protein.String())
+			// The ORF sequence is located at its offsets, on its strand.
+			s := dna[orf.Start:orf.End]
+			if orf.Strand == '-' {
+				s = s.RevComp()
+			}
+			if !bytes.Equal(s, orf.Seq) {
+				t.Errorf("i=%d: expected ORF sequence %q at offsets %d-%d, got %q.", i, orf.Seq, orf.Start, orf.End, s)
+			}
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
+
+func TestFindFrames(t *testing.T) {
+	orfs := Find(seq.DNA(sample), Options{})
+	type pos struct {
+		strand     byte
+		frame      int
+		start, end int
+	}
+	var got []pos
+	for _, orf := range orfs {
+		got = append(got, pos{orf.Strand, orf.Frame, orf.Start, orf.End})
+	}
+	want := []pos{{'+', 2, 4, 10}, {'-', 3, 10, 91}, {'-', 2, 20, 26}, {'+', 1, 24, 69}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v.", want, got)
+	}
+}
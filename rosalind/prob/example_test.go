@@ -0,0 +1,43 @@
+package prob_test
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/mewmew/playground/rosalind/prob"
+)
+
+func ExampleCross() {
+	genotypes, err := prob.Cross("AaBb", "Aabb")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, o := range prob.Phenotypes(genotypes) {
+		fmt.Println(o.Genotype, o.Prob)
+	}
+	// Output:
+	// AB 3/8
+	// Ab 3/8
+	// aB 1/8
+	// ab 1/8
+}
+
+func ExampleIndependentAlleles() {
+	p, err := prob.IndependentAlleles(2, 1)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(p, p.FloatString(3))
+	// Output: 175/256 0.684
+}
+
+func ExampleSimulate() {
+	rng := rand.New(rand.NewSource(1))
+	counts, err := prob.Simulate(rng, 10, 5, 3)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(len(counts), counts[0])
+	// Output: 4 5
+}
@@ -0,0 +1,47 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// HardyWeinberg returns the genotype frequencies of a population in
+// Hardy-Weinberg equilibrium, in which the dominant allele has frequency p;
+// p^2 homozygous dominant, 2pq heterozygous and q^2 homozygous recessive, where
+// q = 1-p.
+func HardyWeinberg(p *big.Rat) (dominant, hetero, recessive *big.Rat, err error) {
+	one := big.NewRat(1, 1)
+	if p.Sign() < 0 || p.Cmp(one) > 0 {
+		return nil, nil, nil, fmt.Errorf("prob.HardyWeinberg: invalid allele frequency %v", p.FloatString(5))
+	}
+	q := new(big.Rat).Sub(one, p)
+	dominant = new(big.Rat).Mul(p, p)
+	hetero = new(big.Rat).Mul(p, q)
+	hetero.Add(hetero, hetero)
+	recessive = new(big.Rat).Mul(q, q)
+	return dominant, hetero, recessive, nil
+}
+
+// AlleleFreq returns the frequency of the dominant allele of a population of
+// diploid organisms; k homozygous dominant, m heterozygous and n homozygous
+// recessive.
+func AlleleFreq(k, m, n int) (*big.Rat, error) {
+	if k < 0 || m < 0 || n < 0 || k+m+n == 0 {
+		return nil, fmt.Errorf("prob.AlleleFreq: invalid population (%d, %d, %d)", k, m, n)
+	}
+	return big.NewRat(2*int64(k)+int64(m), 2*int64(k+m+n)), nil
+}
+
+// CarrierProb returns the probability that a randomly selected organism of a
+// population in Hardy-Weinberg equilibrium carries at least one recessive
+// allele, where the given fraction of the population is homozygous recessive.
+// As the recessive allele has frequency q = sqrt(recessive), the probability is
+// 1 - (1-q)^2.
+func CarrierProb(recessive float64) (float64, error) {
+	if recessive < 0 || recessive > 1 {
+		return 0, fmt.Errorf("prob.CarrierProb: invalid fraction %g", recessive)
+	}
+	q := math.Sqrt(recessive)
+	return 2*q - q*q, nil
+}
@@ -0,0 +1,199 @@
+// Package prob implements probability models of Mendelian inheritance and
+// population genetics.
+//
+// Probabilities of discrete events are computed exactly as rationals, which
+// may be converted to floating-point numbers once all arithmetic is done. The
+// Wright-Fisher model of genetic drift is computed in floating-point, as the
+// denominators of its exact probabilities grow exponentially with the number
+// of generations.
+package prob
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// An Outcome is a genotype or phenotype and its probability.
+type Outcome struct {
+	// Genotype (e.g. "AaBb") or phenotype (e.g. "Ab") of the outcome.
+	Genotype string
+	// Probability of the outcome.
+	Prob *big.Rat
+}
+
+// Cross returns the genotypes of the offspring of two organisms, as in a
+// Punnett square generalized to any number of independently assorting loci.
+// Genotypes are given as pairs of alleles of each locus, where uppercase
+// letters denote dominant alleles and lowercase letters recessive alleles;
+// e.g. "AaBb" for an organism heterozygous in two factors. The outcomes are
+// sorted by genotype, in which the dominant allele of each locus precedes the
+// recessive allele.
+func Cross(a, b string) ([]Outcome, error) {
+	if err := checkGenotypes(a, b); err != nil {
+		return nil, err
+	}
+	half := big.NewRat(1, 2)
+	// dist maps from offspring genotype to probability.
+	dist := map[string]*big.Rat{"": big.NewRat(1, 1)}
+	for i := 0; i < len(a); i += 2 {
+		next := make(map[string]*big.Rat)
+		for genotype, p := range dist {
+			for _, x := range a[i : i+2] {
+				for _, y := range b[i : i+2] {
+					locus := string([]rune{x, y})
+					if y < x {
+						locus = string([]rune{y, x})
+					}
+					q := new(big.Rat).Mul(p, half)
+					q.Mul(q, half)
+					g := genotype + locus
+					if prev, ok := next[g]; ok {
+						q.Add(q, prev)
+					}
+					next[g] = q
+				}
+			}
+		}
+		dist = next
+	}
+	return outcomes(dist), nil
+}
+
+// checkGenotypes validates the genotypes of two organisms to be crossed.
+func checkGenotypes(a, b string) error {
+	if len(a) != len(b) || len(a)%2 != 0 || len(a) == 0 {
+		return fmt.Errorf("prob.Cross: invalid genotypes %q and %q; expected pairs of alleles of the same loci", a, b)
+	}
+	for i := 0; i < len(a); i += 2 {
+		locus := lower(a[i])
+		for _, c := range []byte{a[i], a[i+1], b[i], b[i+1]} {
+			if lower(c) != locus || !('a' <= locus && locus <= 'z') {
+				return fmt.Errorf("prob.Cross: invalid alleles %q and %q of locus %d", a[i:i+2], b[i:i+2], i/2+1)
+			}
+		}
+	}
+	return nil
+}
+
+// Phenotypes returns the phenotypes of the genotype outcomes, sorted by
+// phenotype. The phenotype of each locus is denoted by its dominant allele if
+// present, and by its recessive allele otherwise; e.g. "Ab" for the genotype
+// "AaBb".
+func Phenotypes(genotypes []Outcome) []Outcome {
+	dist := make(map[string]*big.Rat)
+	for _, o := range genotypes {
+		buf := make([]byte, 0, len(o.Genotype)/2)
+		for i := 0; i+1 < len(o.Genotype); i += 2 {
+			// The dominant allele precedes the recessive allele.
+			buf = append(buf, o.Genotype[i])
+		}
+		phenotype := string(buf)
+		if prev, ok := dist[phenotype]; ok {
+			prev.Add(prev, o.Prob)
+			continue
+		}
+		dist[phenotype] = new(big.Rat).Set(o.Prob)
+	}
+	return outcomes(dist)
+}
+
+// outcomes returns the outcomes of the distribution, sorted by genotype.
+func outcomes(dist map[string]*big.Rat) []Outcome {
+	var res []Outcome
+	for genotype, p := range dist {
+		res = append(res, Outcome{Genotype: genotype, Prob: p})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Genotype < res[j].Genotype })
+	return res
+}
+
+// lower returns the lowercase form of the ASCII letter c.
+func lower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// DominantProb returns the probability that two randomly selected mating
+// organisms will produce an individual possessing a dominant allele. Any two
+// organisms can mate from a population of k+m+n organisms: k individuals are
+// homozygous dominant of a factor, m are heterozygous, and n are homozygous
+// recessive.
+func DominantProb(k, m, n int) (*big.Rat, error) {
+	if k < 0 || m < 0 || n < 0 || k+m+n < 2 {
+		return nil, fmt.Errorf("prob.DominantProb: invalid population (%d, %d, %d); expected at least two organisms", k, m, n)
+	}
+	M, N, T := int64(m), int64(n), int64(k+m+n)
+	// Multiplied by 4, the number of ordered pairs producing homozygous
+	// recessive offspring; Aa+Aa with probability 1/4, Aa+aa and aa+Aa with
+	// probability 1/2, and aa+aa with probability 1.
+	recessive := M*(M-1) + 4*M*N + 4*N*(N-1)
+	prob := big.NewRat(recessive, 4*T*(T-1))
+	return prob.Sub(big.NewRat(1, 1), prob), nil
+}
+
+// dominantProbs holds the probability of a dominant phenotype in the offspring
+// of each genotype pairing: AA-AA, AA-Aa, AA-aa, Aa-Aa, Aa-aa and aa-aa.
+var dominantProbs = [6]*big.Rat{
+	big.NewRat(1, 1),
+	big.NewRat(1, 1),
+	big.NewRat(1, 1),
+	big.NewRat(3, 4),
+	big.NewRat(1, 2),
+	big.NewRat(0, 1),
+}
+
+// ExpectedOffspring returns the expected number of offspring displaying the
+// dominant phenotype, where each couple has the given number of offspring and
+// couples holds the number of couples of each genotype pairing: AA-AA, AA-Aa,
+// AA-aa, Aa-Aa, Aa-aa and aa-aa.
+func ExpectedOffspring(couples [6]int, offspring int) *big.Rat {
+	sum := new(big.Rat)
+	for i, n := range couples {
+		x := big.NewRat(int64(n)*int64(offspring), 1)
+		sum.Add(sum, x.Mul(x, dominantProbs[i]))
+	}
+	return sum
+}
+
+// IndependentAlleles returns the probability that at least n organisms of
+// generation k are heterozygous in two factors (AaBb), where the organism of
+// generation 0 has genotype AaBb, and each organism has two children with an
+// AaBb mate. As a mate of genotype AaBb passes on each locus heterozygously
+// with probability 1/2 regardless of the other parent, each of the 2^k
+// organisms of generation k is AaBb with probability 1/4. The generation is
+// limited to k <= 7, as for the Rosalind problem LIA, since the binomial tail is
+// summed term by term over up to 2^k organisms.
+func IndependentAlleles(k, n int) (*big.Rat, error) {
+	if k < 0 || k > 7 {
+		return nil, fmt.Errorf("prob.IndependentAlleles: invalid generation %d; expected 0 <= k <= 7", k)
+	}
+	return BinomialTail(1<<uint(k), n, big.NewRat(1, 4)), nil
+}
+
+// BinomialTail returns the probability of at least k successes in n
+// independent trials, each succeeding with probability p.
+func BinomialTail(n, k int, p *big.Rat) *big.Rat {
+	if k < 0 {
+		k = 0
+	}
+	q := new(big.Rat).Sub(big.NewRat(1, 1), p)
+	sum := new(big.Rat)
+	for i := k; i <= n; i++ {
+		x := new(big.Rat).SetInt(new(big.Int).Binomial(int64(n), int64(i)))
+		x.Mul(x, pow(p, i))
+		x.Mul(x, pow(q, n-i))
+		sum.Add(sum, x)
+	}
+	return sum
+}
+
+// pow returns x raised to the power of the non-negative integer n.
+func pow(x *big.Rat, n int) *big.Rat {
+	e := big.NewInt(int64(n))
+	num := new(big.Int).Exp(x.Num(), e, nil)
+	denom := new(big.Int).Exp(x.Denom(), e, nil)
+	return new(big.Rat).SetFrac(num, denom)
+}
@@ -0,0 +1,262 @@
+package prob
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestCross(t *testing.T) {
+	golden := []struct {
+		a, b       string
+		genotypes  map[string]string
+		phenotypes map[string]string
+		err        bool
+	}{
+		// i=0
+		{
+			a: "Aa", b: "Aa",
+			genotypes:  map[string]string{"AA": "1/4", "Aa": "1/2", "aa": "1/4"},
+			phenotypes: map[string]string{"A": "3/4", "a": "1/4"},
+		},
+		// i=1
+		{
+			a: "aA", b: "AA",
+			genotypes:  map[string]string{"AA": "1/2", "Aa": "1/2"},
+			phenotypes: map[string]string{"A": "1/1"},
+		},
+		// i=2 Dihybrid cross; the 9:3:3:1 phenotypic ratio.
+		{
+			a: "AaBb", b: "AaBb",
+			genotypes: map[string]string{
+				"AABB": "1/16", "AABb": "1/8", "AAbb": "1/16",
+				"AaBB": "1/8", "AaBb": "1/4", "Aabb": "1/8",
+				"aaBB": "1/16", "aaBb": "1/8", "aabb": "1/16",
+			},
+			phenotypes: map[string]string{"AB": "9/16", "Ab": "3/16", "aB": "3/16", "ab": "1/16"},
+		},
+		// i=3
+		{a: "AaBbCc", b: "aabbcc", phenotypes: map[string]string{"ABC": "1/8", "ABc": "1/8", "AbC": "1/8", "Abc": "1/8", "aBC": "1/8", "aBc": "1/8", "abC": "1/8", "abc": "1/8"}},
+		// i=4
+		{a: "Aa", b: "AaBb", err: true},
+		// i=5
+		{a: "Ab", b: "Aa", err: true},
+		// i=6
+		{a: "", b: "", err: true},
+	}
+	for i, g := range golden {
+		genotypes, err := Cross(g.a, g.b)
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error, got nil.", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if g.genotypes != nil {
+			if got := dist(genotypes); !reflect.DeepEqual(got, g.genotypes) {
+				t.Errorf("i=%d: genotype mismatch; expected %v, got %v.", i, g.genotypes, got)
+			}
+		}
+		if got := dist(Phenotypes(genotypes)); !reflect.DeepEqual(got, g.phenotypes) {
+			t.Errorf("i=%d: phenotype mismatch; expected %v, got %v.", i, g.phenotypes, got)
+		}
+	}
+}
+
+// dist returns the probabilities of the outcomes as strings, indexed by
+// genotype.
+func dist(outcomes []Outcome) map[string]string {
+	m := make(map[string]string)
+	for _, o := range outcomes {
+		m[o.Genotype] = o.Prob.String()
+	}
+	return m
+}
+
+func TestDominantProb(t *testing.T) {
+	golden := []struct {
+		k, m, n int
+		want    string
+		err     bool
+	}{
+		// i=0 Sample dataset of the Rosalind problem IPRB.
+		{k: 2, m: 2, n: 2, want: "47/60"},
+		// i=1
+		{k: 0, m: 2, n: 0, want: "3/4"},
+		// i=2
+		{k: 0, m: 0, n: 3, want: "0/1"},
+		// i=3
+		{k: 1, m: 0, n: 0, err: true},
+	}
+	for i, g := range golden {
+		got, err := DominantProb(g.k, g.m, g.n)
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error, got nil.", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if got.String() != g.want {
+			t.Errorf("i=%d: expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
+
+func TestExpectedOffspring(t *testing.T) {
+	golden := []struct {
+		couples [6]int
+		want    string
+	}{
+		// i=0 Sample dataset of the Rosalind problem IEV.
+		{couples: [6]int{1, 0, 0, 1, 0, 1}, want: "7/2"},
+		// i=1
+		{couples: [6]int{0, 0, 0, 1, 1, 0}, want: "5/2"},
+	}
+	for i, g := range golden {
+		if got := ExpectedOffspring(g.couples, 2); got.String() != g.want {
+			t.Errorf("i=%d: expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
+
+func TestIndependentAlleles(t *testing.T) {
+	golden := []struct {
+		k, n int
+		want string
+	}{
+		// i=0 Sample dataset of the Rosalind problem LIA.
+		{k: 2, n: 1, want: "175/256"},
+		// i=1
+		{k: 1, n: 0, want: "1/1"},
+		// i=2
+		{k: 1, n: 2, want: "1/16"},
+		// i=3
+		{k: 1, n: 3, want: "0/1"},
+	}
+	for i, g := range golden {
+		got, err := IndependentAlleles(g.k, g.n)
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if got.String() != g.want {
+			t.Errorf("i=%d: expected %v, got %v.", i, g.want, got)
+		}
+	}
+	for _, k := range []int{-1, 8, 30} {
+		if _, err := IndependentAlleles(k, 1); err == nil {
+			t.Errorf("expected error for generation %d, got nil.", k)
+		}
+	}
+}
+
+func TestHardyWeinberg(t *testing.T) {
+	p, err := AlleleFreq(30, 40, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dominant, hetero, recessive, err := HardyWeinberg(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{p.String(), dominant.String(), hetero.String(), recessive.String()}
+	want := []string{"1/2", "1/4", "1/2", "1/4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v.", want, got)
+	}
+	if _, _, _, err := HardyWeinberg(big.NewRat(3, 2)); err == nil {
+		t.Errorf("expected error, got nil.")
+	}
+}
+
+func TestCarrierProb(t *testing.T) {
+	// Sample dataset of the Rosalind problem AFRQ.
+	golden := []struct {
+		recessive float64
+		want      float64
+	}{
+		// i=0
+		{recessive: 0.1, want: 0.532},
+		// i=1
+		{recessive: 0.25, want: 0.75},
+		// i=2
+		{recessive: 0.5, want: 0.914},
+	}
+	for i, g := range golden {
+		got, err := CarrierProb(g.recessive)
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if math.Abs(got-g.want) > 0.001 {
+			t.Errorf("i=%d: expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
+
+func TestWrightFisher(t *testing.T) {
+	// Sample dataset of the Rosalind problem WFMD.
+	got, err := RecessiveProb(4, 6, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 0.772; math.Abs(got-want) > 0.001 {
+		t.Errorf("expected %v, got %v.", want, got)
+	}
+	// The distribution of a single generation is binomial.
+	dist, err := WrightFisher(4, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{1.0 / 16, 4.0 / 16, 6.0 / 16, 4.0 / 16, 1.0 / 16}
+	for j := range want {
+		if math.Abs(dist[j]-want[j]) > 1e-12 {
+			t.Errorf("j=%d: expected %v, got %v.", j, want[j], dist[j])
+		}
+	}
+	if _, err := WrightFisher(4, 5, 1); err == nil {
+		t.Errorf("expected error, got nil.")
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	// Simulations of the same seed are identical.
+	a, err := Simulate(rand.New(rand.NewSource(1)), 100, 50, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Simulate(rand.New(rand.NewSource(1)), 100, 50, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("expected identical simulations, got %v and %v.", a, b)
+	}
+	// The fraction of simulations with at least one recessive allele
+	// approximates the exact probability of the Rosalind problem WFMD.
+	rng := rand.New(rand.NewSource(1))
+	const trials = 20000
+	hits := 0
+	for i := 0; i < trials; i++ {
+		counts, err := Simulate(rng, 8, 2, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if counts[2] >= 1 {
+			hits++
+		}
+	}
+	if got, want := float64(hits)/trials, 0.772; math.Abs(got-want) > 0.02 {
+		t.Errorf("expected %v, got %v.", want, got)
+	}
+}
@@ -0,0 +1,105 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// WrightFisher returns the distribution of the number of copies of an allele
+// after g generations of genetic drift in a Wright-Fisher population of n
+// chromosomes, which initially holds i copies of the allele. The jth element
+// of the distribution is the probability of j copies, for j from 0 to n.
+//
+// In each generation, the n chromosomes are drawn with replacement from those
+// of the previous generation; the number of copies thereby follows the binomial
+// distribution B(n, j/n), where j is the number of copies of the previous
+// generation.
+func WrightFisher(n, i, g int) ([]float64, error) {
+	if n <= 0 || i < 0 || i > n || g < 0 {
+		return nil, fmt.Errorf("prob.WrightFisher: invalid population of %d chromosomes with %d copies over %d generations", n, i, g)
+	}
+	// trans[j] is the distribution of the next generation given j copies.
+	trans := make([][]float64, n+1)
+	for j := range trans {
+		trans[j] = binomial(n, float64(j)/float64(n))
+	}
+	dist := make([]float64, n+1)
+	dist[i] = 1
+	for ; g > 0; g-- {
+		next := make([]float64, n+1)
+		for j, p := range dist {
+			if p == 0 {
+				continue
+			}
+			for l, q := range trans[j] {
+				next[l] += p * q
+			}
+		}
+		dist = next
+	}
+	return dist, nil
+}
+
+// RecessiveProb returns the probability of at least k copies of a recessive
+// allele after g generations of a Wright-Fisher population of n diploid
+// organisms, which initially holds m copies of the dominant allele.
+func RecessiveProb(n, m, g, k int) (float64, error) {
+	dist, err := WrightFisher(2*n, 2*n-m, g)
+	if err != nil {
+		return 0, err
+	}
+	if k < 0 {
+		k = 0
+	}
+	sum := 0.0
+	for j := k; j < len(dist); j++ {
+		sum += dist[j]
+	}
+	return sum, nil
+}
+
+// binomial returns the probability mass function of the binomial distribution
+// B(n, p).
+func binomial(n int, p float64) []float64 {
+	pmf := make([]float64, n+1)
+	switch p {
+	case 0:
+		pmf[0] = 1
+		return pmf
+	case 1:
+		pmf[n] = 1
+		return pmf
+	}
+	lnp, lnq := math.Log(p), math.Log1p(-p)
+	lnn, _ := math.Lgamma(float64(n + 1))
+	for k := range pmf {
+		lnk, _ := math.Lgamma(float64(k + 1))
+		lnnk, _ := math.Lgamma(float64(n - k + 1))
+		pmf[k] = math.Exp(lnn - lnk - lnnk + float64(k)*lnp + float64(n-k)*lnq)
+	}
+	return pmf
+}
+
+// Simulate simulates g generations of genetic drift in a Wright-Fisher
+// population of n chromosomes, which initially holds i copies of an allele. It
+// returns the number of copies of each generation, starting with i. The
+// simulation is reproducible given the seed of the source of rng.
+func Simulate(rng *rand.Rand, n, i, g int) ([]int, error) {
+	if n <= 0 || i < 0 || i > n || g < 0 {
+		return nil, fmt.Errorf("prob.Simulate: invalid population of %d chromosomes with %d copies over %d generations", n, i, g)
+	}
+	counts := []int{i}
+	for ; g > 0; g-- {
+		p := float64(i) / float64(n)
+		next := 0
+		for j := 0; j < n; j++ {
+			if rng.Float64() < p {
+				next++
+			}
+		}
+		i = next
+		counts = append(counts, i)
+	}
+	return counts, nil
+}
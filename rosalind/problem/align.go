@@ -0,0 +1,63 @@
+package problem
+
+import (
+	"fmt"
+
+	"github.com/mewmew/playground/rosalind/align"
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func init() {
+	Register(&Problem{
+		ID:    "glob",
+		Title: "Global Alignment with Scoring Matrix",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			ss, err := nseqs(input.(*rosa.FASTA), 2)
+			if err != nil {
+				return "", err
+			}
+			s := align.Scoring{Matrix: align.BLOSUM62, GapOpen: 5, GapExtend: 5}
+			return fmt.Sprint(align.Hirschberg([]byte(ss[0]), []byte(ss[1]), s).Score), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "gcon",
+		Title: "Global Alignment with Constant Gap Penalty",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			ss, err := nseqs(input.(*rosa.FASTA), 2)
+			if err != nil {
+				return "", err
+			}
+			s := align.Scoring{Matrix: align.BLOSUM62, GapOpen: 5, GapExtend: 0}
+			return fmt.Sprint(align.Hirschberg([]byte(ss[0]), []byte(ss[1]), s).Score), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "loca",
+		Title: "Local Alignment with Scoring Matrix",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			ss, err := nseqs(input.(*rosa.FASTA), 2)
+			if err != nil {
+				return "", err
+			}
+			a, b := []byte(ss[0]), []byte(ss[1])
+			s := align.Scoring{Matrix: align.PAM250, GapOpen: 5, GapExtend: 5}
+			aln := align.Local(a, b, s)
+			return fmt.Sprintf("%d\n%s\n%s", aln.Score, a[aln.AStart:aln.AEnd], b[aln.BStart:aln.BEnd]), nil
+		},
+		Compare: compareScore,
+	})
+}
+
+// compareScore compares the alignment score on the first line of the output
+// against the expected output; Rosalind accepts any optimal alignment.
+func compareScore(got, want string) error {
+	gs, ws := lines(got), lines(want)
+	if len(gs) == 0 || len(ws) == 0 {
+		return Exact(got, want)
+	}
+	return Exact(gs[0], ws[0])
+}
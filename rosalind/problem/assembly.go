@@ -0,0 +1,94 @@
+package problem
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/assembly"
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func init() {
+	Register(&Problem{
+		ID:    "long",
+		Title: "Genome Assembly as Shortest Superstring",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			return rosa.Superstring(seqs(input.(*rosa.FASTA)))
+		},
+	})
+	Register(&Problem{
+		ID:    "dbru",
+		Title: "Constructing a De Bruijn Graph",
+		Parse: parseLines,
+		Solve: func(input interface{}) (string, error) {
+			g, err := newGraph(input.([]string), true)
+			if err != nil {
+				return "", err
+			}
+			buf := new(bytes.Buffer)
+			for _, edge := range g.Edges() {
+				fmt.Fprintf(buf, "(%s, %s)\n", edge.From, edge.To)
+			}
+			return buf.String(), nil
+		},
+		// Rosalind accepts the edges in any order.
+		Compare: Unordered,
+	})
+	Register(&Problem{
+		ID:    "pcov",
+		Title: "Genome Assembly with Perfect Coverage",
+		Parse: parseLines,
+		Solve: func(input interface{}) (string, error) {
+			g, err := newGraph(input.([]string), false)
+			if err != nil {
+				return "", err
+			}
+			cycle, err := g.EulerianPath()
+			if err != nil {
+				return "", err
+			}
+			return assembly.SpellCycle(cycle), nil
+		},
+		Compare: cyclic(false),
+	})
+	Register(&Problem{
+		ID:    "gasm",
+		Title: "Genome Assembly Using Reads",
+		Parse: parseLines,
+		Solve: func(input interface{}) (string, error) {
+			return assembly.Circular(input.([]string))
+		},
+		Compare: cyclic(true),
+	})
+}
+
+// newGraph returns the De Bruijn graph of the k-mers, optionally including
+// their reverse complements.
+func newGraph(kmers []string, bothStrands bool) (*assembly.Graph, error) {
+	if len(kmers) == 0 {
+		return nil, errors.New("no k-mers")
+	}
+	return assembly.NewGraph(kmers, len(kmers[0]), bothStrands, 1)
+}
+
+// cyclic returns a comparison function which accepts any rotation of the
+// expected circular sequence, or of its reverse complement if bothStrands is
+// set.
+func cyclic(bothStrands bool) func(got, want string) error {
+	return func(got, want string) error {
+		g, w := strings.TrimSpace(got), strings.TrimSpace(want)
+		candidates := []string{w}
+		if bothStrands {
+			candidates = append(candidates, rosa.RevComp(w))
+		}
+		for _, c := range candidates {
+			if len(g) == len(c) && strings.Contains(c+c, g) {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected a rotation of %q, got %q", w, g)
+	}
+}
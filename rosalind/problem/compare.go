@@ -0,0 +1,84 @@
+package problem
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Exact returns an error if the output differs from the expected output,
+// ignoring trailing whitespace of lines and blank lines at the start and end of
+// the output.
+func Exact(got, want string) error {
+	gs, ws := lines(got), lines(want)
+	for i := 0; i < len(gs) || i < len(ws); i++ {
+		var g, w string
+		if i < len(gs) {
+			g = gs[i]
+		}
+		if i < len(ws) {
+			w = ws[i]
+		}
+		if g != w {
+			return fmt.Errorf("line %d: expected %q, got %q", i+1, w, g)
+		}
+	}
+	return nil
+}
+
+// Tolerance returns a comparison function which, like Exact, compares the
+// output line by line and word by word, but where numeric words may differ by
+// at most eps.
+func Tolerance(eps float64) func(got, want string) error {
+	return func(got, want string) error {
+		gs, ws := lines(got), lines(want)
+		if len(gs) != len(ws) {
+			return fmt.Errorf("expected %d lines, got %d", len(ws), len(gs))
+		}
+		for i := range ws {
+			gf, wf := strings.Fields(gs[i]), strings.Fields(ws[i])
+			if len(gf) != len(wf) {
+				return fmt.Errorf("line %d: expected %q, got %q", i+1, ws[i], gs[i])
+			}
+			for j := range wf {
+				if gf[j] == wf[j] {
+					continue
+				}
+				g, gerr := strconv.ParseFloat(gf[j], 64)
+				w, werr := strconv.ParseFloat(wf[j], 64)
+				if gerr != nil || werr != nil || math.Abs(g-w) > eps {
+					return fmt.Errorf("line %d: expected %q, got %q", i+1, wf[j], gf[j])
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// rosalindTolerance compares numeric output with the absolute error of 0.001
+// accepted by Rosalind.
+var rosalindTolerance = Tolerance(0.001)
+
+// Unordered returns an error if the lines of the output differ from the lines
+// of the expected output, regardless of order.
+func Unordered(got, want string) error {
+	gs, ws := lines(got), lines(want)
+	sort.Strings(gs)
+	sort.Strings(ws)
+	return Exact(strings.Join(gs, "\n"), strings.Join(ws, "\n"))
+}
+
+// lines returns the lines of s, excluding trailing whitespace and blank lines
+// at the start and end of s.
+func lines(s string) []string {
+	ls := strings.Split(strings.TrimSpace(s), "\n")
+	for i, l := range ls {
+		ls[i] = strings.TrimRight(l, " \t\r")
+	}
+	if len(ls) == 1 && len(ls[0]) == 0 {
+		return nil
+	}
+	return ls
+}
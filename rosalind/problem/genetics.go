@@ -0,0 +1,93 @@
+package problem
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/prob"
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func init() {
+	Register(&Problem{
+		ID:    "fib",
+		Title: "Rabbits and Recurrence Relations",
+		Parse: parseInts(2),
+		Solve: func(input interface{}) (string, error) {
+			ints := input.([]int)
+			return fmt.Sprint(rosa.Fib(ints[0], ints[1])), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "iprb",
+		Title: "Mendel's First Law",
+		Parse: parseInts(3),
+		Solve: func(input interface{}) (string, error) {
+			ints := input.([]int)
+			p, err := prob.DominantProb(ints[0], ints[1], ints[2])
+			if err != nil {
+				return "", err
+			}
+			return p.FloatString(5), nil
+		},
+		Compare: rosalindTolerance,
+	})
+	Register(&Problem{
+		ID:    "iev",
+		Title: "Calculating Expected Offspring",
+		Parse: parseInts(6),
+		Solve: func(input interface{}) (string, error) {
+			var couples [6]int
+			copy(couples[:], input.([]int))
+			// Each couple has exactly two offspring.
+			x, _ := prob.ExpectedOffspring(couples, 2).Float64()
+			return fmt.Sprint(x), nil
+		},
+		Compare: rosalindTolerance,
+	})
+	Register(&Problem{
+		ID:    "lia",
+		Title: "Independent Alleles",
+		Parse: parseInts(2),
+		Solve: func(input interface{}) (string, error) {
+			ints := input.([]int)
+			p, err := prob.IndependentAlleles(ints[0], ints[1])
+			if err != nil {
+				return "", err
+			}
+			return p.FloatString(3), nil
+		},
+		Compare: rosalindTolerance,
+	})
+	Register(&Problem{
+		ID:    "afrq",
+		Title: "Counting Disease Carriers",
+		Parse: parseFloats,
+		Solve: func(input interface{}) (string, error) {
+			var ps []string
+			for _, x := range input.([]float64) {
+				p, err := prob.CarrierProb(x)
+				if err != nil {
+					return "", err
+				}
+				ps = append(ps, fmt.Sprintf("%.3f", p))
+			}
+			return strings.Join(ps, " "), nil
+		},
+		Compare: rosalindTolerance,
+	})
+	Register(&Problem{
+		ID:    "wfmd",
+		Title: "The Wright-Fisher Model of Genetic Drift",
+		Parse: parseInts(4),
+		Solve: func(input interface{}) (string, error) {
+			ints := input.([]int)
+			p, err := prob.RecessiveProb(ints[0], ints[1], ints[2], ints[3])
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%.3f", p), nil
+		},
+		Compare: rosalindTolerance,
+	})
+}
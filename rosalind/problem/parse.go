@@ -0,0 +1,115 @@
+package problem
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+// parseText parses an input dataset consisting of a single string, excluding
+// surrounding whitespace.
+func parseText(r io.Reader) (interface{}, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// parseLines parses an input dataset consisting of one string per line, where
+// blank lines and surrounding whitespace are ignored.
+func parseLines(r io.Reader) (interface{}, error) {
+	return readLines(r)
+}
+
+// readLines reads the non-blank lines of r, excluding surrounding whitespace.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseFASTA parses an input dataset in FASTA format.
+func parseFASTA(r io.Reader) (interface{}, error) {
+	return rosa.ParseFASTA(r)
+}
+
+// parseInts returns a parser of input datasets consisting of n integers,
+// separated by whitespace.
+func parseInts(n int) func(r io.Reader) (interface{}, error) {
+	return func(r io.Reader) (interface{}, error) {
+		buf, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(string(buf))
+		if len(fields) != n {
+			return nil, fmt.Errorf("invalid number of integers; expected %d, got %d", n, len(fields))
+		}
+		ints := make([]int, n)
+		for i, field := range fields {
+			if ints[i], err = strconv.Atoi(field); err != nil {
+				return nil, err
+			}
+		}
+		return ints, nil
+	}
+}
+
+// parseFloats parses an input dataset consisting of floating-point numbers,
+// separated by whitespace.
+func parseFloats(r io.Reader) (interface{}, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var floats []float64
+	for _, field := range strings.Fields(string(buf)) {
+		x, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, err
+		}
+		floats = append(floats, x)
+	}
+	return floats, nil
+}
+
+// seqs returns the sequences of the FASTA file, in order of occurrence.
+func seqs(fas *rosa.FASTA) []string {
+	var ss []string
+	for _, rec := range fas.Records {
+		ss = append(ss, string(rec.Seq))
+	}
+	return ss
+}
+
+// nseqs returns the sequences of the FASTA file, which must contain n records.
+func nseqs(fas *rosa.FASTA, n int) ([]string, error) {
+	if len(fas.Records) != n {
+		return nil, fmt.Errorf("invalid number of sequences; expected %d, got %d", n, len(fas.Records))
+	}
+	return seqs(fas), nil
+}
+
+// joinInts returns the integers separated by spaces.
+func joinInts(ints []int) string {
+	strs := make([]string, len(ints))
+	for i, v := range ints {
+		strs[i] = strconv.Itoa(v)
+	}
+	return strings.Join(strs, " ")
+}
@@ -0,0 +1,153 @@
+package problem
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+)
+
+func init() {
+	Register(&Problem{
+		ID:    "nwck",
+		Title: "Distances in Trees",
+		Parse: parseTreePairs,
+		Solve: func(input interface{}) (string, error) {
+			return solveTreePairs(input.([]treePair), false)
+		},
+	})
+	Register(&Problem{
+		ID:    "nkew",
+		Title: "Newick Format with Edge Weights",
+		Parse: parseTreePairs,
+		Solve: func(input interface{}) (string, error) {
+			return solveTreePairs(input.([]treePair), true)
+		},
+	})
+	Register(&Problem{
+		ID:    "pdst",
+		Title: "Creating a Distance Matrix",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			dist, err := rosa.PDistance(seqs(input.(*rosa.FASTA)))
+			if err != nil {
+				return "", err
+			}
+			buf := new(bytes.Buffer)
+			for _, row := range dist {
+				for j, d := range row {
+					if j != 0 {
+						buf.WriteByte(' ')
+					}
+					fmt.Fprintf(buf, "%.5f", d)
+				}
+				buf.WriteByte('\n')
+			}
+			return buf.String(), nil
+		},
+		Compare: rosalindTolerance,
+	})
+	Register(&Problem{
+		ID:    "tree",
+		Title: "Completing a Tree",
+		Parse: parseGraph,
+		Solve: func(input interface{}) (string, error) {
+			g := input.(graph)
+			n, err := rosa.TreeEdges(g.n, g.edges)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprint(n), nil
+		},
+	})
+}
+
+// A treePair is a tree in Newick format and the names of two of its nodes.
+type treePair struct {
+	// Tree in Newick format.
+	tree string
+	// Node names.
+	a, b string
+}
+
+// parseTreePairs parses an input dataset consisting of pairs of lines, where
+// the first line holds a tree in Newick format and the second line the names
+// of two of its nodes.
+func parseTreePairs(r io.Reader) (interface{}, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines)%2 != 0 {
+		return nil, fmt.Errorf("invalid number of lines; expected pairs of trees and node names, got %d lines", len(lines))
+	}
+	var pairs []treePair
+	for i := 0; i < len(lines); i += 2 {
+		names := strings.Fields(lines[i+1])
+		if len(names) != 2 {
+			return nil, fmt.Errorf("invalid number of node names; expected 2, got %d", len(names))
+		}
+		pairs = append(pairs, treePair{tree: lines[i], a: names[0], b: names[1]})
+	}
+	return pairs, nil
+}
+
+// solveTreePairs returns the distance between the nodes of each tree, separated
+// by spaces; the weighted distance if weighted is set.
+func solveTreePairs(pairs []treePair, weighted bool) (string, error) {
+	var ds []string
+	for _, pair := range pairs {
+		root, err := rosa.ParseNewick(pair.tree)
+		if err != nil {
+			return "", err
+		}
+		d, err := root.Distance(pair.a, pair.b, weighted)
+		if err != nil {
+			return "", err
+		}
+		ds = append(ds, strconv.FormatFloat(d, 'f', -1, 64))
+	}
+	return strings.Join(ds, " "), nil
+}
+
+// A graph is an undirected graph of n nodes, numbered from 1 to n.
+type graph struct {
+	// Number of nodes.
+	n int
+	// Edges of the graph.
+	edges [][2]int
+}
+
+// parseGraph parses an input dataset consisting of the number of nodes of a
+// graph, followed by its adjacency list with one edge per line.
+func parseGraph(r io.Reader) (interface{}, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("missing number of nodes")
+	}
+	var g graph
+	if g.n, err = strconv.Atoi(lines[0]); err != nil {
+		return nil, err
+	}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid edge %q; expected 2 nodes, got %d", line, len(fields))
+		}
+		var edge [2]int
+		for i, field := range fields {
+			if edge[i], err = strconv.Atoi(field); err != nil {
+				return nil, err
+			}
+		}
+		g.edges = append(g.edges, edge)
+	}
+	return g, nil
+}
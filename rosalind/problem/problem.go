@@ -0,0 +1,126 @@
+// Package problem implements a registry of Rosalind problems, each of which
+// provides a parser of input datasets and a solver.
+//
+// The sample dataset of a problem is located in the testdata directory, where
+// "<id>.in" holds the input dataset and "<id>.out" the expected output.
+package problem
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A Problem is a Rosalind problem.
+type Problem struct {
+	// ID of the problem, in lowercase; e.g. "iprb".
+	ID string
+	// Title of the problem.
+	Title string
+	// Parse parses the input dataset of the problem.
+	Parse func(r io.Reader) (interface{}, error)
+	// Solve solves the problem for the parsed input dataset, and returns the
+	// output in the format expected by Rosalind.
+	Solve func(input interface{}) (string, error)
+	// Compare returns an error if the output differs from the expected output;
+	// Exact is used if nil.
+	Compare func(got, want string) error
+}
+
+// problems maps from problem ID to registered problem.
+var problems = make(map[string]*Problem)
+
+// Register registers the problem. It panics if a problem with the same ID has
+// already been registered.
+func Register(p *Problem) {
+	if _, ok := problems[p.ID]; ok {
+		panic(fmt.Sprintf("problem.Register: problem %q already registered", p.ID))
+	}
+	problems[p.ID] = p
+}
+
+// Lookup returns the registered problem with the provided ID, regardless of
+// case.
+func Lookup(id string) (*Problem, error) {
+	p, ok := problems[strings.ToLower(id)]
+	if !ok {
+		return nil, fmt.Errorf("problem.Lookup: unknown problem %q", id)
+	}
+	return p, nil
+}
+
+// Problems returns the registered problems, sorted by ID.
+func Problems() []*Problem {
+	var ps []*Problem
+	for _, p := range problems {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].ID < ps[j].ID })
+	return ps
+}
+
+// Run parses the input dataset read from r and solves the problem.
+func (p *Problem) Run(r io.Reader) (string, error) {
+	input, err := p.Parse(r)
+	if err != nil {
+		return "", fmt.Errorf("%s: invalid input; %v", p.ID, err)
+	}
+	output, err := p.Solve(input)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", p.ID, err)
+	}
+	return output, nil
+}
+
+// Check solves the problem for the input dataset read from r, and compares the
+// output against the expected output.
+func (p *Problem) Check(r io.Reader, want string) error {
+	got, err := p.Run(r)
+	if err != nil {
+		return err
+	}
+	compare := p.Compare
+	if compare == nil {
+		compare = Exact
+	}
+	if err := compare(got, want); err != nil {
+		return fmt.Errorf("%s: %v", p.ID, err)
+	}
+	return nil
+}
+
+// CheckSample checks the solution of the sample dataset of the problem, located
+// in dir.
+func (p *Problem) CheckSample(dir string) error {
+	in, out, err := p.samplePaths(dir)
+	if err != nil {
+		return err
+	}
+	want, err := ioutil.ReadFile(out)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return p.Check(f, string(want))
+}
+
+// samplePaths returns the paths of the input dataset and the expected output of
+// the sample dataset of the problem, located in dir.
+func (p *Problem) samplePaths(dir string) (in, out string, err error) {
+	in = filepath.Join(dir, p.ID+".in")
+	out = filepath.Join(dir, p.ID+".out")
+	for _, path := range []string{in, out} {
+		if _, err := os.Stat(path); err != nil {
+			return "", "", fmt.Errorf("%s: missing sample dataset; %v", p.ID, err)
+		}
+	}
+	return in, out, nil
+}
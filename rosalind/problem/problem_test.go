@@ -0,0 +1,81 @@
+package problem
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSamples(t *testing.T) {
+	for _, p := range Problems() {
+		p := p
+		t.Run(p.ID, func(t *testing.T) {
+			if err := p.CheckSample("testdata"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	// Each sample dataset must belong to a registered problem.
+	paths, err := filepath.Glob("testdata/*.in")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), ".in")
+		if _, err := Lookup(id); err != nil {
+			t.Errorf("sample dataset %q of unregistered problem; %v", path, err)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	golden := []struct {
+		compare   func(got, want string) error
+		got, want string
+		ok        bool
+	}{
+		// i=0
+		{compare: Exact, got: "1 2\n3\n", want: "1 2  \r\n3", ok: true},
+		// i=1
+		{compare: Exact, got: "1 2\n3\n4", want: "1 2\n3", ok: false},
+		// i=2
+		{compare: Tolerance(0.001), got: "0.78333", want: "0.7830", ok: true},
+		// i=3
+		{compare: Tolerance(0.001), got: "0.78333", want: "0.7820", ok: false},
+		// i=4
+		{compare: Tolerance(0.001), got: "Rosalind_0808\n60.919540", want: "Rosalind_0808\n60.91954", ok: true},
+		// i=5
+		{compare: Tolerance(0.001), got: "Rosalind_0809\n60.919540", want: "Rosalind_0808\n60.91954", ok: false},
+		// i=6
+		{compare: Unordered, got: "5 4\n4 6\n", want: "4 6\n5 4", ok: true},
+		// i=7
+		{compare: Unordered, got: "5 4\n4 6\n4 6", want: "4 6\n5 4", ok: false},
+		// i=8
+		{compare: cyclic(false), got: "ACAGATT", want: "GATTACA", ok: true},
+		// i=9
+		{compare: cyclic(false), got: "AATCTGT", want: "GATTACA", ok: false},
+		// i=10
+		{compare: cyclic(true), got: "AATCTGT", want: "GATTACA", ok: true},
+		// i=11
+		{compare: compareScore, got: "23\nMEANLYPRTEINSTRIN\nLEASANTLYEINSTEIN", want: "23\nLYPRTEINSTRIN\nLYEINSTEIN", ok: true},
+	}
+	for i, g := range golden {
+		err := g.compare(g.got, g.want)
+		if ok := err == nil; ok != g.ok {
+			t.Errorf("i=%d: expected ok=%v, got %v; %v", i, g.ok, ok, err)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	p, err := Lookup("IPRB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ID != "iprb" {
+		t.Errorf("expected problem %q, got %q.", "iprb", p.ID)
+	}
+	if _, err := Lookup("nope"); err == nil {
+		t.Errorf("expected error for unknown problem, got nil.")
+	}
+}
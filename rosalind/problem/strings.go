@@ -0,0 +1,242 @@
+package problem
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mewmew/playground/rosalind/gene"
+	"github.com/mewmew/playground/rosalind/orf"
+	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+func init() {
+	Register(&Problem{
+		ID:    "dna",
+		Title: "Counting DNA Nucleotides",
+		Parse: parseDNA,
+		Solve: func(input interface{}) (string, error) {
+			a, c, g, t := input.(seq.DNA).Count()
+			return fmt.Sprintln(a, c, g, t), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "rna",
+		Title: "Transcribing DNA into RNA",
+		Parse: parseDNA,
+		Solve: func(input interface{}) (string, error) {
+			return input.(seq.DNA).Transcribe().String(), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "revc",
+		Title: "Complementing a Strand of DNA",
+		Parse: parseDNA,
+		Solve: func(input interface{}) (string, error) {
+			return input.(seq.DNA).RevComp().String(), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "gc",
+		Title: "Computing GC Content",
+		Parse: parseFASTA,
+		Solve: solveGC,
+		Compare: rosalindTolerance,
+	})
+	Register(&Problem{
+		ID:    "hamm",
+		Title: "Counting Point Mutations",
+		Parse: parseLines,
+		Solve: func(input interface{}) (string, error) {
+			lines := input.([]string)
+			if len(lines) != 2 {
+				return "", fmt.Errorf("invalid number of DNA sequences; expected 2, got %d", len(lines))
+			}
+			n, err := rosa.HamDist(lines[0], lines[1])
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprint(n), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "prot",
+		Title: "Translating RNA into Protein",
+		Parse: parseText,
+		Solve: func(input interface{}) (string, error) {
+			return rosa.Prot(input.(string))
+		},
+	})
+	Register(&Problem{
+		ID:    "orf",
+		Title: "Open Reading Frames",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			ss, err := nseqs(input.(*rosa.FASTA), 1)
+			if err != nil {
+				return "", err
+			}
+			dna, err := seq.ParseDNA(ss[0])
+			if err != nil {
+				return "", err
+			}
+			uniq := make(map[string]bool)
+			buf := new(bytes.Buffer)
+			for _, o := range orf.Find(dna, orf.Options{Nested: true}) {
+				if prot := o.Protein.String(); !uniq[prot] {
+					uniq[prot] = true
+					fmt.Fprintln(buf, prot)
+				}
+			}
+			return buf.String(), nil
+		},
+		// Rosalind accepts the proteins in any order.
+		Compare: Unordered,
+	})
+	Register(&Problem{
+		ID:    "splc",
+		Title: "RNA Splicing",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			ss := seqs(input.(*rosa.FASTA))
+			if len(ss) == 0 {
+				return "", errors.New("missing DNA sequence")
+			}
+			dna := ss[0]
+			introns, err := gene.Locate(dna, ss[1:])
+			if err != nil {
+				return "", err
+			}
+			g, err := gene.FromIntrons("", "", '+', len(dna), introns)
+			if err != nil {
+				return "", err
+			}
+			prot, err := g.Protein(seq.DNA(dna), nil)
+			if err != nil {
+				return "", err
+			}
+			return prot.String(), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "subs",
+		Title: "Finding a Motif in DNA",
+		Parse: parseLines,
+		Solve: func(input interface{}) (string, error) {
+			lines := input.([]string)
+			if len(lines) != 2 {
+				return "", fmt.Errorf("invalid number of lines; expected 2, got %d", len(lines))
+			}
+			locs := rosa.NewSuffixArray(lines[0]).Lookup(lines[1])
+			for i := range locs {
+				locs[i]++
+			}
+			return joinInts(locs), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "cons",
+		Title: "Consensus and Profile",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			profile, err := rosa.NewProfile(seqs(input.(*rosa.FASTA)), true)
+			if err != nil {
+				return "", err
+			}
+			return profile.Cons() + "\n" + profile.String(), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "lcsm",
+		Title: "Finding a Shared Motif",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			return rosa.LongestCommonSubstring(seqs(input.(*rosa.FASTA))), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "revp",
+		Title: "Locating Restriction Sites",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			ss, err := nseqs(input.(*rosa.FASTA), 1)
+			if err != nil {
+				return "", err
+			}
+			locs, ns := rosa.RevPal(ss[0])
+			buf := new(bytes.Buffer)
+			for i := range locs {
+				fmt.Fprintln(buf, locs[i]+1, ns[i])
+			}
+			return buf.String(), nil
+		},
+		// Rosalind accepts the locations in any order.
+		Compare: Unordered,
+	})
+	Register(&Problem{
+		ID:    "sseq",
+		Title: "Finding a Spliced Motif",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			ss, err := nseqs(input.(*rosa.FASTA), 2)
+			if err != nil {
+				return "", err
+			}
+			locs := rosa.SubSeq(ss[0], ss[1])
+			if locs == nil {
+				return "", fmt.Errorf("unable to locate %q as a subsequence", ss[1])
+			}
+			for i := range locs {
+				locs[i]++
+			}
+			return joinInts(locs), nil
+		},
+	})
+	Register(&Problem{
+		ID:    "kmer",
+		Title: "k-Mer Composition",
+		Parse: parseFASTA,
+		Solve: func(input interface{}) (string, error) {
+			ss, err := nseqs(input.(*rosa.FASTA), 1)
+			if err != nil {
+				return "", err
+			}
+			counts, err := rosa.KmerComposition(ss[0], 4)
+			if err != nil {
+				return "", err
+			}
+			return joinInts(counts), nil
+		},
+	})
+}
+
+// parseDNA parses an input dataset consisting of a single DNA sequence.
+func parseDNA(r io.Reader) (interface{}, error) {
+	s, err := parseText(r)
+	if err != nil {
+		return nil, err
+	}
+	return seq.ParseDNA(s.(string))
+}
+
+// solveGC returns the ID and GC-content of the DNA sequence with the highest
+// GC-content.
+func solveGC(input interface{}) (string, error) {
+	var maxID string
+	maxGC := -1.0
+	for _, rec := range input.(*rosa.FASTA).Records {
+		dna, err := seq.ParseDNA(string(rec.Seq))
+		if err != nil {
+			return "", fmt.Errorf("%s: %v", rec.ID, err)
+		}
+		if gc := 100 * dna.GC(); gc > maxGC {
+			maxID, maxGC = rec.ID, gc
+		}
+	}
+	if maxGC < 0 {
+		return "", errors.New("no DNA sequences")
+	}
+	return fmt.Sprintf("%s\n%.6f", maxID, maxGC), nil
+}
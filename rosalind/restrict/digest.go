@@ -0,0 +1,171 @@
+package restrict
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+// A Fragment is a fragment of a digested DNA sequence.
+type Fragment struct {
+	// Start and end offsets of the fragment in the sequence, where the end
+	// offset is exclusive. Fragments of circular sequences may wrap around the
+	// end of the sequence, in which case End <= Start.
+	Start, End int
+	// Length of the fragment.
+	Len int
+	// Left and Right are the sites cut at the start and end of the fragment, or
+	// nil at the ends of linear sequences and for uncut circular sequences.
+	Left, Right *Site
+}
+
+// Digest returns the fragments of a DNA sequence of length n, which is cut at
+// the top strand cut offsets of the sites, sorted by cut offset as returned by
+// Map. Sites cutting at the same offset yield a single cut. A circular
+// sequence without sites yields one fragment; the uncut sequence.
+func Digest(n int, sites []*Site, circular bool) []*Fragment {
+	// Locate the distinct cuts.
+	var cuts []*Site
+	for _, site := range sites {
+		if len(cuts) > 0 && cuts[len(cuts)-1].Cut == site.Cut {
+			continue
+		}
+		cuts = append(cuts, site)
+	}
+	var frags []*Fragment
+	if !circular {
+		start := 0
+		var left *Site
+		for _, cut := range cuts {
+			frags = append(frags, &Fragment{Start: start, End: cut.Cut, Len: cut.Cut - start, Left: left, Right: cut})
+			start, left = cut.Cut, cut
+		}
+		return append(frags, &Fragment{Start: start, End: n, Len: n - start, Left: left})
+	}
+	if len(cuts) == 0 {
+		return []*Fragment{{Start: 0, End: n, Len: n}}
+	}
+	for i, cut := range cuts {
+		next := cuts[(i+1)%len(cuts)]
+		frags = append(frags, &Fragment{Start: cut.Cut, End: next.Cut, Len: mod(next.Cut-cut.Cut-1, n) + 1, Left: cut, Right: next})
+	}
+	return frags
+}
+
+// Seq returns the sequence of the fragment, which was cut from dna.
+func (frag *Fragment) Seq(dna seq.DNA) seq.DNA {
+	s := make(seq.DNA, 0, frag.Len)
+	for i := 0; i < frag.Len; i++ {
+		s = append(s, dna[(frag.Start+i)%len(dna)])
+	}
+	return s
+}
+
+// WriteTable writes a table of the fragments to w, with one fragment per line.
+// Start and end positions start at 1 and are inclusive.
+func WriteTable(w io.Writer, frags []*Fragment) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "#\tStart\tEnd\tLength\tLeft\tRight\t")
+	for i, frag := range frags {
+		end := frag.End
+		if end == 0 {
+			// Fragment wrapping around the end of a circular sequence, up to and
+			// including its last base.
+			end = frag.Start + frag.Len
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%s\t%s\t\n", i+1, frag.Start+1, end, frag.Len, siteName(frag.Left), siteName(frag.Right))
+	}
+	return tw.Flush()
+}
+
+// siteName returns the name of the enzyme of the site, or "-" if nil.
+func siteName(site *Site) string {
+	if site == nil {
+		return "-"
+	}
+	return site.Enzyme.Name
+}
+
+// A Lane is a lane of a gel.
+type Lane struct {
+	// Name of the lane.
+	Name string
+	// Fragment sizes in base pairs.
+	Sizes []int
+}
+
+// WriteGel writes a text rendering of the gel electrophoresis of the lanes to
+// w, using the given number of rows. Fragments migrate a distance proportional
+// to the logarithm of their size, with the largest fragment on the first row
+// and the smallest on the last. Bands of co-migrating fragments are drawn with
+// '#' and other bands with '='. Each row is labelled by the approximate
+// fragment size of the row.
+func WriteGel(w io.Writer, lanes []Lane, rows int) error {
+	if rows < 2 {
+		rows = 2
+	}
+	min, max := math.MaxInt32, 0
+	for _, lane := range lanes {
+		for _, size := range lane.Sizes {
+			if size < min {
+				min = size
+			}
+			if size > max {
+				max = size
+			}
+		}
+	}
+	if max == 0 {
+		min, max = 1, 1
+	}
+	logMin, logMax := math.Log(float64(min)), math.Log(float64(max))
+	row := func(size int) int {
+		if logMax == logMin {
+			return 0
+		}
+		return int(math.Round((logMax - math.Log(float64(size))) / (logMax - logMin) * float64(rows-1)))
+	}
+	// bands[i][r] holds the number of fragments of lane i at row r.
+	bands := make([][]int, len(lanes))
+	widths := make([]int, len(lanes))
+	for i, lane := range lanes {
+		bands[i] = make([]int, rows)
+		for _, size := range lane.Sizes {
+			if size > 0 {
+				bands[i][row(size)]++
+			}
+		}
+		widths[i] = len(lane.Name)
+		if widths[i] < 6 {
+			widths[i] = 6
+		}
+	}
+	buf := new(bytes.Buffer)
+	line := fmt.Sprintf("%8s", "bp")
+	for i, lane := range lanes {
+		line += fmt.Sprintf("  %-*s", widths[i], lane.Name)
+	}
+	buf.WriteString(strings.TrimRight(line, " ") + "\n")
+	for r := 0; r < rows; r++ {
+		size := math.Exp(logMax - float64(r)/float64(rows-1)*(logMax-logMin))
+		line := fmt.Sprintf("%8d", int(math.Round(size)))
+		for i := range lanes {
+			band := strings.Repeat(" ", widths[i])
+			switch {
+			case bands[i][r] == 1:
+				band = strings.Repeat("=", widths[i])
+			case bands[i][r] > 1:
+				band = strings.Repeat("#", widths[i])
+			}
+			line += "  " + band
+		}
+		buf.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,196 @@
+// Package restrict implements restriction enzyme site mapping and digests of
+// DNA sequences.
+package restrict
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+// An Enzyme is a restriction enzyme, which cuts double-stranded DNA at or near
+// its recognition site.
+type Enzyme struct {
+	// Name of the enzyme; e.g. "EcoRI".
+	Name string
+	// Recognition site of the enzyme from 5' to 3' in uppercase, which may
+	// contain IUPAC ambiguity codes.
+	Site string
+	// Cut is the offset of the cut in the top strand relative to the start of
+	// the recognition site; the enzyme cuts between bases Cut-1 and Cut.
+	Cut int
+	// RevCut is the offset of the cut in the bottom strand, in top strand
+	// coordinates relative to the start of the recognition site.
+	RevCut int
+	// palindromic specifies whether the recognition site equals its reverse
+	// complement.
+	palindromic bool
+}
+
+// ParseEnzyme parses the recognition site and cut positions of the named
+// enzyme, as specified in REBASE notation. Either the top strand cut is marked
+// by '^' within the site, in which case the bottom strand is cut symmetrically
+// (e.g. "G^AATTC"), or the cuts are given as offsets after the end of the site
+// on the top and bottom strand (e.g. "GGTCTC(1/5)").
+func ParseEnzyme(name, spec string) (*Enzyme, error) {
+	e := &Enzyme{Name: name}
+	spec = strings.ToUpper(strings.TrimSpace(spec))
+	switch {
+	case strings.HasSuffix(spec, ")"):
+		i := strings.IndexByte(spec, '(')
+		if i == -1 {
+			return nil, fmt.Errorf("restrict.ParseEnzyme: %s: invalid cut positions in %q; missing '('", name, spec)
+		}
+		e.Site = spec[:i]
+		cuts := strings.Split(spec[i+1:len(spec)-1], "/")
+		if len(cuts) != 2 {
+			return nil, fmt.Errorf("restrict.ParseEnzyme: %s: invalid cut positions in %q; expected top/bottom", name, spec)
+		}
+		top, err := strconv.Atoi(cuts[0])
+		if err != nil {
+			return nil, fmt.Errorf("restrict.ParseEnzyme: %s: invalid top strand cut position; %v", name, err)
+		}
+		bottom, err := strconv.Atoi(cuts[1])
+		if err != nil {
+			return nil, fmt.Errorf("restrict.ParseEnzyme: %s: invalid bottom strand cut position; %v", name, err)
+		}
+		e.Cut, e.RevCut = len(e.Site)+top, len(e.Site)+bottom
+	default:
+		i := strings.IndexByte(spec, '^')
+		if i == -1 {
+			return nil, fmt.Errorf("restrict.ParseEnzyme: %s: missing cut position in %q", name, spec)
+		}
+		e.Site = spec[:i] + spec[i+1:]
+		e.Cut, e.RevCut = i, len(e.Site)-i
+	}
+	if len(e.Site) == 0 {
+		return nil, fmt.Errorf("restrict.ParseEnzyme: %s: empty recognition site", name)
+	}
+	if _, err := seq.ParseDNA(e.Site); err != nil {
+		return nil, fmt.Errorf("restrict.ParseEnzyme: %s: invalid recognition site; %v", name, err)
+	}
+	if isDegenerate(e.Site) {
+		e.palindromic = e.Site == seq.DNA(e.Site).RevComp().String()
+	} else {
+		e.palindromic = rosa.IsRevPal(e.Site)
+	}
+	return e, nil
+}
+
+// isDegenerate reports whether the recognition site contains ambiguity codes.
+func isDegenerate(site string) bool {
+	return strings.Trim(site, "ACGT") != ""
+}
+
+// Palindromic reports whether the recognition site of the enzyme equals its
+// reverse complement, in which case the site is recognized on both strands at
+// the same position.
+func (e *Enzyme) Palindromic() bool {
+	return e.palindromic
+}
+
+// Overhang returns the length of the single-stranded overhang left by the
+// enzyme; positive for 5' overhangs, negative for 3' overhangs and 0 for blunt
+// ends.
+func (e *Enzyme) Overhang() int {
+	return e.RevCut - e.Cut
+}
+
+// String returns the recognition site and cut positions of the enzyme in
+// REBASE notation.
+func (e *Enzyme) String() string {
+	if 0 <= e.Cut && e.Cut <= len(e.Site) && e.RevCut == len(e.Site)-e.Cut {
+		return e.Site[:e.Cut] + "^" + e.Site[e.Cut:]
+	}
+	return fmt.Sprintf("%s(%d/%d)", e.Site, e.Cut-len(e.Site), e.RevCut-len(e.Site))
+}
+
+// enzymes maps from enzyme name to enzyme of the database.
+var enzymes = make(map[string]*Enzyme)
+
+func init() {
+	for _, def := range []struct{ name, spec string }{
+		// Palindromic recognition sites.
+		{"AluI", "AG^CT"},
+		{"ApaI", "GGGCC^C"},
+		{"BamHI", "G^GATCC"},
+		{"BglII", "A^GATCT"},
+		{"ClaI", "AT^CGAT"},
+		{"DpnII", "^GATC"},
+		{"EcoRI", "G^AATTC"},
+		{"EcoRV", "GAT^ATC"},
+		{"HaeIII", "GG^CC"},
+		{"HhaI", "GCG^C"},
+		{"HindIII", "A^AGCTT"},
+		{"HpaII", "C^CGG"},
+		{"KpnI", "GGTAC^C"},
+		{"MboI", "^GATC"},
+		{"MspI", "C^CGG"},
+		{"NcoI", "C^CATGG"},
+		{"NdeI", "CA^TATG"},
+		{"NheI", "G^CTAGC"},
+		{"NotI", "GC^GGCCGC"},
+		{"PstI", "CTGCA^G"},
+		{"PvuII", "CAG^CTG"},
+		{"SacI", "GAGCT^C"},
+		{"SalI", "G^TCGAC"},
+		{"Sau3AI", "^GATC"},
+		{"SmaI", "CCC^GGG"},
+		{"SpeI", "A^CTAGT"},
+		{"SphI", "GCATG^C"},
+		{"TaqI", "T^CGA"},
+		{"XbaI", "T^CTAGA"},
+		{"XhoI", "C^TCGAG"},
+		{"XmaI", "C^CCGGG"},
+		// Degenerate recognition sites.
+		{"AccI", "GT^MKAC"},
+		{"AvaI", "C^YCGRG"},
+		{"BanI", "G^GYRCC"},
+		{"BglI", "GCCNNNN^NGGC"},
+		{"BsaJI", "C^CNNGG"},
+		{"HaeII", "RGCGC^Y"},
+		{"HincII", "GTY^RAC"},
+		{"SfiI", "GGCCNNNN^NGGCC"},
+		{"StyI", "C^CWWGG"},
+		{"XmnI", "GAANN^NNTTC"},
+		// Non-palindromic recognition sites, cutting outside of the site.
+		{"BbsI", "GAAGAC(2/6)"},
+		{"BsaI", "GGTCTC(1/5)"},
+		{"BsmBI", "CGTCTC(1/5)"},
+		{"FokI", "GGATG(9/13)"},
+		{"HphI", "GGTGA(8/7)"},
+		{"MboII", "GAAGA(8/7)"},
+		{"MlyI", "GAGTC(5/5)"},
+		{"SapI", "GCTCTTC(1/4)"},
+	} {
+		e, err := ParseEnzyme(def.name, def.spec)
+		if err != nil {
+			panic(err)
+		}
+		enzymes[strings.ToLower(e.Name)] = e
+	}
+}
+
+// Lookup returns the enzyme of the database with the provided name, regardless
+// of case.
+func Lookup(name string) (*Enzyme, error) {
+	e, ok := enzymes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("restrict.Lookup: unknown enzyme %q", name)
+	}
+	return e, nil
+}
+
+// Enzymes returns the enzymes of the database, sorted by name.
+func Enzymes() []*Enzyme {
+	var es []*Enzyme
+	for _, e := range enzymes {
+		es = append(es, e)
+	}
+	sort.Slice(es, func(i, j int) bool { return es[i].Name < es[j].Name })
+	return es
+}
@@ -0,0 +1,64 @@
+package restrict_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mewmew/playground/rosalind/restrict"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+func ExampleDigest() {
+	dna, err := seq.ParseDNA("GGATCCAAAAGAATTCAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAGAATTCAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var enzymes []*restrict.Enzyme
+	for _, name := range []string{"EcoRI", "BamHI"} {
+		e, err := restrict.Lookup(name)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		enzymes = append(enzymes, e)
+	}
+	sites := restrict.Map(dna, enzymes, true)
+	for _, site := range sites {
+		fmt.Printf("%s %s at %d\n", site.Enzyme.Name, site.Enzyme, site.Cut)
+	}
+	frags := restrict.Digest(len(dna), sites, true)
+	if err := restrict.WriteTable(os.Stdout, frags); err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// BamHI G^GATCC at 1
+	// EcoRI G^AATTC at 11
+	// EcoRI G^AATTC at 49
+	//   #  Start  End  Length   Left  Right
+	//   1      2   11      10  BamHI  EcoRI
+	//   2     12   49      38  EcoRI  EcoRI
+	//   3     50    1      62  EcoRI  BamHI
+}
+
+func ExampleWriteGel() {
+	lanes := []restrict.Lane{
+		{Name: "ladder", Sizes: []int{10000, 5000, 3000, 2000, 1000, 500, 250}},
+		{Name: "EcoRI", Sizes: []int{4000, 1200, 1200}},
+		{Name: "BamHI", Sizes: []int{6400}},
+	}
+	if err := restrict.WriteGel(os.Stdout, lanes, 10); err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	//       bp  ladder  EcoRI   BamHI
+	//    10000  ======
+	//     6637                  ======
+	//     4405  ======  ======
+	//     2924  ======
+	//     1941  ======
+	//     1288          ######
+	//      855  ======
+	//      567  ======
+	//      377
+	//      250  ======
+}
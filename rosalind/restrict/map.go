@@ -0,0 +1,152 @@
+package restrict
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/mewmew/playground/rosalind/rosa"
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+// A Site is a recognition site of an enzyme in a DNA sequence.
+type Site struct {
+	// Enzyme which recognizes the site.
+	Enzyme *Enzyme
+	// Offset of the recognition site in the sequence. Sites of circular
+	// sequences may wrap around the end of the sequence.
+	Pos int
+	// Strand of the recognition site; '+' if it occurs in the sequence, and '-'
+	// if it occurs in the reverse complement. Palindromic sites are on the '+'
+	// strand.
+	Strand byte
+	// Cut and RevCut are the offsets of the cuts in the top and bottom strand of
+	// the sequence.
+	Cut, RevCut int
+}
+
+// Map locates the recognition sites of the enzymes in the DNA sequence, sorted
+// by cut offset. Sites are located on both strands, and of circular sequences
+// may wrap around the end of the sequence. Sites of linear sequences where the
+// enzyme would cut outside of the sequence are omitted.
+//
+// Bases of the sequence which are ambiguity codes never match recognition
+// sites.
+func Map(dna seq.DNA, enzymes []*Enzyme, circular bool) []*Site {
+	n := len(dna)
+	// Search the sequence extended by its start for circular sequences, so that
+	// sites may wrap around the end.
+	text := bytes.ToUpper(dna)
+	if circular && n > 0 {
+		maxLen := 0
+		for _, e := range enzymes {
+			if len(e.Site) > maxLen {
+				maxLen = len(e.Site)
+			}
+		}
+		for len(text) < n+maxLen-1 {
+			text = append(text, text[:n]...)
+		}
+		text = text[:n+maxLen-1]
+	}
+	f := &finder{text: text, n: n}
+	var sites []*Site
+	for _, e := range enzymes {
+		add := func(pos int, strand byte) {
+			site := &Site{Enzyme: e, Pos: pos, Strand: strand, Cut: pos + e.Cut, RevCut: pos + e.RevCut}
+			if strand == '-' {
+				l := len(e.Site)
+				site.Cut, site.RevCut = pos+l-e.RevCut, pos+l-e.Cut
+			}
+			if circular {
+				site.Cut, site.RevCut = mod(site.Cut, n), mod(site.RevCut, n)
+			} else if site.Cut <= 0 || site.Cut >= n || site.RevCut < 0 || site.RevCut > n {
+				return
+			}
+			sites = append(sites, site)
+		}
+		for _, pos := range f.find(e.Site) {
+			add(pos, '+')
+		}
+		if !e.palindromic {
+			for _, pos := range f.find(seq.DNA(e.Site).RevComp().String()) {
+				add(pos, '-')
+			}
+		}
+	}
+	sort.SliceStable(sites, func(i, j int) bool {
+		if sites[i].Cut != sites[j].Cut {
+			return sites[i].Cut < sites[j].Cut
+		}
+		return sites[i].Enzyme.Name < sites[j].Enzyme.Name
+	})
+	return sites
+}
+
+// A finder locates recognition sites in a sequence.
+type finder struct {
+	// Uppercase sequence, extended by its start if circular.
+	text []byte
+	// Length of the sequence.
+	n int
+	// Locations and lengths of the reverse palindromes of the text, or nil if
+	// not yet located.
+	palLocs, palNs []int
+}
+
+// find returns the offsets below n at which the recognition site occurs in the
+// text. Palindromic sites of unambiguous bases are located among the reverse
+// palindromes of the text.
+func (f *finder) find(site string) []int {
+	var locs []int
+	if !isDegenerate(site) && rosa.IsRevPal(site) && 4 <= len(site) && len(site) <= 12 {
+		if f.palLocs == nil {
+			f.palLocs, f.palNs = rosa.RevPal(string(f.text))
+		}
+		for i, loc := range f.palLocs {
+			if loc < f.n && f.palNs[i] == len(site) && string(f.text[loc:loc+len(site)]) == site {
+				locs = append(locs, loc)
+			}
+		}
+		return locs
+	}
+	for pos := 0; pos < f.n && pos+len(site) <= len(f.text); pos++ {
+		if matches(f.text[pos:pos+len(site)], site) {
+			locs = append(locs, pos)
+		}
+	}
+	return locs
+}
+
+// bases maps from nucleotide codes, including ambiguity codes, to the set of
+// bases they represent; A, C, G and T are represented by bit 0 to 3
+// respectively.
+var bases = map[byte]byte{
+	'A': 1, 'C': 2, 'G': 4, 'T': 8,
+	'R': 1 | 4, 'Y': 2 | 8, 'S': 2 | 4, 'W': 1 | 8, 'K': 4 | 8, 'M': 1 | 2,
+	'B': 2 | 4 | 8, 'D': 1 | 4 | 8, 'H': 1 | 2 | 8, 'V': 1 | 2 | 4, 'N': 1 | 2 | 4 | 8,
+}
+
+// matches reports whether the bases of s match the recognition site, which may
+// contain ambiguity codes.
+func matches(s []byte, site string) bool {
+	for i := range s {
+		switch s[i] {
+		case 'A', 'C', 'G', 'T':
+			if bases[s[i]]&bases[site[i]] == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// mod returns x modulo n, in the range [0, n).
+func mod(x, n int) int {
+	x %= n
+	if x < 0 {
+		x += n
+	}
+	return x
+}
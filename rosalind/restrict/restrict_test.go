@@ -0,0 +1,153 @@
+package restrict
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/playground/rosalind/seq"
+)
+
+func TestParseEnzyme(t *testing.T) {
+	golden := []struct {
+		spec        string
+		site        string
+		cut, revCut int
+		palindromic bool
+		overhang    int
+	}{
+		// i=0
+		{spec: "G^AATTC", site: "GAATTC", cut: 1, revCut: 5, palindromic: true, overhang: 4},
+		// i=1
+		{spec: "CTGCA^G", site: "CTGCAG", cut: 5, revCut: 1, palindromic: true, overhang: -4},
+		// i=2
+		{spec: "CCC^GGG", site: "CCCGGG", cut: 3, revCut: 3, palindromic: true, overhang: 0},
+		// i=3
+		{spec: "GTY^RAC", site: "GTYRAC", cut: 3, revCut: 3, palindromic: true, overhang: 0},
+		// i=4
+		{spec: "GGTCTC(1/5)", site: "GGTCTC", cut: 7, revCut: 11, palindromic: false, overhang: 4},
+		// i=5
+		{spec: "ggatg(9/13)", site: "GGATG", cut: 14, revCut: 18, palindromic: false, overhang: 4},
+		// i=6
+		{spec: "^GATC", site: "GATC", cut: 0, revCut: 4, palindromic: true, overhang: 4},
+	}
+	for i, g := range golden {
+		e, err := ParseEnzyme("test", g.spec)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if e.Site != g.site || e.Cut != g.cut || e.RevCut != g.revCut {
+			t.Errorf("i=%d: expected %s (%d/%d), got %s (%d/%d).", i, g.site, g.cut, g.revCut, e.Site, e.Cut, e.RevCut)
+		}
+		if e.Palindromic() != g.palindromic {
+			t.Errorf("i=%d: expected palindromic %v, got %v.", i, g.palindromic, e.Palindromic())
+		}
+		if e.Overhang() != g.overhang {
+			t.Errorf("i=%d: expected overhang %d, got %d.", i, g.overhang, e.Overhang())
+		}
+	}
+	for i, spec := range []string{"GAATTC", "GAATTC(1)", "GAXTTC^", "^", "GGTCTC(a/5)"} {
+		if _, err := ParseEnzyme("test", spec); err == nil {
+			t.Errorf("i=%d: expected error for %q, got nil.", i, spec)
+		}
+	}
+	// Each enzyme of the database is written in the notation it was parsed from.
+	for _, e := range Enzymes() {
+		e2, err := ParseEnzyme(e.Name, e.String())
+		if err != nil {
+			t.Errorf("%s: unexpected error; %v", e.Name, err)
+			continue
+		}
+		if *e2 != *e {
+			t.Errorf("%s: expected %v, got %v.", e.Name, e, e2)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	type cut struct {
+		name        string
+		pos         int
+		strand      byte
+		cut, revCut int
+	}
+	golden := []struct {
+		dna      string
+		enzymes  []string
+		circular bool
+		want     []cut
+	}{
+		// i=0
+		{dna: "AAGAATTCAAGGATCCAA", enzymes: []string{"EcoRI", "BamHI"}, want: []cut{{"EcoRI", 2, '+', 3, 7}, {"BamHI", 10, '+', 11, 15}}},
+		// i=1 Site wrapping around the end of a circular sequence.
+		{dna: "AATTCAAAAAAG", enzymes: []string{"EcoRI"}, circular: true, want: []cut{{"EcoRI", 11, '+', 0, 4}}},
+		// i=2
+		{dna: "AATTCAAAAAAG", enzymes: []string{"EcoRI"}, want: nil},
+		// i=3 Degenerate sites.
+		{dna: "ccgtcgaccc", enzymes: []string{"AccI", "HincII", "SalI"}, want: []cut{{"SalI", 2, '+', 3, 7}, {"AccI", 2, '+', 4, 6}, {"HincII", 2, '+', 5, 5}}},
+		// i=4 Ambiguity codes of the sequence never match.
+		{dna: "CCGTNGACCC", enzymes: []string{"HincII"}, want: nil},
+		// i=5 Non-palindromic site on the forward strand.
+		{dna: "AAGGTCTCAGATCAAAAA", enzymes: []string{"BsaI"}, want: []cut{{"BsaI", 2, '+', 9, 13}}},
+		// i=6 Non-palindromic site on the reverse strand.
+		{dna: "AAAAGATCGAGACCAA", enzymes: []string{"BsaI"}, want: []cut{{"BsaI", 8, '-', 3, 7}}},
+		// i=7 Cut outside of a linear sequence.
+		{dna: "AAGGTCTCA", enzymes: []string{"BsaI"}, want: nil},
+	}
+	for i, g := range golden {
+		var enzymes []*Enzyme
+		for _, name := range g.enzymes {
+			e, err := Lookup(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			enzymes = append(enzymes, e)
+		}
+		var got []cut
+		for _, site := range Map(seq.DNA(g.dna), enzymes, g.circular) {
+			got = append(got, cut{site.Enzyme.Name, site.Pos, site.Strand, site.Cut, site.RevCut})
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
+
+func TestDigest(t *testing.T) {
+	ecoRI, err := Lookup("EcoRI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := []struct {
+		dna      string
+		circular bool
+		want     []int
+	}{
+		// i=0
+		{dna: "AAGAATTCAAAAGAATTCAAAA", want: []int{3, 10, 9}},
+		// i=1
+		{dna: "AAGAATTCAAAAGAATTCAAAA", circular: true, want: []int{10, 12}},
+		// i=2
+		{dna: "AAAAAAAA", want: []int{8}},
+		// i=3
+		{dna: "AAAAAAAA", circular: true, want: []int{8}},
+		// i=4
+		{dna: "AATTCAAAAAAG", circular: true, want: []int{12}},
+	}
+	for i, g := range golden {
+		dna := seq.DNA(g.dna)
+		frags := Digest(len(dna), Map(dna, []*Enzyme{ecoRI}, g.circular), g.circular)
+		var got []int
+		total := 0
+		for _, frag := range frags {
+			got = append(got, frag.Len)
+			total += len(frag.Seq(dna))
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expected fragment sizes %v, got %v.", i, g.want, got)
+		}
+		if total != len(dna) {
+			t.Errorf("i=%d: expected fragments of total length %d, got %d.", i, len(dna), total)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package rosa
 
 import (
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"sort"
 	"strings"
 )
@@ -57,6 +59,73 @@ func ExampleParseFASTA() {
 	// CCTGCGGAAGATCGGCACTAGAATAGCCAGAACCGTTTCTCTGAGGCTTCCGGCCTTCCCTCCCACTAATAATTCTGAGG
 }
 
+func ExampleFASTAReader() {
+	fr := NewFASTAReader(strings.NewReader(s))
+	for {
+		rec, err := fr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Println(rec.ID, len(rec.Seq))
+	}
+	// Output:
+	// Rosalind_6404 80
+	// Rosalind_5959 84
+	// Rosalind_0808 87
+}
+
+func ExampleFASTAWriter() {
+	fw := NewFASTAWriter(os.Stdout)
+	fw.Width = 10
+	rec := &Record{
+		ID:   "Rosalind_6404",
+		Desc: "example record",
+		Seq:  []byte("CCTGCGGAAGATCGGCACTAGAATAG"),
+	}
+	if err := fw.Write(rec); err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// >Rosalind_6404 example record
+	// CCTGCGGAAG
+	// ATCGGCACTA
+	// GAATAG
+}
+
+func ExampleFASTQReader() {
+	const s = `@SEQ_ID
+GATTTGGGGTTCAAAGCAGTATCGATCAAATAGTAAATCCATTTGTTCAACTCACAGTTT
++
+!''*((((***+))%%%++)(%%%%).1***-+*''))**55CCF>>>>>>CCCCCCC65
+`
+	qr := NewFASTQReader(strings.NewReader(s), Phred33)
+	rec, err := qr.Read()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(rec.ID)
+	fmt.Println(rec.Qual[:8])
+
+	// Trim low quality bases from both ends of the read.
+	rec.Trim(20)
+	fmt.Println(string(rec.Seq))
+	// Output:
+	// SEQ_ID
+	// [0 6 6 9 7 7 7 7]
+	// ATTTGTTCAACTCACAGTTT
+}
+
+func ExampleQualStats() {
+	var qs QualStats
+	qs.Add(&FASTQRecord{Qual: []byte{30, 20, 10}})
+	qs.Add(&FASTQRecord{Qual: []byte{40, 30}})
+	fmt.Println(qs.Mean())
+	// Output: [35 25 10]
+}
+
 func ExampleFASTA_Label() {
 	// Parse FASTA.
 	fas, err := ParseFASTA(strings.NewReader(s))
@@ -2,16 +2,224 @@ package rosa
 
 import (
 	"bufio"
-	"errors"
+	"bytes"
 	"fmt"
 	"io"
-	"strings"
 )
 
+// A Record is a FASTA record, consisting of a header line and a sequence.
+type Record struct {
+	// ID is the identifier of the record; the first word of the header line,
+	// excluding the leading '>'.
+	ID string
+	// Desc is the description of the record; the remainder of the header line,
+	// excluding leading whitespace.
+	Desc string
+	// Seq is the sequence of the record, with line breaks removed.
+	Seq []byte
+}
+
+// Header returns the header line of the record, excluding the leading '>'.
+func (rec *Record) Header() string {
+	if len(rec.Desc) == 0 {
+		return rec.ID
+	}
+	return rec.ID + " " + rec.Desc
+}
+
+// A FASTAReader reads records from a FASTA file, one record at a time.
+//
+// Blank lines and comment lines starting with ';' are ignored, as is trailing
+// whitespace of sequence lines; lines may end with "\n" or "\r\n".
+type FASTAReader struct {
+	lineReader
+	// Header line of the next record, or nil at the start of the file.
+	header []byte
+	// Line number of the header line of the next record.
+	headerLine int
+	// Line number of the header line of the last record read.
+	recLine int
+	// Sticky error.
+	err error
+}
+
+// NewFASTAReader returns a new FASTA reader reading from r.
+func NewFASTAReader(r io.Reader) *FASTAReader {
+	return &FASTAReader{lineReader: newLineReader(r)}
+}
+
+// Read reads and returns the next record. Only the record being read is kept
+// in memory, and lines may be of any length. At the end of the file Read
+// returns io.EOF.
+func (fr *FASTAReader) Read() (rec *Record, err error) {
+	if fr.err != nil {
+		return nil, fr.err
+	}
+	rec, err = fr.read()
+	if err != nil {
+		fr.err = err
+		return nil, err
+	}
+	return rec, nil
+}
+
+// read reads and returns the next record.
+func (fr *FASTAReader) read() (*Record, error) {
+	// Locate the header line of the first record.
+	for fr.header == nil {
+		line, err := fr.readLine()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(line) == 0 || line[0] == ';':
+			continue
+		case line[0] != '>':
+			return nil, fr.errorf("sequence data before first header")
+		}
+		fr.header = append([]byte(nil), line...)
+		fr.headerLine = fr.line
+	}
+	rec := newRecord(fr.header)
+	if len(rec.ID) == 0 {
+		return nil, fmt.Errorf("rosa.FASTAReader.Read: line %d: invalid header; empty ID", fr.headerLine)
+	}
+	fr.recLine = fr.headerLine
+	fr.header = fr.header[:0]
+	for {
+		line, err := fr.readLine()
+		if err != nil {
+			if err == io.EOF {
+				fr.header = nil
+				// Return io.EOF on the next call.
+				fr.err = io.EOF
+				return rec, nil
+			}
+			return nil, err
+		}
+		switch {
+		case len(line) > 0 && line[0] == '>':
+			fr.header = append(fr.header, line...)
+			fr.headerLine = fr.line
+			return rec, nil
+		case len(line) > 0 && line[0] == ';':
+			continue
+		}
+		rec.Seq = append(rec.Seq, bytes.TrimRight(line, " \t\r")...)
+	}
+}
+
+// Line returns the line number of the header line of the last record read.
+func (fr *FASTAReader) Line() int {
+	return fr.recLine
+}
+
+// errorf returns an error at the current line.
+func (fr *FASTAReader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("rosa.FASTAReader.Read: line %d: %s", fr.line, fmt.Sprintf(format, args...))
+}
+
+// A lineReader reads lines of any length.
+type lineReader struct {
+	// Underlying reader.
+	r *bufio.Reader
+	// Current line number.
+	line int
+}
+
+// newLineReader returns a new line reader reading from r.
+func newLineReader(r io.Reader) lineReader {
+	return lineReader{r: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// readLine reads the next line, excluding the line break. The returned slice
+// is only valid until the next call to readLine.
+func (lr *lineReader) readLine() (line []byte, err error) {
+	line, isPrefix, err := lr.r.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	lr.line++
+	if !isPrefix {
+		return line, nil
+	}
+	// Join the fragments of lines longer than the read buffer.
+	buf := append([]byte(nil), line...)
+	for isPrefix {
+		line, isPrefix, err = lr.r.ReadLine()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		buf = append(buf, line...)
+	}
+	return buf, nil
+}
+
+// newRecord returns a new record of the provided header line.
+func newRecord(header []byte) *Record {
+	rec := new(Record)
+	rec.ID, rec.Desc = splitHeader(header[1:])
+	return rec
+}
+
+// splitHeader splits the header line, excluding its leading marker, into an
+// identifier and a description.
+func splitHeader(header []byte) (id, desc string) {
+	i := bytes.IndexAny(header, " \t")
+	if i == -1 {
+		return string(header), ""
+	}
+	return string(header[:i]), string(bytes.TrimLeft(header[i:], " \t"))
+}
+
+// A FASTAWriter writes records to a FASTA file.
+type FASTAWriter struct {
+	// Width specifies the maximum length of sequence lines; sequences are not
+	// wrapped if Width is zero.
+	Width int
+	// Underlying writer.
+	w io.Writer
+	// Output buffer of the current record.
+	buf []byte
+}
+
+// NewFASTAWriter returns a new FASTA writer writing to w, which wraps sequence
+// lines at 60 characters.
+func NewFASTAWriter(w io.Writer) *FASTAWriter {
+	return &FASTAWriter{Width: 60, w: w}
+}
+
+// Write writes the record to the FASTA file.
+func (fw *FASTAWriter) Write(rec *Record) error {
+	buf := append(fw.buf[:0], '>')
+	buf = append(buf, rec.Header()...)
+	buf = append(buf, '\n')
+	seq := rec.Seq
+	for len(seq) > 0 {
+		n := len(seq)
+		if fw.Width > 0 && n > fw.Width {
+			n = fw.Width
+		}
+		buf = append(buf, seq[:n]...)
+		buf = append(buf, '\n')
+		seq = seq[n:]
+	}
+	fw.buf = buf
+	_, err := fw.w.Write(buf)
+	return err
+}
+
 // FASTA handles labeled DNA sequences.
 type FASTA struct {
-	// Seqs is a map from FASTA label names to DNA sequences.
+	// Seqs is a map from FASTA record IDs to DNA sequences. If records with
+	// duplicate IDs are kept, Seqs holds the sequence of the first record with
+	// a given ID.
 	Seqs map[string]string
+	// Records holds the records of the FASTA file, in order of occurrence.
+	Records []*Record
 	// labels maps from index to label; it keeps track of the order in which
 	// labels occur in the FASTA file.
 	labels []string
@@ -19,31 +227,50 @@ type FASTA struct {
 	indices map[string]int
 }
 
+// FASTAOptions specify how FASTA files are parsed.
+type FASTAOptions struct {
+	// AllowDuplicates specifies whether records with duplicate IDs are kept as
+	// separate records, rather than reported as an error.
+	AllowDuplicates bool
+}
+
 // ParseFASTA reads data from r and parses it according to the FASTA file
-// format.
+// format. The label of each sequence is the ID of its record; the first word of
+// the header line. Duplicate IDs are reported as an error.
 func ParseFASTA(r io.Reader) (fas *FASTA, err error) {
+	return ParseFASTAWith(r, FASTAOptions{})
+}
+
+// ParseFASTAWith is like ParseFASTA but uses the provided options.
+func ParseFASTAWith(r io.Reader, opts FASTAOptions) (fas *FASTA, err error) {
 	fas = &FASTA{
 		Seqs:    make(map[string]string),
 		indices: make(map[string]int),
 	}
-	s := bufio.NewScanner(r)
-	var label string
-	index := 0
-	for s.Scan() {
-		line := s.Text()
-		if strings.HasPrefix(line, ">") {
-			label = line[1:]
-			fas.labels = append(fas.labels, label)
-			fas.indices[label] = index
-			index++
-			continue
+	fr := NewFASTAReader(r)
+	// lines maps from record ID to the line number of its first header line.
+	lines := make(map[string]int)
+	for {
+		rec, err := fr.Read()
+		if err != nil {
+			if err == io.EOF {
+				return fas, nil
+			}
+			return nil, err
 		}
-		if len(label) == 0 {
-			return nil, errors.New("rosa.ParseFASTA: invalid label; zero length")
+		label := rec.ID
+		fas.Records = append(fas.Records, rec)
+		fas.labels = append(fas.labels, label)
+		if line, ok := lines[label]; ok {
+			if !opts.AllowDuplicates {
+				return nil, fmt.Errorf("rosa.ParseFASTA: duplicate ID %q at line %d; previously defined at line %d", label, fr.Line(), line)
+			}
+			continue
 		}
-		fas.Seqs[label] += strings.Replace(line, "\n", "", -1)
+		lines[label] = fr.Line()
+		fas.indices[label] = len(fas.labels) - 1
+		fas.Seqs[label] = string(rec.Seq)
 	}
-	return fas, s.Err()
 }
 
 // Label returns the nth label of the FASTA file.
@@ -0,0 +1,227 @@
+package rosa
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFASTAReader(t *testing.T) {
+	// A long unwrapped sequence line exceeds the read buffer of the reader.
+	long := strings.Repeat("ACGT", 100000)
+	input := "\n>seq1 first  record\nACGT\nAC\n>seq2\n" + long + "\n>seq3\tthird\n"
+	want := []Record{
+		{ID: "seq1", Desc: "first  record", Seq: []byte("ACGTAC")},
+		{ID: "seq2", Seq: []byte(long)},
+		{ID: "seq3", Desc: "third"},
+	}
+	fr := NewFASTAReader(strings.NewReader(input))
+	for i, w := range want {
+		rec, err := fr.Read()
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		if rec.ID != w.ID || rec.Desc != w.Desc || !bytes.Equal(rec.Seq, w.Seq) {
+			t.Errorf("i=%d: expected %q %q (%d bases), got %q %q (%d bases).", i, w.ID, w.Desc, len(w.Seq), rec.ID, rec.Desc, len(rec.Seq))
+		}
+	}
+	if _, err := fr.Read(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v.", err)
+	}
+
+	// Sequence data before the first header.
+	fr = NewFASTAReader(strings.NewReader("ACGT\n>seq1\nACGT\n"))
+	if _, err := fr.Read(); err == nil || err == io.EOF {
+		t.Errorf("expected error for sequence data before first header, got %v.", err)
+	}
+}
+
+func TestFASTAWriterRoundTrip(t *testing.T) {
+	input := genome(2, 1000)
+	fr := NewFASTAReader(bytes.NewReader(input))
+	buf := new(bytes.Buffer)
+	fw := NewFASTAWriter(buf)
+	for {
+		rec, err := fr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := fw.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(buf.Bytes(), input) {
+		t.Errorf("round trip mismatch; expected %d bytes, got %d bytes.", len(input), buf.Len())
+	}
+}
+
+// genome returns a FASTA file of n random DNA sequences, each of the provided
+// length, wrapped at 60 characters.
+func genome(n, length int) []byte {
+	rnd := rand.New(rand.NewSource(1))
+	buf := new(bytes.Buffer)
+	fw := NewFASTAWriter(buf)
+	seq := make([]byte, length)
+	for i := 0; i < n; i++ {
+		for j := range seq {
+			seq[j] = "ACGT"[rnd.Intn(4)]
+		}
+		rec := &Record{ID: "chr" + strconv.Itoa(i+1), Desc: "synthetic chromosome", Seq: seq}
+		if err := fw.Write(rec); err != nil {
+			panic(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+var (
+	// benchGenome is a FASTA file of four sequences of 1 megabase each, which
+	// is generated on first use by benchmarkGenome.
+	benchGenome     []byte
+	benchGenomeOnce sync.Once
+)
+
+// benchmarkGenome returns the FASTA file used by benchmarks.
+func benchmarkGenome() []byte {
+	benchGenomeOnce.Do(func() {
+		benchGenome = genome(4, 1000000)
+	})
+	return benchGenome
+}
+
+func BenchmarkFASTAReader(b *testing.B) {
+	data := benchmarkGenome()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fr := NewFASTAReader(bytes.NewReader(data))
+		for {
+			_, err := fr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkParseFASTA(b *testing.B) {
+	data := benchmarkGenome()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseFASTA(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFASTAWriter(b *testing.B) {
+	data := benchmarkGenome()
+	var recs []*Record
+	fr := NewFASTAReader(bytes.NewReader(data))
+	for {
+		rec, err := fr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+		recs = append(recs, rec)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fw := NewFASTAWriter(ioutil.Discard)
+		for _, rec := range recs {
+			if err := fw.Write(rec); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestParseFASTAFiles(t *testing.T) {
+	golden := []struct {
+		path string
+		opts FASTAOptions
+		// Expected records, or substring of expected error.
+		want []Record
+		err  string
+	}{
+		// i=0
+		{
+			path: "testdata/crlf.fasta",
+			want: []Record{
+				{ID: "Rosalind_1", Desc: "first record", Seq: []byte("ACGTAC")},
+				{ID: "Rosalind_2", Seq: []byte("GGCC")},
+			},
+		},
+		// i=1
+		{path: "testdata/duplicate.fasta", err: `duplicate ID "Rosalind_1" at line 5; previously defined at line 1`},
+		// i=2
+		{
+			path: "testdata/duplicate.fasta",
+			opts: FASTAOptions{AllowDuplicates: true},
+			want: []Record{
+				{ID: "Rosalind_1", Seq: []byte("ACGT")},
+				{ID: "Rosalind_2", Seq: []byte("GG")},
+				{ID: "Rosalind_1", Desc: "again", Seq: []byte("TTTT")},
+			},
+		},
+		// i=3
+		{path: "testdata/no_header.fasta", err: "line 1: sequence data before first header"},
+		// i=4
+		{path: "testdata/empty_id.fasta", err: "line 3: invalid header; empty ID"},
+	}
+	for i, g := range golden {
+		f, err := os.Open(g.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fas, err := ParseFASTAWith(f, g.opts)
+		f.Close()
+		if g.err != "" {
+			if err == nil || !strings.Contains(err.Error(), g.err) {
+				t.Errorf("i=%d: expected error containing %q, got %v.", i, g.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		var got []Record
+		for _, rec := range fas.Records {
+			got = append(got, *rec)
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expected %q, got %q.", i, g.want, got)
+		}
+		// The sequence and index of duplicate IDs are those of the first record.
+		for j, rec := range g.want {
+			if label, _ := fas.Label(j); label != rec.ID {
+				t.Errorf("i=%d: expected label %q at index %d, got %q.", i, rec.ID, j, label)
+			}
+			if n, _ := fas.Index(rec.ID); n > j {
+				t.Errorf("i=%d: expected index of %q at most %d, got %d.", i, rec.ID, j, n)
+			}
+		}
+		if fas.Seqs["Rosalind_1"] != string(g.want[0].Seq) {
+			t.Errorf("i=%d: expected sequence %q of Rosalind_1, got %q.", i, g.want[0].Seq, fas.Seqs["Rosalind_1"])
+		}
+	}
+}
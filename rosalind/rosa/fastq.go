@@ -0,0 +1,252 @@
+package rosa
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// A QualEncoding specifies the ASCII offset of encoded Phred quality scores.
+type QualEncoding byte
+
+// Quality encodings.
+const (
+	// Phred33 is used by Sanger and Illumina 1.8+ sequencers; scores 0 to 93
+	// are encoded as '!' to '~'.
+	Phred33 QualEncoding = 33
+	// Phred64 is used by Illumina 1.3 to 1.7 sequencers; scores 0 to 62 are
+	// encoded as '@' to '~'.
+	Phred64 QualEncoding = 64
+)
+
+// MaxQual returns the maximum quality score of the encoding.
+func (enc QualEncoding) MaxQual() int {
+	return '~' - int(enc)
+}
+
+// decode decodes the quality character c.
+func (enc QualEncoding) decode(c byte) (qual byte, ok bool) {
+	if c < byte(enc) || c > '~' {
+		return 0, false
+	}
+	return c - byte(enc), true
+}
+
+// A FASTQRecord is a FASTQ record; a sequencing read with a quality score per
+// base.
+type FASTQRecord struct {
+	Record
+	// Qual holds the decoded Phred quality score of each base of the sequence.
+	Qual []byte
+}
+
+// A FASTQReader reads records from a FASTQ file, one record at a time.
+type FASTQReader struct {
+	lineReader
+	// Quality encoding.
+	enc QualEncoding
+	// Sticky error.
+	err error
+}
+
+// NewFASTQReader returns a new FASTQ reader reading from r, which decodes
+// quality scores using the provided encoding.
+func NewFASTQReader(r io.Reader, enc QualEncoding) *FASTQReader {
+	return &FASTQReader{lineReader: newLineReader(r), enc: enc}
+}
+
+// Read reads and returns the next record. Sequence and quality lines may be
+// wrapped, and the sequence identifier after the '+' is optional. At the end of
+// the file Read returns io.EOF.
+func (qr *FASTQReader) Read() (rec *FASTQRecord, err error) {
+	if qr.err != nil {
+		return nil, qr.err
+	}
+	rec, err = qr.read()
+	if err != nil {
+		qr.err = err
+		return nil, err
+	}
+	return rec, nil
+}
+
+// read reads and returns the next record.
+func (qr *FASTQReader) read() (*FASTQRecord, error) {
+	// Header line.
+	var line []byte
+	for len(line) == 0 {
+		var err error
+		line, err = qr.readLine()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if line[0] != '@' {
+		return nil, qr.errorf("invalid header; expected '@', got %q", line[0])
+	}
+	header := append([]byte(nil), line[1:]...)
+	rec := new(FASTQRecord)
+	rec.ID, rec.Desc = splitHeader(header)
+
+	// Sequence lines, up to the separator line.
+	for {
+		line, err := qr.readLine()
+		if err != nil {
+			return nil, qr.unexpected(err)
+		}
+		if len(line) > 0 && line[0] == '+' {
+			if len(line) > 1 && !bytes.Equal(line[1:], header) {
+				return nil, qr.errorf("separator %q does not match header %q", line[1:], header)
+			}
+			break
+		}
+		rec.Seq = append(rec.Seq, line...)
+	}
+
+	// Quality lines; as '@' is a valid quality character, quality lines are
+	// read until the quality string is as long as the sequence.
+	rec.Qual = make([]byte, 0, len(rec.Seq))
+	for len(rec.Qual) < len(rec.Seq) {
+		line, err := qr.readLine()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(line) == 0 {
+			// Report short quality strings at the end of the file or before a
+			// blank line as length mismatches below.
+			break
+		}
+		for col, c := range line {
+			q, ok := qr.enc.decode(c)
+			if !ok {
+				return nil, qr.errorf("invalid quality character %q at column %d for Phred+%d encoding", c, col+1, qr.enc)
+			}
+			rec.Qual = append(rec.Qual, q)
+		}
+	}
+	if len(rec.Qual) != len(rec.Seq) {
+		return nil, qr.errorf("quality length %d of record %q does not match sequence length %d", len(rec.Qual), rec.ID, len(rec.Seq))
+	}
+	return rec, nil
+}
+
+// errorf returns an error at the current line.
+func (qr *FASTQReader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("rosa.FASTQReader.Read: line %d: %s", qr.line, fmt.Sprintf(format, args...))
+}
+
+// unexpected returns an error for an incomplete record if err is io.EOF, and
+// err otherwise.
+func (qr *FASTQReader) unexpected(err error) error {
+	if err == io.EOF {
+		return qr.errorf("incomplete record; %v", io.ErrUnexpectedEOF)
+	}
+	return err
+}
+
+// A FASTQWriter writes records to a FASTQ file.
+type FASTQWriter struct {
+	// Underlying writer.
+	w io.Writer
+	// Quality encoding.
+	enc QualEncoding
+	// Output buffer of the current record.
+	buf []byte
+}
+
+// NewFASTQWriter returns a new FASTQ writer writing to w, which encodes quality
+// scores using the provided encoding.
+func NewFASTQWriter(w io.Writer, enc QualEncoding) *FASTQWriter {
+	return &FASTQWriter{w: w, enc: enc}
+}
+
+// Write writes the record to the FASTQ file, using one line for the sequence
+// and one for the quality scores.
+func (qw *FASTQWriter) Write(rec *FASTQRecord) error {
+	if len(rec.Qual) != len(rec.Seq) {
+		return fmt.Errorf("rosa.FASTQWriter.Write: quality length %d of record %q does not match sequence length %d", len(rec.Qual), rec.ID, len(rec.Seq))
+	}
+	buf := append(qw.buf[:0], '@')
+	buf = append(buf, rec.Header()...)
+	buf = append(buf, '\n')
+	buf = append(buf, rec.Seq...)
+	buf = append(buf, "\n+\n"...)
+	for i, q := range rec.Qual {
+		if int(q) > qw.enc.MaxQual() {
+			return fmt.Errorf("rosa.FASTQWriter.Write: invalid quality score %d at position %d of record %q; above maximum %d of Phred+%d encoding", q, i+1, rec.ID, qw.enc.MaxQual(), qw.enc)
+		}
+		buf = append(buf, q+byte(qw.enc))
+	}
+	buf = append(buf, '\n')
+	qw.buf = buf
+	_, err := qw.w.Write(buf)
+	return err
+}
+
+// QualTrim returns the bounds [start, end) of the part of a read which remains
+// after quality trimming both of its ends. The 3' end is trimmed as by BWA
+// (bwa aln -q), up to the position which maximizes the sum of threshold minus
+// the quality score of each trimmed base. Unlike BWA, which leaves the 5' end
+// untouched, the same rule is then applied to the 5' end of the remaining
+// part.
+func QualTrim(qual []byte, threshold int) (start, end int) {
+	end = len(qual)
+	sum, max := 0, 0
+	for i := len(qual) - 1; i >= 0; i-- {
+		sum += threshold - int(qual[i])
+		if sum < 0 {
+			break
+		}
+		if sum > max {
+			max, end = sum, i
+		}
+	}
+	sum, max = 0, 0
+	for i := 0; i < end; i++ {
+		sum += threshold - int(qual[i])
+		if sum < 0 {
+			break
+		}
+		if sum > max {
+			max, start = sum, i+1
+		}
+	}
+	return start, end
+}
+
+// Trim trims both ends of the read by quality; see QualTrim.
+func (rec *FASTQRecord) Trim(threshold int) {
+	start, end := QualTrim(rec.Qual, threshold)
+	rec.Seq = rec.Seq[start:end]
+	rec.Qual = rec.Qual[start:end]
+}
+
+// QualStats records per-position quality statistics of reads.
+type QualStats struct {
+	// Sum of quality scores per position.
+	sum []int64
+	// Number of reads covering each position.
+	n []int
+}
+
+// Add adds the quality scores of the read to the statistics.
+func (qs *QualStats) Add(rec *FASTQRecord) {
+	for len(qs.sum) < len(rec.Qual) {
+		qs.sum = append(qs.sum, 0)
+		qs.n = append(qs.n, 0)
+	}
+	for i, q := range rec.Qual {
+		qs.sum[i] += int64(q)
+		qs.n[i]++
+	}
+}
+
+// Mean returns the mean quality score of each position, over the reads which
+// cover the position.
+func (qs *QualStats) Mean() []float64 {
+	mean := make([]float64, len(qs.sum))
+	for i, sum := range qs.sum {
+		mean[i] = float64(sum) / float64(qs.n[i])
+	}
+	return mean
+}
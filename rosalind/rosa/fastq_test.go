@@ -0,0 +1,165 @@
+package rosa
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFASTQReader(t *testing.T) {
+	// The second record is wrapped, repeats its identifier after the '+' and
+	// has quality lines starting with '@'.
+	input := "@read1 lane 1\nACGT\n+\nII5!\n\n@read2\nAC\nGTA\n+read2\n@@\n@AB\n"
+	golden := []struct {
+		enc  QualEncoding
+		want []FASTQRecord
+	}{
+		// i=0
+		{
+			enc: Phred33,
+			want: []FASTQRecord{
+				{Record: Record{ID: "read1", Desc: "lane 1", Seq: []byte("ACGT")}, Qual: []byte{40, 40, 20, 0}},
+				{Record: Record{ID: "read2", Seq: []byte("ACGTA")}, Qual: []byte{31, 31, 31, 32, 33}},
+			},
+		},
+	}
+	for i, g := range golden {
+		qr := NewFASTQReader(strings.NewReader(input), g.enc)
+		var got []FASTQRecord
+		for {
+			rec, err := qr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("i=%d: %v", i, err)
+			}
+			got = append(got, *rec)
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expected %+v, got %+v.", i, g.want, got)
+		}
+	}
+
+	// Phred+64 encoded qualities.
+	qr := NewFASTQReader(strings.NewReader("@r\nACG\n+\nh@B\n"), Phred64)
+	rec, err := qr.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{40, 0, 2}; !bytes.Equal(rec.Qual, want) {
+		t.Errorf("expected Phred+64 qualities %v, got %v.", want, rec.Qual)
+	}
+}
+
+func TestFASTQReaderMalformed(t *testing.T) {
+	golden := []struct {
+		input string
+		enc   QualEncoding
+		// Substring of the expected error message.
+		want string
+	}{
+		// i=0
+		{input: ">r\nACGT\n+\nIIII\n", enc: Phred33, want: "line 1: invalid header"},
+		// i=1
+		{input: "@r\nACGT\n+\nIII\n", enc: Phred33, want: "quality length 3"},
+		// i=2
+		{input: "@r\nACGT\n+\nIIIII\n", enc: Phred33, want: "quality length 5"},
+		// i=3
+		{input: "@r\nACGT\n", enc: Phred33, want: "incomplete record"},
+		// i=4
+		{input: "@r\nACGT\n+\n", enc: Phred33, want: "quality length 0"},
+		// i=5
+		{input: "@r\nACGT\n+s\nIIII\n", enc: Phred33, want: `separator "s" does not match`},
+		// i=6; '5' is below the Phred+64 offset.
+		{input: "@r\nACGT\n+\nhh5h\n", enc: Phred64, want: "invalid quality character '5' at column 3"},
+		// i=7
+		{input: "@r\nAC\n+\nI\x7f\n", enc: Phred33, want: "invalid quality character"},
+	}
+	for i, g := range golden {
+		qr := NewFASTQReader(strings.NewReader(g.input), g.enc)
+		_, err := qr.Read()
+		if err == nil || !strings.Contains(err.Error(), g.want) {
+			t.Errorf("i=%d: expected error containing %q, got %v.", i, g.want, err)
+		}
+		// Errors are sticky.
+		if _, err2 := qr.Read(); err2 != err {
+			t.Errorf("i=%d: expected sticky error %v, got %v.", i, err, err2)
+		}
+	}
+}
+
+func TestFASTQWriter(t *testing.T) {
+	input := "@read1 lane 1\nACGT\n+\nII5!\n@read2\nACGTA\n+\n@@@AB\n"
+	for _, enc := range []QualEncoding{Phred33, Phred64} {
+		qr := NewFASTQReader(strings.NewReader(input), Phred33)
+		buf := new(bytes.Buffer)
+		qw := NewFASTQWriter(buf, enc)
+		for {
+			rec, err := qr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := qw.Write(rec); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Converting back to Phred+33 yields the input.
+		qr = NewFASTQReader(buf, enc)
+		buf2 := new(bytes.Buffer)
+		qw = NewFASTQWriter(buf2, Phred33)
+		for {
+			rec, err := qr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Phred+%d: %v", enc, err)
+			}
+			if err := qw.Write(rec); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if buf2.String() != input {
+			t.Errorf("Phred+%d: round trip mismatch; expected %q, got %q.", enc, input, buf2.String())
+		}
+	}
+
+	// Scores above the maximum of the encoding.
+	rec := &FASTQRecord{Record: Record{ID: "r", Seq: []byte("A")}, Qual: []byte{70}}
+	if err := NewFASTQWriter(new(bytes.Buffer), Phred64).Write(rec); err == nil {
+		t.Error("expected error for quality score 70 in Phred+64 encoding, got nil.")
+	}
+}
+
+func TestQualTrim(t *testing.T) {
+	golden := []struct {
+		qual       []byte
+		threshold  int
+		start, end int
+	}{
+		// i=0
+		{qual: []byte{40, 40, 40}, threshold: 20, start: 0, end: 3},
+		// i=1
+		{qual: []byte{40, 40, 10, 2}, threshold: 20, start: 0, end: 2},
+		// i=2; a single good base does not stop trimming.
+		{qual: []byte{40, 40, 40, 5, 25, 2, 2}, threshold: 20, start: 0, end: 3},
+		// i=3
+		{qual: []byte{2, 10, 30, 30, 30}, threshold: 20, start: 2, end: 5},
+		// i=4
+		{qual: []byte{2, 2, 2}, threshold: 20, start: 0, end: 0},
+		// i=5
+		{qual: nil, threshold: 20, start: 0, end: 0},
+	}
+	for i, g := range golden {
+		start, end := QualTrim(g.qual, g.threshold)
+		if start != g.start || end != g.end {
+			t.Errorf("i=%d: expected [%d, %d), got [%d, %d).", i, g.start, g.end, start, end)
+		}
+	}
+}
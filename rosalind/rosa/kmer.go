@@ -0,0 +1,134 @@
+package rosa
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A KmerPos is the position of a k-mer in a group of sequences.
+type KmerPos struct {
+	// Index of the sequence.
+	Seq int
+	// Offset of the k-mer in the sequence, starting at 0.
+	Pos int
+}
+
+// A KmerIndex maps from each k-mer of a group of DNA sequences to the positions
+// at which it occurs. K-mers containing characters other than the bases A, C, G
+// and T are not indexed.
+type KmerIndex struct {
+	// K is the length of the indexed k-mers.
+	K int
+	// Canonical specifies whether k-mers are indexed by their canonical form;
+	// the lexicographically smaller of the k-mer and its reverse complement.
+	Canonical bool
+	// pos maps from k-mer to positions, in order of occurrence.
+	pos map[string][]KmerPos
+}
+
+// NewKmerIndex returns a new index of the k-mers of the provided DNA sequences,
+// optionally indexed by their canonical form.
+func NewKmerIndex(seqs []string, k int, canonical bool) (*KmerIndex, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("rosa.NewKmerIndex: invalid k-mer length %d", k)
+	}
+	idx := &KmerIndex{K: k, Canonical: canonical, pos: make(map[string][]KmerPos)}
+	for i, seq := range seqs {
+		// Length of the run of valid bases ending at the current position.
+		run := 0
+		for j := 0; j < len(seq); j++ {
+			if !isBase(seq[j]) {
+				run = 0
+				continue
+			}
+			run++
+			if run < k {
+				continue
+			}
+			kmer := seq[j+1-k : j+1]
+			if canonical {
+				kmer = Canonical(kmer)
+			}
+			idx.pos[kmer] = append(idx.pos[kmer], KmerPos{Seq: i, Pos: j + 1 - k})
+		}
+	}
+	return idx, nil
+}
+
+// isBase reports whether c is one of the bases A, C, G and T.
+func isBase(c byte) bool {
+	switch c {
+	case 'A', 'C', 'G', 'T':
+		return true
+	}
+	return false
+}
+
+// Canonical returns the canonical form of the provided k-mer; the
+// lexicographically smaller of the k-mer and its reverse complement.
+func Canonical(kmer string) string {
+	if revc := RevComp(kmer); revc < kmer {
+		return revc
+	}
+	return kmer
+}
+
+// Count returns the number of occurrences of the k-mer, including occurrences
+// of its reverse complement if the index is canonical.
+func (idx *KmerIndex) Count(kmer string) int {
+	return len(idx.Positions(kmer))
+}
+
+// Positions returns the positions of the k-mer, including positions of its
+// reverse complement if the index is canonical. The returned slice should not
+// be modified.
+func (idx *KmerIndex) Positions(kmer string) []KmerPos {
+	if idx.Canonical {
+		kmer = Canonical(kmer)
+	}
+	return idx.pos[kmer]
+}
+
+// Kmers returns the indexed k-mers in lexicographic order.
+func (idx *KmerIndex) Kmers() []string {
+	kmers := make([]string, 0, len(idx.pos))
+	for kmer := range idx.pos {
+		kmers = append(kmers, kmer)
+	}
+	sort.Strings(kmers)
+	return kmers
+}
+
+// KmerComposition returns the number of occurrences of each of the 4^k k-mers
+// over the bases A, C, G and T in the DNA sequence, in lexicographic order of
+// the k-mers. K-mers containing other characters are not counted.
+func KmerComposition(dna string, k int) ([]int, error) {
+	if k < 1 || k > 15 {
+		return nil, fmt.Errorf("rosa.KmerComposition: invalid k-mer length %d; expected 1 to 15", k)
+	}
+	counts := make([]int, 1<<(2*uint(k)))
+	mask := len(counts) - 1
+	code, run := 0, 0
+	for i := 0; i < len(dna); i++ {
+		var b int
+		switch dna[i] {
+		case 'A':
+			b = 0
+		case 'C':
+			b = 1
+		case 'G':
+			b = 2
+		case 'T':
+			b = 3
+		default:
+			run = 0
+			continue
+		}
+		code = (code<<2 | b) & mask
+		run++
+		if run >= k {
+			counts[code]++
+		}
+	}
+	return counts, nil
+}
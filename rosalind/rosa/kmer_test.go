@@ -0,0 +1,60 @@
+package rosa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKmerIndex(t *testing.T) {
+	seqs := []string{"ACGTTACG", "CGTANNACG"}
+	idx, err := NewKmerIndex(seqs, 3, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := []struct {
+		kmer string
+		want []KmerPos
+	}{
+		// i=0
+		{kmer: "ACG", want: []KmerPos{{0, 0}, {0, 5}, {1, 6}}},
+		// i=1
+		{kmer: "CGT", want: []KmerPos{{0, 1}, {1, 0}}},
+		// i=2
+		{kmer: "TAN", want: nil},
+		// i=3
+		{kmer: "CCC", want: nil},
+	}
+	for i, g := range golden {
+		if got := idx.Positions(g.kmer); !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expected positions %v of %q, got %v.", i, g.want, g.kmer, got)
+		}
+	}
+	want := []string{"ACG", "CGT", "GTA", "GTT", "TAC", "TTA"}
+	if got := idx.Kmers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected k-mers %v, got %v.", want, got)
+	}
+
+	// Canonical k-mers; ACG is the reverse complement of CGT.
+	idx, err = NewKmerIndex(seqs, 3, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := idx.Count("CGT"); got != 5 {
+		t.Errorf("expected 5 canonical occurrences of CGT, got %d.", got)
+	}
+	if _, err := NewKmerIndex(seqs, 0, false); err == nil {
+		t.Errorf("expected error for k-mer length 0, got nil.")
+	}
+}
+
+func TestKmerComposition(t *testing.T) {
+	counts, err := KmerComposition("ACGTNAAA", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// AA AC AG AT CA CC CG CT GA GC GG GT TA TC TG TT
+	want := []int{2, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("expected %v, got %v.", want, counts)
+	}
+}
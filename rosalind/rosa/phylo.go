@@ -0,0 +1,224 @@
+package rosa
+
+import (
+	"fmt"
+	"math"
+)
+
+// PDistance returns the p-distance matrix of the provided sequences, which must
+// be of equal length; the fraction of positions at which each pair of sequences
+// differ.
+func PDistance(seqs []string) ([][]float64, error) {
+	dist := newMatrix(len(seqs))
+	for i := range seqs {
+		for j := i + 1; j < len(seqs); j++ {
+			n, err := HamDist(seqs[i], seqs[j])
+			if err != nil {
+				return nil, fmt.Errorf("rosa.PDistance: sequences %d and %d; %v", i, j, err)
+			}
+			var p float64
+			if len(seqs[i]) > 0 {
+				p = float64(n) / float64(len(seqs[i]))
+			}
+			dist[i][j], dist[j][i] = p, p
+		}
+	}
+	return dist, nil
+}
+
+// JukesCantor returns the Jukes-Cantor distance matrix of the provided
+// sequences, which must be of equal length; the p-distances corrected for
+// multiple substitutions at the same position, d = -3/4 ln(1 - 4/3 p). The
+// distance is +Inf for pairs with p >= 0.75, which UPGMA and NeighborJoining
+// reject.
+func JukesCantor(seqs []string) ([][]float64, error) {
+	dist, err := PDistance(seqs)
+	if err != nil {
+		return nil, fmt.Errorf("rosa.JukesCantor: %v", err)
+	}
+	for i := range dist {
+		for j, p := range dist[i] {
+			if i == j {
+				continue
+			}
+			if p >= 0.75 {
+				dist[i][j] = math.Inf(1)
+				continue
+			}
+			dist[i][j] = -0.75 * math.Log(1-4*p/3)
+		}
+	}
+	return dist, nil
+}
+
+// newMatrix returns a zeroed n×n matrix.
+func newMatrix(n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+	}
+	return m
+}
+
+// checkMatrix validates the dimensions and the distances of the distance
+// matrix of the named taxa; distances must be finite.
+func checkMatrix(names []string, dist [][]float64) error {
+	if len(names) == 0 {
+		return fmt.Errorf("no taxa")
+	}
+	if len(dist) != len(names) {
+		return fmt.Errorf("distance matrix has %d rows; expected %d", len(dist), len(names))
+	}
+	for i, row := range dist {
+		if len(row) != len(names) {
+			return fmt.Errorf("row %d of distance matrix has %d columns; expected %d", i, len(row), len(names))
+		}
+		for j, d := range row {
+			if math.IsInf(d, 0) || math.IsNaN(d) {
+				return fmt.Errorf("invalid distance %v between taxa %q and %q", d, names[i], names[j])
+			}
+		}
+	}
+	return nil
+}
+
+// UPGMA constructs a rooted ultrametric tree of the named taxa from their
+// distance matrix, using the unweighted pair group method with arithmetic mean.
+// The branch lengths are the differences in height between the nodes, where the
+// height of the node joining two clusters is half their distance.
+func UPGMA(names []string, dist [][]float64) (*Node, error) {
+	if err := checkMatrix(names, dist); err != nil {
+		return nil, fmt.Errorf("rosa.UPGMA: %v", err)
+	}
+	n := len(names)
+	d := newMatrix(n)
+	for i := range d {
+		copy(d[i], dist[i])
+	}
+	nodes := make([]*Node, n)
+	heights := make([]float64, n)
+	sizes := make([]int, n)
+	// active holds the indices of the remaining clusters.
+	active := make([]int, n)
+	for i, name := range names {
+		nodes[i] = &Node{Name: name}
+		sizes[i] = 1
+		active[i] = i
+	}
+	for len(active) > 1 {
+		// Locate the closest pair of clusters; ties are broken by index.
+		ai, aj := 0, 1
+		for x := range active {
+			for y := x + 1; y < len(active); y++ {
+				if d[active[x]][active[y]] < d[active[ai]][active[aj]] {
+					ai, aj = x, y
+				}
+			}
+		}
+		i, j := active[ai], active[aj]
+		h := d[i][j] / 2
+		parent := new(Node)
+		for _, c := range []int{i, j} {
+			nodes[c].Length, nodes[c].HasLength = h-heights[c], true
+			parent.AddChild(nodes[c])
+		}
+		// Reuse the slot of cluster i for the joined cluster.
+		for _, k := range active {
+			if k == i || k == j {
+				continue
+			}
+			dk := (d[i][k]*float64(sizes[i]) + d[j][k]*float64(sizes[j])) / float64(sizes[i]+sizes[j])
+			d[i][k], d[k][i] = dk, dk
+		}
+		nodes[i], heights[i] = parent, h
+		sizes[i] += sizes[j]
+		active = append(active[:aj], active[aj+1:]...)
+	}
+	return nodes[active[0]], nil
+}
+
+// NeighborJoining constructs an unrooted tree of the named taxa from their
+// distance matrix, using the neighbor-joining method of Saitou and Nei. The
+// returned tree is rooted at the internal node joining the last three
+// clusters.
+func NeighborJoining(names []string, dist [][]float64) (*Node, error) {
+	if err := checkMatrix(names, dist); err != nil {
+		return nil, fmt.Errorf("rosa.NeighborJoining: %v", err)
+	}
+	n := len(names)
+	d := newMatrix(n)
+	for i := range d {
+		copy(d[i], dist[i])
+	}
+	nodes := make([]*Node, n)
+	active := make([]int, n)
+	for i, name := range names {
+		nodes[i] = &Node{Name: name}
+		active[i] = i
+	}
+	switch n {
+	case 1:
+		return nodes[0], nil
+	case 2:
+		root := new(Node)
+		for _, c := range nodes {
+			c.Length, c.HasLength = d[0][1]/2, true
+			root.AddChild(c)
+		}
+		return root, nil
+	}
+	for len(active) > 3 {
+		// Net divergence of each cluster.
+		r := make(map[int]float64)
+		for _, i := range active {
+			for _, k := range active {
+				r[i] += d[i][k]
+			}
+		}
+		// Locate the pair minimizing Q(i, j) = (m-2) d(i, j) - r(i) - r(j).
+		m := float64(len(active))
+		ai, aj := 0, 1
+		q := func(x, y int) float64 {
+			i, j := active[x], active[y]
+			return (m-2)*d[i][j] - r[i] - r[j]
+		}
+		for x := range active {
+			for y := x + 1; y < len(active); y++ {
+				if q(x, y) < q(ai, aj) {
+					ai, aj = x, y
+				}
+			}
+		}
+		i, j := active[ai], active[aj]
+		li := d[i][j]/2 + (r[i]-r[j])/(2*(m-2))
+		lj := d[i][j] - li
+		parent := new(Node)
+		nodes[i].Length, nodes[i].HasLength = li, true
+		nodes[j].Length, nodes[j].HasLength = lj, true
+		parent.AddChild(nodes[i])
+		parent.AddChild(nodes[j])
+		// Reuse the slot of cluster i for the joined cluster.
+		for _, k := range active {
+			if k == i || k == j {
+				continue
+			}
+			dk := (d[i][k] + d[j][k] - d[i][j]) / 2
+			d[i][k], d[k][i] = dk, dk
+		}
+		nodes[i] = parent
+		active = append(active[:aj], active[aj+1:]...)
+	}
+	// Join the last three clusters at a central node.
+	a, b, c := active[0], active[1], active[2]
+	lengths := []float64{
+		(d[a][b] + d[a][c] - d[b][c]) / 2,
+		(d[a][b] + d[b][c] - d[a][c]) / 2,
+		(d[a][c] + d[b][c] - d[a][b]) / 2,
+	}
+	root := new(Node)
+	for x, k := range active {
+		nodes[k].Length, nodes[k].HasLength = lengths[x], true
+		root.AddChild(nodes[k])
+	}
+	return root, nil
+}
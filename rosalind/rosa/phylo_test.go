@@ -0,0 +1,120 @@
+package rosa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPDistance(t *testing.T) {
+	seqs := []string{"TTTCCATTTA", "GATTCATTTC", "TTTCCATTTT", "GTTCCATTTA"}
+	dist, err := PDistance(seqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]float64{
+		{0, 0.4, 0.1, 0.1},
+		{0.4, 0, 0.4, 0.3},
+		{0.1, 0.4, 0, 0.2},
+		{0.1, 0.3, 0.2, 0},
+	}
+	checkDist(t, dist, want)
+	if _, err := PDistance([]string{"ACGT", "ACG"}); err == nil {
+		t.Errorf("expected error for sequences of different length, got nil.")
+	}
+}
+
+func TestJukesCantor(t *testing.T) {
+	dist, err := JukesCantor([]string{"AAAA", "AAAC", "ACGT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := -0.75 * math.Log(1-4*0.25/3)
+	want := [][]float64{
+		{0, d, math.Inf(1)},
+		{d, 0, math.Inf(1)},
+		{math.Inf(1), math.Inf(1), 0},
+	}
+	checkDist(t, dist, want)
+}
+
+func TestUPGMA(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+	dist := [][]float64{
+		{0, 17, 21, 31, 23},
+		{17, 0, 30, 34, 21},
+		{21, 30, 0, 28, 39},
+		{31, 34, 28, 0, 43},
+		{23, 21, 39, 43, 0},
+	}
+	root, err := UPGMA(names, dist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "(((a:8.5,b:8.5):2.5,e:11):5.5,(c:14,d:14):2.5);"
+	if got := root.Newick(); got != want {
+		t.Errorf("expected %q, got %q.", want, got)
+	}
+	if _, err := UPGMA(names[:3], infDist()); err == nil {
+		t.Errorf("expected error for infinite distance, got nil.")
+	}
+}
+
+func TestNeighborJoining(t *testing.T) {
+	// An additive distance matrix is reproduced exactly by the tree.
+	names := []string{"a", "b", "c", "d", "e"}
+	dist := [][]float64{
+		{0, 5, 9, 9, 8},
+		{5, 0, 10, 10, 9},
+		{9, 10, 0, 8, 7},
+		{9, 10, 8, 0, 3},
+		{8, 9, 7, 3, 0},
+	}
+	root, err := NeighborJoining(names, dist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(root.Leaves()); got != len(names) {
+		t.Fatalf("expected %d leaves, got %d.", len(names), got)
+	}
+	got := newMatrix(len(names))
+	for i, a := range names {
+		for j, b := range names {
+			if got[i][j], err = root.Distance(a, b, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	checkDist(t, got, dist)
+	if _, err := NeighborJoining(names, dist[:4]); err == nil {
+		t.Errorf("expected error for non-square distance matrix, got nil.")
+	}
+	if _, err := NeighborJoining(names[:3], infDist()); err == nil {
+		t.Errorf("expected error for infinite distance, got nil.")
+	}
+}
+
+// infDist returns a distance matrix of three taxa, one of which is infinitely
+// distant from the others; e.g. a Jukes-Cantor distance with p >= 0.75.
+func infDist() [][]float64 {
+	inf := math.Inf(1)
+	return [][]float64{
+		{0, 0.3, inf},
+		{0.3, 0, inf},
+		{inf, inf, 0},
+	}
+}
+
+// checkDist reports differences between the distance matrices got and want.
+func checkDist(t *testing.T, got, want [][]float64) {
+	t.Helper()
+	for i := range want {
+		for j := range want[i] {
+			if math.IsInf(want[i][j], 1) && math.IsInf(got[i][j], 1) {
+				continue
+			}
+			if math.Abs(got[i][j]-want[i][j]) > 1e-9 {
+				t.Errorf("i=%d, j=%d: expected %v, got %v.", i, j, want[i][j], got[i][j])
+			}
+		}
+	}
+}
@@ -3,38 +3,23 @@
 package rosa
 
 import (
-	"bytes"
 	"fmt"
 	"strings"
+
+	"github.com/mewmew/playground/rosalind/seq"
 )
 
 // Trans transcribes the provided DNA sequence into an RNA sequence where the
-// nucleotide uracil is used in place of thymine.
+// nucleotide uracil is used in place of thymine; see seq.DNA.Transcribe.
 func Trans(dna string) (rna string) {
-	return strings.Replace(dna, "T", "U", -1)
+	return string(seq.DNA(dna).Transcribe())
 }
 
-// RevComp returns the reverse complement of the provided DNA sequence. The
-// bases are complemented as follows:
-//    A: T
-//    C: G
-//    G: C
-//    T: A
+// RevComp returns the reverse complement of the provided DNA sequence. Case and
+// IUPAC ambiguity codes are preserved, and characters without a complement are
+// kept as is; see seq.DNA.RevComp.
 func RevComp(dna string) (revc string) {
-	buf := new(bytes.Buffer)
-	for i := len(dna) - 1; i >= 0; i-- {
-		switch dna[i] {
-		case 'A':
-			fmt.Fprint(buf, "T")
-		case 'C':
-			fmt.Fprint(buf, "G")
-		case 'G':
-			fmt.Fprint(buf, "C")
-		case 'T':
-			fmt.Fprint(buf, "A")
-		}
-	}
-	return buf.String()
+	return string(seq.DNA(dna).RevComp())
 }
 
 const (
@@ -42,55 +27,101 @@ const (
 	Stop byte = 0
 )
 
-// aminos is a map from codons to amino acids.
-var aminos = map[string]byte{
-	"GCA": 'A', "GCC": 'A', "GCG": 'A', "GCU": 'A',
-	"UGC": 'C', "UGU": 'C',
-	"GAC": 'D', "GAU": 'D',
-	"GAA": 'E', "GAG": 'E', "UUC": 'F',
-	"UUU": 'F',
-	"GGA": 'G', "GGC": 'G', "GGG": 'G', "GGU": 'G',
-	"CAC": 'H', "CAU": 'H',
-	"AUA": 'I', "AUC": 'I', "AUU": 'I',
-	"AAA": 'K', "AAG": 'K',
-	"CUA": 'L', "CUC": 'L', "CUG": 'L', "CUU": 'L', "UUA": 'L', "UUG": 'L',
-	"AUG": 'M',
-	"AAC": 'N', "AAU": 'N',
-	"CCA": 'P', "CCC": 'P', "CCG": 'P', "CCU": 'P',
-	"CAA": 'Q', "CAG": 'Q',
-	"AGA": 'R', "AGG": 'R', "CGA": 'R', "CGC": 'R', "CGG": 'R', "CGU": 'R',
-	"AGC": 'S', "AGU": 'S', "UCA": 'S', "UCC": 'S', "UCG": 'S', "UCU": 'S',
-	"ACA": 'T', "ACC": 'T', "ACG": 'T', "ACU": 'T',
-	"GUA": 'V', "GUC": 'V', "GUG": 'V', "GUU": 'V',
-	"UGG": 'W',
-	"UAC": 'Y', "UAU": 'Y',
-	"UAA": Stop, "UAG": Stop, "UGA": Stop,
-}
-
 // Prot translates the provided RNA sequence to a protein which consists of a
-// sequence of amino acids.
+// sequence of amino acids, using the standard genetic code. Use
+// seq.RNA.TranslateWith for alternative genetic codes.
 func Prot(rna string) (prot string, err error) {
 	if len(rna)%3 != 0 {
 		return "", fmt.Errorf("rosa.Prot: invalid RNA length; not divisible by 3")
 	}
+	r, err := seq.ParseRNA(rna)
+	if err != nil {
+		return "", fmt.Errorf("rosa.Prot: %v", err)
+	}
+	p, err := r.Translate()
+	if err != nil {
+		return "", fmt.Errorf("rosa.Prot: %v", err)
+	}
+	return p.String(), nil
+}
+
+// HamDist calculates the Hamming distance between a and b; the number of
+// positions at which the sequences differ.
+func HamDist(a, b string) (n int, err error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("rosa.HamDist: length mismatch; len(a)=%d, len(b)=%d", len(a), len(b))
+	}
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			n++
+		}
+	}
+	return n, nil
+}
 
-	buf := make([]byte, len(rna)/3)
-	for i := 0; i < len(buf); i++ {
-		j := i * 3
-		codon := rna[j : j+3]
-		amino, ok := aminos[codon]
-		if !ok {
-			return "", fmt.Errorf("rosa.Prot: invalid codon %q", codon)
+// SubSeq returns the first location of each character in sep as a subsequence
+// of s, or nil if no match was found.
+func SubSeq(s, sep string) (locs []int) {
+	var i int
+	for j := 0; j < len(sep); j++ {
+		base := sep[j]
+		pos := strings.IndexByte(s[i:], base)
+		if pos == -1 {
+			return nil
 		}
+		loc := i + pos
+		locs = append(locs, loc)
+		i += pos + 1
+	}
+	return locs
+}
 
-		// Break when a stop codon has been located.
-		if amino == Stop {
-			buf = buf[:i]
-			break
+// RevPal returns the location of length of every reverse palindrome in the
+// provided DNA sequence having a length between 4 and 12 nucleotides.
+func RevPal(dna string) (locs, ns []int) {
+	for loc := 0; loc < len(dna); loc++ {
+		// The length of a reverse palindrome is always divisible by 2.
+		for n := 4; n <= 12; n += 2 {
+			end := loc + n
+			if end > len(dna) {
+				break
+			}
+			if IsRevPal(dna[loc:end]) {
+				locs = append(locs, loc)
+				ns = append(ns, n)
+			}
 		}
+	}
+	return locs, ns
+}
 
-		buf[i] = amino
+var (
+	// baseComp is a map from each DNA base to its complement.
+	baseComp = map[byte]byte{
+		'A': 'T',
+		'C': 'G',
+		'G': 'C',
+		'T': 'A',
+	}
+)
+
+// IsRevPal returns true if the provided DNA sequence is a reverse palindrome,
+// and false otherwise. A DNA sequence is a reverse palindrome if it is equal to
+// its reverse complement.
+func IsRevPal(dna string) bool {
+	// The length of a reverse palindrome is always divisible by 2.
+	if len(dna)%2 != 0 {
+		return false
 	}
 
-	return string(buf), nil
+	// This algorithm starts from both ends of the DNA sequence and successively
+	// works towards the middle. It compares the start and end neucleotides to
+	// verify that they are each others complement; and if not exits early.
+	for i := 0; i < len(dna)/2; i++ {
+		j := len(dna) - i - 1
+		if baseComp[dna[i]] != dna[j] {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,43 @@
+package rosa
+
+import "testing"
+
+func TestTrans(t *testing.T) {
+	golden := []struct {
+		dna  string
+		want string
+	}{
+		// i=0
+		{dna: "GATGGAACTTGACTACGTAAATT", want: "GAUGGAACUUGACUACGUAAAUU"},
+		// i=1; case is preserved.
+		{dna: "gatTACa", want: "gauUACa"},
+		// i=2; ambiguity codes are preserved.
+		{dna: "TNRT", want: "UNRU"},
+	}
+	for i, g := range golden {
+		if got := Trans(g.dna); got != g.want {
+			t.Errorf("i=%d: expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
+
+func TestRevComp(t *testing.T) {
+	golden := []struct {
+		dna  string
+		want string
+	}{
+		// i=0
+		{dna: "AAAACCCGGT", want: "ACCGGGTTTT"},
+		// i=1; case is preserved.
+		{dna: "aaaCGt", want: "aCGttt"},
+		// i=2; ambiguity codes are complemented, not dropped.
+		{dna: "AAAGNTCCC", want: "GGGANCTTT"},
+		// i=3
+		{dna: "ARYB", want: "VRYT"},
+	}
+	for i, g := range golden {
+		if got := RevComp(g.dna); got != g.want {
+			t.Errorf("i=%d: expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package rosa
+
+import (
+	"sort"
+)
+
+// A SuffixArray is a suffix array of a text, together with its longest common
+// prefix (LCP) array. It supports exact matching of patterns in time
+// proportional to the length of the pattern times the logarithm of the length
+// of the text.
+type SuffixArray struct {
+	// Text of the suffix array.
+	text string
+	// SA holds the start offsets of the suffixes of the text, in lexicographic
+	// order.
+	SA []int
+	// LCP holds the length of the longest common prefix of each suffix with
+	// the previous suffix in lexicographic order; LCP[0] is 0.
+	LCP []int
+}
+
+// NewSuffixArray returns a new suffix array of the text.
+func NewSuffixArray(text string) *SuffixArray {
+	syms := make([]int, len(text))
+	for i := 0; i < len(text); i++ {
+		syms[i] = int(text[i])
+	}
+	sa := suffixArray(syms, 256)
+	return &SuffixArray{text: text, SA: sa, LCP: lcpArray(syms, sa)}
+}
+
+// Lookup returns the start offsets of all occurrences of the pattern in the
+// text, in increasing order.
+func (sa *SuffixArray) Lookup(pattern string) []int {
+	if len(pattern) == 0 {
+		return nil
+	}
+	// Locate the range of suffixes which start with the pattern.
+	lo := sort.Search(len(sa.SA), func(i int) bool {
+		return sa.suffix(i, len(pattern)) >= pattern
+	})
+	hi := lo + sort.Search(len(sa.SA)-lo, func(i int) bool {
+		return sa.suffix(lo+i, len(pattern)) > pattern
+	})
+	if lo == hi {
+		return nil
+	}
+	locs := append([]int(nil), sa.SA[lo:hi]...)
+	sort.Ints(locs)
+	return locs
+}
+
+// suffix returns the first n bytes of the ith suffix in lexicographic order.
+func (sa *SuffixArray) suffix(i, n int) string {
+	s := sa.text[sa.SA[i]:]
+	if len(s) > n {
+		s = s[:n]
+	}
+	return s
+}
+
+// LongestRepeat returns the longest substring which occurs at least twice in
+// the text; occurrences may overlap.
+func (sa *SuffixArray) LongestRepeat() string {
+	best, start := 0, 0
+	for i, lcp := range sa.LCP {
+		if lcp > best {
+			best, start = lcp, sa.SA[i]
+		}
+	}
+	return sa.text[start : start+best]
+}
+
+// LongestCommonSubstring returns a longest substring which is common to all of
+// the provided strings.
+func LongestCommonSubstring(strs []string) string {
+	switch len(strs) {
+	case 0:
+		return ""
+	case 1:
+		return strs[0]
+	}
+	// Concatenate the strings, terminating each with a unique separator symbol
+	// outside of the byte range, and record the string of each position.
+	var syms, owner []int
+	for i, s := range strs {
+		for j := 0; j < len(s); j++ {
+			syms = append(syms, int(s[j]))
+			owner = append(owner, i)
+		}
+		syms = append(syms, 256+i)
+		owner = append(owner, -1)
+	}
+	sa := suffixArray(syms, 256+len(strs))
+	lcp := lcpArray(syms, sa)
+
+	// Slide a window over the suffix array, extending it until it contains
+	// suffixes of all strings and shrinking it while it does. The longest
+	// common prefix of the suffixes in a window is the minimum LCP value of the
+	// window, excluding its first suffix; it is tracked using a monotone queue
+	// of LCP indices.
+	counts := make([]int, len(strs))
+	covered := 0
+	var queue []int
+	best, start := 0, 0
+	lo := 0
+	for hi := 0; hi < len(sa); hi++ {
+		if o := owner[sa[hi]]; o != -1 {
+			if counts[o] == 0 {
+				covered++
+			}
+			counts[o]++
+		}
+		if hi > lo {
+			for len(queue) > 0 && lcp[queue[len(queue)-1]] >= lcp[hi] {
+				queue = queue[:len(queue)-1]
+			}
+			queue = append(queue, hi)
+		}
+		for covered == len(strs) {
+			for len(queue) > 0 && queue[0] <= lo {
+				queue = queue[1:]
+			}
+			if len(queue) > 0 {
+				if min := lcp[queue[0]]; min > best {
+					best, start = min, sa[lo]
+				}
+			}
+			if o := owner[sa[lo]]; o != -1 {
+				counts[o]--
+				if counts[o] == 0 {
+					covered--
+				}
+			}
+			lo++
+		}
+	}
+	buf := make([]byte, best)
+	for i := range buf {
+		buf[i] = byte(syms[start+i])
+	}
+	return string(buf)
+}
+
+// suffixArray returns the suffix array of the symbols, which are in the range
+// [0, alphabet), using prefix doubling with radix sorting.
+func suffixArray(syms []int, alphabet int) []int {
+	n := len(syms)
+	if n == 0 {
+		return nil
+	}
+	sa := make([]int, n)
+	rank := make([]int, n)
+	tmp := make([]int, n)
+	for i := range sa {
+		sa[i] = i
+		rank[i] = syms[i]
+	}
+	size := alphabet
+	if n > size {
+		size = n
+	}
+	// Counting sort buckets; the secondary key of suffixes without a second
+	// half is 0, and keys of other suffixes are offset by 1.
+	count := make([]int, size+1)
+	for k := 1; ; k <<= 1 {
+		second := func(i int) int {
+			if i+k < n {
+				return rank[i+k] + 1
+			}
+			return 0
+		}
+		// Sort by the secondary key, then stable sort by the primary key.
+		countingSort(tmp, sa, count, second)
+		countingSort(sa, tmp, count[:size], func(i int) int { return rank[i] })
+		// Rank the suffixes by their first 2k symbols.
+		tmp[sa[0]] = 0
+		for i := 1; i < n; i++ {
+			prev, cur := sa[i-1], sa[i]
+			r := tmp[prev]
+			if rank[prev] != rank[cur] || second(prev) != second(cur) {
+				r++
+			}
+			tmp[cur] = r
+		}
+		rank, tmp = tmp, rank
+		if rank[sa[n-1]] == n-1 {
+			return sa
+		}
+	}
+}
+
+// countingSort stably sorts the suffixes of src by key into dst, using the
+// provided bucket counts, which must span the range of keys.
+func countingSort(dst, src, count []int, key func(i int) int) {
+	for i := range count {
+		count[i] = 0
+	}
+	for _, i := range src {
+		count[key(i)]++
+	}
+	sum := 0
+	for i, c := range count {
+		count[i] = sum
+		sum += c
+	}
+	for _, i := range src {
+		k := key(i)
+		dst[count[k]] = i
+		count[k]++
+	}
+}
+
+// lcpArray returns the LCP array of the symbols and their suffix array, using
+// the algorithm of Kasai et al.
+func lcpArray(syms, sa []int) []int {
+	n := len(syms)
+	rank := make([]int, n)
+	for i, s := range sa {
+		rank[s] = i
+	}
+	lcp := make([]int, n)
+	h := 0
+	for i := 0; i < n; i++ {
+		if rank[i] == 0 {
+			h = 0
+			continue
+		}
+		j := sa[rank[i]-1]
+		for i+h < n && j+h < n && syms[i+h] == syms[j+h] {
+			h++
+		}
+		lcp[rank[i]] = h
+		if h > 0 {
+			h--
+		}
+	}
+	return lcp
+}
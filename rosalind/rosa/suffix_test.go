@@ -0,0 +1,114 @@
+package rosa
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSuffixArray(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		text := randDNA(r, r.Intn(200), "ACGT"[:1+r.Intn(4)])
+		sa := NewSuffixArray(text)
+		// Compare against sorted suffixes.
+		want := make([]int, len(text))
+		for j := range want {
+			want[j] = j
+		}
+		sort.Slice(want, func(a, b int) bool { return text[want[a]:] < text[want[b]:] })
+		if !reflect.DeepEqual(sa.SA, want) && len(text) > 0 {
+			t.Errorf("i=%d: %q: expected suffix array %v, got %v.", i, text, want, sa.SA)
+			continue
+		}
+		for j := 1; j < len(want); j++ {
+			a, b := text[want[j-1]:], text[want[j]:]
+			lcp := 0
+			for lcp < len(a) && lcp < len(b) && a[lcp] == b[lcp] {
+				lcp++
+			}
+			if sa.LCP[j] != lcp {
+				t.Errorf("i=%d: %q: expected LCP %d at %d, got %d.", i, text, lcp, j, sa.LCP[j])
+			}
+		}
+		// Lookup matches a naive scan.
+		if len(text) == 0 {
+			continue
+		}
+		start := r.Intn(len(text))
+		pattern := text[start : start+1+r.Intn(len(text)-start)]
+		var locs []int
+		for j := 0; j+len(pattern) <= len(text); j++ {
+			if strings.HasPrefix(text[j:], pattern) {
+				locs = append(locs, j)
+			}
+		}
+		if got := sa.Lookup(pattern); !reflect.DeepEqual(got, locs) {
+			t.Errorf("i=%d: %q: expected locations %v of %q, got %v.", i, text, locs, pattern, got)
+		}
+	}
+}
+
+func TestLongestCommonSubstring(t *testing.T) {
+	golden := []struct {
+		strs []string
+		n    int
+	}{
+		// i=0
+		// Rosalind LCSM; any of AC, CA and TA.
+		{strs: []string{"GATTACA", "TAGACCA", "ATACA"}, n: 2},
+		// i=1
+		{strs: []string{"ACGTACGT", "TTACGTTT", "GGACGTGG"}, n: 4},
+		// i=2
+		{strs: []string{"AAAA", "CCCC"}, n: 0},
+		// i=3
+		{strs: []string{"GATTACA"}, n: 7},
+	}
+	for i, g := range golden {
+		got := LongestCommonSubstring(g.strs)
+		if len(got) != g.n {
+			t.Errorf("i=%d: expected common substring of length %d, got %q.", i, g.n, got)
+		}
+		for _, s := range g.strs {
+			if !strings.Contains(s, got) {
+				t.Errorf("i=%d: %q is not a substring of %q.", i, got, s)
+			}
+		}
+	}
+}
+
+func TestLongestRepeat(t *testing.T) {
+	sa := NewSuffixArray("ATATCGATATA")
+	if got, want := sa.LongestRepeat(), "ATAT"; got != want {
+		t.Errorf("expected longest repeat %q, got %q.", want, got)
+	}
+}
+
+func TestSuffixArrayEmpty(t *testing.T) {
+	sa := NewSuffixArray("")
+	if got := sa.Lookup("A"); len(got) != 0 {
+		t.Errorf("expected no occurrences, got %v.", got)
+	}
+	if got := sa.LongestRepeat(); got != "" {
+		t.Errorf("expected empty longest repeat, got %q.", got)
+	}
+}
+
+// randDNA returns a random sequence of length n over the alphabet.
+func randDNA(r *rand.Rand, n int, alphabet string) string {
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return string(buf)
+}
+
+func BenchmarkNewSuffixArray(b *testing.B) {
+	text := randDNA(rand.New(rand.NewSource(1)), 1000000, "ACGT")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewSuffixArray(text)
+	}
+}
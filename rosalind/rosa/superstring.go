@@ -0,0 +1,81 @@
+package rosa
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Superstring returns the shortest superstring of the reads, which is assembled
+// by gluing together pairs of reads that overlap by more than half their
+// length.
+//
+// The successor of each read is located using an index of the k-mers of the
+// reads, where k is one more than half the length of the shortest read; a read
+// b overlaps the end of read a by more than half its length only if the first
+// k-mer of b occurs in the second half of a.
+func Superstring(reads []string) (string, error) {
+	if len(reads) == 0 {
+		return "", errors.New("rosa.Superstring: no reads provided")
+	}
+	k := len(reads[0])
+	for _, read := range reads {
+		if len(read) < k {
+			k = len(read)
+		}
+	}
+	k = k/2 + 1
+	idx, err := NewKmerIndex(reads, k, false)
+	if err != nil {
+		return "", err
+	}
+
+	// Locate the successor of each read, and the overlap between them.
+	next := make([]int, len(reads))
+	overlap := make([]int, len(reads))
+	hasPrev := make([]bool, len(reads))
+	for a, read := range reads {
+		next[a] = -1
+		for i := 1; i+k <= len(read) && len(read)-i > len(read)/2; i++ {
+			for _, pos := range idx.Positions(read[i : i+k]) {
+				b := pos.Seq
+				n := len(read) - i
+				if pos.Pos != 0 || b == a || n <= len(reads[b])/2 || len(reads[b]) < n || reads[b][:n] != read[i:] {
+					continue
+				}
+				next[a], overlap[a] = b, n
+				hasPrev[b] = true
+				break
+			}
+			if next[a] != -1 {
+				break
+			}
+		}
+	}
+
+	// Glue the reads together, starting with the read which has no predecessor.
+	start := -1
+	for i, ok := range hasPrev {
+		if !ok {
+			if start != -1 {
+				return "", errors.New("rosa.Superstring: unable to assemble reads; multiple reads without predecessor")
+			}
+			start = i
+		}
+	}
+	if start == -1 {
+		return "", errors.New("rosa.Superstring: unable to assemble reads; reads form a cycle")
+	}
+	s := reads[start]
+	n := 1
+	for i := start; next[i] != -1; i = next[i] {
+		s += reads[next[i]][overlap[i]:]
+		n++
+		if n > len(reads) {
+			return "", errors.New("rosa.Superstring: unable to assemble reads; reads form a cycle")
+		}
+	}
+	if n != len(reads) {
+		return "", fmt.Errorf("rosa.Superstring: unable to assemble reads; %d of %d reads glued", n, len(reads))
+	}
+	return s, nil
+}
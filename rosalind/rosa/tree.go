@@ -0,0 +1,285 @@
+package rosa
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Node is a node of a phylogenetic tree.
+type Node struct {
+	// Name of the node; may be empty.
+	Name string
+	// Length of the branch to the parent node, if HasLength is set.
+	Length float64
+	// HasLength specifies whether the length of the branch is known.
+	HasLength bool
+	// Child nodes.
+	Children []*Node
+	// Parent node, or nil for the root.
+	Parent *Node
+}
+
+// AddChild adds the child node to n.
+func (n *Node) AddChild(child *Node) {
+	child.Parent = n
+	n.Children = append(n.Children, child)
+}
+
+// ParseNewick parses the provided tree in Newick format, where each node may
+// have a name and a branch length; e.g. "((dog:4,cat:3):74,robot:98);".
+func ParseNewick(s string) (*Node, error) {
+	p := &newickParser{s: s}
+	root, err := p.subtree()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos >= len(p.s) || p.s[p.pos] != ';' {
+		return nil, p.errorf("expected ';'")
+	}
+	p.pos++
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected data after ';'")
+	}
+	return root, nil
+}
+
+// A newickParser is a recursive descent parser of Newick trees.
+type newickParser struct {
+	// Tree in Newick format.
+	s string
+	// Current offset into s.
+	pos int
+}
+
+// subtree parses a subtree; a leaf or an internal node followed by its name
+// and branch length.
+func (p *newickParser) subtree() (*Node, error) {
+	n := new(Node)
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == '(' {
+		p.pos++
+		for {
+			child, err := p.subtree()
+			if err != nil {
+				return nil, err
+			}
+			n.AddChild(child)
+			p.skipSpace()
+			if p.pos >= len(p.s) {
+				return nil, p.errorf("expected ',' or ')'")
+			}
+			c := p.s[p.pos]
+			p.pos++
+			if c == ')' {
+				break
+			}
+			if c != ',' {
+				return nil, p.errorf("expected ',' or ')', got %q", c)
+			}
+		}
+	}
+	n.Name = p.name()
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == ':' {
+		p.pos++
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.s) && !strings.ContainsRune("(),:; \t\r\n", rune(p.s[p.pos])) {
+			p.pos++
+		}
+		length, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+		if err != nil {
+			return nil, p.errorf("invalid branch length %q", p.s[start:p.pos])
+		}
+		n.Length, n.HasLength = length, true
+	}
+	return n, nil
+}
+
+// name parses the name of a node; underscores in unquoted names represent
+// spaces, as is the convention of the Newick format, but are kept as is.
+func (p *newickParser) name() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune("(),:;", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	return strings.TrimSpace(p.s[start:p.pos])
+}
+
+// skipSpace skips whitespace.
+func (p *newickParser) skipSpace() {
+	for p.pos < len(p.s) && strings.ContainsRune(" \t\r\n", rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+// errorf returns an error at the current offset.
+func (p *newickParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("rosa.ParseNewick: offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// Newick returns the tree rooted at n in Newick format.
+func (n *Node) Newick() string {
+	buf := new(bytes.Buffer)
+	n.writeNewick(buf)
+	buf.WriteByte(';')
+	return buf.String()
+}
+
+// writeNewick writes the subtree rooted at n in Newick format to buf.
+func (n *Node) writeNewick(buf *bytes.Buffer) {
+	if len(n.Children) > 0 {
+		buf.WriteByte('(')
+		for i, child := range n.Children {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			child.writeNewick(buf)
+		}
+		buf.WriteByte(')')
+	}
+	buf.WriteString(n.Name)
+	if n.HasLength {
+		buf.WriteByte(':')
+		buf.WriteString(strconv.FormatFloat(n.Length, 'f', -1, 64))
+	}
+}
+
+// Find returns the first node with the provided name in the tree rooted at n,
+// in depth-first order, or nil if not present.
+func (n *Node) Find(name string) *Node {
+	if n.Name == name {
+		return n
+	}
+	for _, child := range n.Children {
+		if found := child.Find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Leaves returns the leaves of the tree rooted at n, from left to right.
+func (n *Node) Leaves() []*Node {
+	if len(n.Children) == 0 {
+		return []*Node{n}
+	}
+	var leaves []*Node
+	for _, child := range n.Children {
+		leaves = append(leaves, child.Leaves()...)
+	}
+	return leaves
+}
+
+// Distance returns the distance between the nodes with the provided names in
+// the tree rooted at n; the sum of the branch lengths on the path between them
+// if weighted is set, and the number of branches otherwise.
+func (n *Node) Distance(a, b string, weighted bool) (float64, error) {
+	x, y := n.Find(a), n.Find(b)
+	if x == nil {
+		return 0, fmt.Errorf("rosa.Node.Distance: unable to locate node %q", a)
+	}
+	if y == nil {
+		return 0, fmt.Errorf("rosa.Node.Distance: unable to locate node %q", b)
+	}
+	// Record the distance from x to each of its ancestors, then walk up from y
+	// to the first common ancestor.
+	dist := make(map[*Node]float64)
+	d := 0.0
+	for node := x; node != nil; node = node.Parent {
+		dist[node] = d
+		d += node.branch(weighted)
+	}
+	d = 0
+	for node := y; node != nil; node = node.Parent {
+		if dx, ok := dist[node]; ok {
+			return dx + d, nil
+		}
+		d += node.branch(weighted)
+	}
+	return 0, fmt.Errorf("rosa.Node.Distance: nodes %q and %q are not in the same tree", a, b)
+}
+
+// branch returns the length of the branch to the parent node if weighted is
+// set, and 1 otherwise.
+func (n *Node) branch(weighted bool) float64 {
+	if weighted {
+		return n.Length
+	}
+	return 1
+}
+
+// ASCII returns a plain-text rendering of the tree rooted at n, with one node
+// per line; unnamed internal nodes are shown as '+'.
+//
+//    +
+//    |-- +:74
+//    |   |-- dog:4
+//    |   `-- cat:3
+//    `-- robot:98
+func (n *Node) ASCII() string {
+	buf := new(bytes.Buffer)
+	n.writeASCII(buf, "", "")
+	return buf.String()
+}
+
+// writeASCII writes the subtree rooted at n to buf, where the line of n starts
+// with the first prefix and the lines of its descendants with the second.
+func (n *Node) writeASCII(buf *bytes.Buffer, first, rest string) {
+	buf.WriteString(first)
+	label := n.Name
+	if len(label) == 0 {
+		label = "+"
+	}
+	buf.WriteString(label)
+	if n.HasLength {
+		buf.WriteByte(':')
+		buf.WriteString(strconv.FormatFloat(n.Length, 'f', -1, 64))
+	}
+	buf.WriteByte('\n')
+	for i, child := range n.Children {
+		if i == len(n.Children)-1 {
+			child.writeASCII(buf, rest+"`-- ", rest+"    ")
+		} else {
+			child.writeASCII(buf, rest+"|-- ", rest+"|   ")
+		}
+	}
+}
+
+// TreeEdges returns the minimum number of edges which must be added to the
+// acyclic graph of n nodes, numbered from 1 to n, to produce a tree; one less
+// than the number of connected components.
+func TreeEdges(n int, edges [][2]int) (int, error) {
+	// Union-find of the connected components.
+	parent := make([]int, n+1)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(x int) int
+	find = func(x int) int {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	components := n
+	for _, edge := range edges {
+		for _, x := range edge {
+			if x < 1 || x > n {
+				return 0, fmt.Errorf("rosa.TreeEdges: invalid node %d; expected 1 to %d", x, n)
+			}
+		}
+		a, b := find(edge[0]), find(edge[1])
+		if a == b {
+			return 0, fmt.Errorf("rosa.TreeEdges: edge %d-%d forms a cycle", edge[0], edge[1])
+		}
+		parent[a] = b
+		components--
+	}
+	return components - 1, nil
+}
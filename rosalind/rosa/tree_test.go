@@ -0,0 +1,86 @@
+package rosa
+
+import "testing"
+
+func TestParseNewick(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+	}{
+		// i=0
+		{in: "(cat)dog;", want: "(cat)dog;"},
+		// i=1
+		{in: "(dog,cat);", want: "(dog,cat);"},
+		// i=2
+		{in: "((dog:4,cat:3):74,robot:98,elephant:58);", want: "((dog:4,cat:3):74,robot:98,elephant:58);"},
+		// i=3
+		{in: " ( a : 0.5 , ( b,c ) d : 1e-2 ) root ;\n", want: "(a:0.5,(b,c)d:0.01)root;"},
+		// i=4
+		{in: "(,,(,));", want: "(,,(,));"},
+	}
+	for i, g := range golden {
+		root, err := ParseNewick(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got := root.Newick(); got != g.want {
+			t.Errorf("i=%d: expected %q, got %q.", i, g.want, got)
+		}
+	}
+	for i, in := range []string{"(a,b)", "(a,b;", "(a:x);", "(a);b"} {
+		if _, err := ParseNewick(in); err == nil {
+			t.Errorf("i=%d: expected error for %q, got nil.", i, in)
+		}
+	}
+}
+
+func TestNodeDistance(t *testing.T) {
+	golden := []struct {
+		tree     string
+		a, b     string
+		weighted bool
+		want     float64
+	}{
+		// i=0
+		{tree: "(cat)dog;", a: "dog", b: "cat", want: 1},
+		// i=1
+		{tree: "(dog,cat);", a: "dog", b: "cat", want: 2},
+		// i=2
+		{tree: "(dog:42,cat:33);", a: "cat", b: "dog", weighted: true, want: 75},
+		// i=3
+		{tree: "((dog:4,cat:3):74,robot:98,elephant:58);", a: "dog", b: "elephant", weighted: true, want: 136},
+		// i=4
+		{tree: "((dog:4,cat:3):74,robot:98,elephant:58);", a: "dog", b: "dog", weighted: true, want: 0},
+	}
+	for i, g := range golden {
+		root, err := ParseNewick(g.tree)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		got, err := root.Distance(g.a, g.b, g.weighted)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
+
+func TestNodeASCII(t *testing.T) {
+	root, err := ParseNewick("((dog:4,cat:3):74,robot:98);")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "+\n" +
+		"|-- +:74\n" +
+		"|   |-- dog:4\n" +
+		"|   `-- cat:3\n" +
+		"`-- robot:98\n"
+	if got := root.ASCII(); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
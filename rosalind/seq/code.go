@@ -0,0 +1,227 @@
+package seq
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A GeneticCode is an NCBI translation table, which maps codons to amino acids
+// and specifies the start codons of an organism.
+type GeneticCode struct {
+	// NCBI translation table ID.
+	ID int
+	// Name of the translation table.
+	Name string
+	// Amino acid of each codon, indexed by 16*b1 + 4*b2 + b3 where the bases
+	// T, C, A and G are numbered 0 to 3; '*' denotes a stop codon.
+	aminos string
+	// Start codons, marked by 'M' using the same indexing as aminos.
+	starts string
+}
+
+// codes holds the NCBI translation tables, indexed by ID.
+var codes = map[int]*GeneticCode{}
+
+func init() {
+	for _, code := range []*GeneticCode{
+		{ID: 1, Name: "Standard",
+			aminos: "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "---M---------------M---------------M----------------------------"},
+		{ID: 2, Name: "Vertebrate Mitochondrial",
+			aminos: "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSS**VVVVAAAADDEEGGGG",
+			starts: "--------------------------------MMMM---------------M------------"},
+		{ID: 3, Name: "Yeast Mitochondrial",
+			aminos: "FFLLSSSSYY**CCWWTTTTPPPPHHQQRRRRIIMMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "----------------------------------MM---------------M------------"},
+		{ID: 4, Name: "Mold, Protozoan, and Coelenterate Mitochondrial and Mycoplasma/Spiroplasma",
+			aminos: "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "--MM---------------M------------MMMM---------------M------------"},
+		{ID: 5, Name: "Invertebrate Mitochondrial",
+			aminos: "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSSSSVVVVAAAADDEEGGGG",
+			starts: "---M----------------------------MMMM---------------M------------"},
+		{ID: 6, Name: "Ciliate, Dasycladacean and Hexamita Nuclear",
+			aminos: "FFLLSSSSYYQQCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "-----------------------------------M----------------------------"},
+		{ID: 9, Name: "Echinoderm and Flatworm Mitochondrial",
+			aminos: "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNNKSSSSVVVVAAAADDEEGGGG",
+			starts: "-----------------------------------M---------------M------------"},
+		{ID: 10, Name: "Euplotid Nuclear",
+			aminos: "FFLLSSSSYY**CCCWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "-----------------------------------M----------------------------"},
+		{ID: 11, Name: "Bacterial, Archaeal and Plant Plastid",
+			aminos: "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "---M---------------M------------MMMM---------------M------------"},
+		{ID: 12, Name: "Alternative Yeast Nuclear",
+			aminos: "FFLLSSSSYY**CC*WLLLSPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "-------------------M---------------M----------------------------"},
+		{ID: 13, Name: "Ascidian Mitochondrial",
+			aminos: "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSSGGVVVVAAAADDEEGGGG",
+			starts: "---M------------------------------MM---------------M------------"},
+		{ID: 14, Name: "Alternative Flatworm Mitochondrial",
+			aminos: "FFLLSSSSYYY*CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNNKSSSSVVVVAAAADDEEGGGG",
+			starts: "-----------------------------------M----------------------------"},
+		{ID: 16, Name: "Chlorophycean Mitochondrial",
+			aminos: "FFLLSSSSYY*LCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "-----------------------------------M----------------------------"},
+		{ID: 21, Name: "Trematode Mitochondrial",
+			aminos: "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNNKSSSSVVVVAAAADDEEGGGG",
+			starts: "-----------------------------------M---------------M------------"},
+		{ID: 22, Name: "Scenedesmus obliquus Mitochondrial",
+			aminos: "FFLLSS*SYY*LCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "-----------------------------------M----------------------------"},
+		{ID: 23, Name: "Thraustochytrium Mitochondrial",
+			aminos: "FF*LSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "--------------------------------M--M---------------M------------"},
+		{ID: 24, Name: "Rhabdopleuridae Mitochondrial",
+			aminos: "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSSKVVVVAAAADDEEGGGG",
+			starts: "---M---------------M---------------M---------------M------------"},
+		{ID: 25, Name: "Candidate Division SR1 and Gracilibacteria",
+			aminos: "FFLLSSSSYY**CCGWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "---M-------------------------------M---------------M------------"},
+		{ID: 26, Name: "Pachysolen tannophilus Nuclear",
+			aminos: "FFLLSSSSYY**CC*WLLLAPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "-------------------M---------------M----------------------------"},
+		{ID: 29, Name: "Mesodinium Nuclear",
+			aminos: "FFLLSSSSYYYYCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "-----------------------------------M----------------------------"},
+		{ID: 30, Name: "Peritrich Nuclear",
+			aminos: "FFLLSSSSYYEECC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+			starts: "-----------------------------------M----------------------------"},
+		{ID: 33, Name: "Cephalodiscidae Mitochondrial",
+			aminos: "FFLLSSSSYYY*CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSSKVVVVAAAADDEEGGGG",
+			starts: "---M---------------M---------------M---------------M------------"},
+	} {
+		codes[code.ID] = code
+	}
+	standardCode = codes[1]
+}
+
+// standardCode is the standard genetic code (NCBI translation table 1).
+var standardCode *GeneticCode
+
+// StandardCode returns the standard genetic code (NCBI translation table 1).
+func StandardCode() *GeneticCode {
+	return standardCode
+}
+
+// Code returns the NCBI translation table with the provided ID.
+func Code(id int) (*GeneticCode, error) {
+	code, ok := codes[id]
+	if !ok {
+		return nil, fmt.Errorf("seq: unknown translation table ID %d", id)
+	}
+	return code, nil
+}
+
+// Codes returns the NCBI translation tables, sorted by ID.
+func Codes() []*GeneticCode {
+	var cs []*GeneticCode
+	for _, code := range codes {
+		cs = append(cs, code)
+	}
+	sort.Slice(cs, func(i, j int) bool { return cs[i].ID < cs[j].ID })
+	return cs
+}
+
+// baseIndex maps from unambiguous nucleotides of either case to their index in
+// codon tables, or -1.
+var baseIndex [256]int8
+
+// baseSets maps from nucleotide codes of either case, including ambiguity
+// codes, to the indices of the bases they represent.
+var baseSets [256][]int
+
+func init() {
+	for i := range baseIndex {
+		baseIndex[i] = -1
+	}
+	for i, c := range []byte("TCAG") {
+		baseIndex[c] = int8(i)
+		baseIndex[c+'a'-'A'] = int8(i)
+	}
+	baseIndex['U'] = 0
+	baseIndex['u'] = 0
+	sets := map[byte]string{
+		'T': "T", 'U': "T", 'C': "C", 'A': "A", 'G': "G",
+		'R': "AG", 'Y': "CT", 'S': "CG", 'W': "AT", 'K': "GT", 'M': "AC",
+		'B': "CGT", 'D': "AGT", 'H': "ACT", 'V': "ACG", 'N': "ACGT",
+	}
+	for c, bases := range sets {
+		var set []int
+		for i := 0; i < len(bases); i++ {
+			set = append(set, int(baseIndex[bases[i]]))
+		}
+		baseSets[c] = set
+		baseSets[c+'a'-'A'] = set
+	}
+}
+
+// index returns the index of the unambiguous codon in codon tables. The
+// boolean return value is false if the codon contains ambiguity codes or
+// invalid characters.
+func index(codon []byte) (i int, ok bool) {
+	for _, c := range codon {
+		b := baseIndex[c]
+		if b < 0 {
+			return 0, false
+		}
+		i = 4*i + int(b)
+	}
+	return i, true
+}
+
+// translate returns the amino acid of the unambiguous codon. The boolean
+// return value is false if the codon contains ambiguity codes or invalid
+// characters.
+func (code *GeneticCode) translate(codon []byte) (amino byte, ok bool) {
+	i, ok := index(codon)
+	if !ok {
+		return 0, false
+	}
+	return code.aminos[i], true
+}
+
+// Translate returns the amino acid of the codon, where '*' denotes a stop
+// codon. Codons containing ambiguity codes translate to the amino acid shared
+// by all codons they represent (e.g. "CTN" to 'L'), or to 'X' if the amino acid
+// is ambiguous. The boolean return value is false if the codon contains invalid
+// characters.
+func (code *GeneticCode) Translate(codon []byte) (amino byte, ok bool) {
+	if len(codon) != 3 {
+		return 0, false
+	}
+	if amino, ok := code.translate(codon); ok {
+		return amino, true
+	}
+	s1, s2, s3 := baseSets[codon[0]], baseSets[codon[1]], baseSets[codon[2]]
+	if s1 == nil || s2 == nil || s3 == nil {
+		return 0, false
+	}
+	amino = 0
+	for _, b1 := range s1 {
+		for _, b2 := range s2 {
+			for _, b3 := range s3 {
+				a := code.aminos[16*b1+4*b2+b3]
+				if amino != 0 && a != amino {
+					return 'X', true
+				}
+				amino = a
+			}
+		}
+	}
+	return amino, true
+}
+
+// IsStart reports whether the unambiguous codon is a start codon of the
+// genetic code.
+func (code *GeneticCode) IsStart(codon []byte) bool {
+	i, ok := index(codon)
+	return ok && len(codon) == 3 && code.starts[i] == 'M'
+}
+
+// IsStop reports whether the unambiguous codon is a stop codon of the genetic
+// code.
+func (code *GeneticCode) IsStop(codon []byte) bool {
+	amino, ok := code.translate(codon)
+	return ok && len(codon) == 3 && amino == '*'
+}
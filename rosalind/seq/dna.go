@@ -0,0 +1,92 @@
+package seq
+
+// DNA is a DNA sequence.
+type DNA []byte
+
+// ParseDNA parses the provided DNA sequence, which may contain IUPAC ambiguity
+// codes.
+func ParseDNA(s string) (DNA, error) {
+	if err := dnaAlphabet.validate("DNA", s); err != nil {
+		return nil, err
+	}
+	return DNA(s), nil
+}
+
+func (dna DNA) String() string {
+	return string(dna)
+}
+
+// Complement returns the complement of the DNA sequence.
+func (dna DNA) Complement() DNA {
+	return complement(dna, &dnaComplements, false)
+}
+
+// RevComp returns the reverse complement of the DNA sequence.
+func (dna DNA) RevComp() DNA {
+	return complement(dna, &dnaComplements, true)
+}
+
+// Transcribe transcribes the DNA sequence into an RNA sequence, where uracil is
+// used in place of thymine.
+func (dna DNA) Transcribe() RNA {
+	rna := make(RNA, len(dna))
+	for i, c := range dna {
+		switch c {
+		case 'T':
+			c = 'U'
+		case 't':
+			c = 'u'
+		}
+		rna[i] = c
+	}
+	return rna
+}
+
+// TranslateWith translates the DNA sequence into a protein using the provided
+// options, as if it had first been transcribed. An incomplete trailing codon is
+// ignored.
+func (dna DNA) TranslateWith(opts TranslateOptions) (Protein, error) {
+	return translate(dna, opts)
+}
+
+// Frames translates the six reading frames of the DNA sequence using the
+// provided options; see RNA.Frames.
+func (dna DNA) Frames(opts TranslateOptions) ([6]Protein, error) {
+	return frames(dna, dna.RevComp(), opts)
+}
+
+// Count returns the number of occurrences of each of the nucleotides A, C, G
+// and T in the DNA sequence, regardless of case. Ambiguity codes are not
+// counted.
+func (dna DNA) Count() (a, c, g, t int) {
+	for _, base := range dna {
+		switch upper(base) {
+		case 'A':
+			a++
+		case 'C':
+			c++
+		case 'G':
+			g++
+		case 'T':
+			t++
+		}
+	}
+	return a, c, g, t
+}
+
+// GC returns the fraction of the bases of the DNA sequence which are guanine
+// or cytosine, including the ambiguity code S (G or C). It returns 0 for empty
+// sequences.
+func (dna DNA) GC() float64 {
+	if len(dna) == 0 {
+		return 0
+	}
+	gc := 0
+	for _, base := range dna {
+		switch upper(base) {
+		case 'C', 'G', 'S':
+			gc++
+		}
+	}
+	return float64(gc) / float64(len(dna))
+}
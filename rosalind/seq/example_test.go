@@ -0,0 +1,97 @@
+package seq
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleParseDNA() {
+	_, err := ParseDNA("ACGTNXACGT")
+	fmt.Println(err)
+	// Output: seq: invalid DNA character 'X' at position 6
+}
+
+func ExampleDNA_RevComp() {
+	dna, err := ParseDNA("AAAACCCGGTnRy")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(dna.RevComp())
+	// Output: rYnACCGGGTTTT
+}
+
+func ExampleDNA_Transcribe() {
+	dna, err := ParseDNA("GATGGAACTTGACTACGTAAATt")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(dna.Transcribe())
+	// Output: GAUGGAACUUGACUACGUAAAUu
+}
+
+func ExampleDNA_GC() {
+	dna, err := ParseDNA("CCATCGGTAGCGCATCCTTAGTCCAATTAAGTCCCTATCCAGGCGCTCCGCCGAAGGTCTATATCCATTTGTCAGCAGACACGC")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("%.6f\n", 100*dna.GC())
+	// Output: 53.571429
+}
+
+func ExampleRNA_Translate() {
+	rna, err := ParseRNA("AUGGCCAUGGCGCCCAGAACUGAGAUCAAUAGUACCCGUAUUAACGGGUGA")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	prot, err := rna.Translate()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(prot)
+	// Output: MAMAPRTEINSTRING
+}
+
+func ExampleCode() {
+	code, err := Code(2)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	rna, err := ParseRNA("AUAUGAAGAUAG")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	prot, err := rna.TranslateWith(TranslateOptions{Code: code, ThroughStops: true})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(code.Name)
+	fmt.Println(prot)
+	// Output:
+	// Vertebrate Mitochondrial
+	// MW**
+}
+
+func ExampleDNA_Frames() {
+	dna, err := ParseDNA("ATGGCCTAAGGCATN")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	prots, err := dna.Frames(TranslateOptions{ThroughStops: true, Ambiguous: true})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for i, prot := range prots {
+		frame := i%3 + 1
+		if i >= 3 {
+			frame = -frame
+		}
+		fmt.Printf("%+d %s\n", frame, prot)
+	}
+	// Output:
+	// +1 MA*GX
+	// +2 WPKA
+	// +3 GLRH
+	// -1 XALGH
+	// -2 MP*A
+	// -3 CLRP
+}
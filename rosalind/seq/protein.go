@@ -0,0 +1,18 @@
+package seq
+
+// Protein is a protein sequence of amino acids, where '*' denotes a stop.
+type Protein []byte
+
+// ParseProtein parses the provided protein sequence, which may contain the
+// ambiguity codes B (D or N), Z (E or Q), J (I or L) and X (any), and the
+// amino acids U (selenocysteine) and O (pyrrolysine).
+func ParseProtein(s string) (Protein, error) {
+	if err := proteinAlphabet.validate("protein", s); err != nil {
+		return nil, err
+	}
+	return Protein(s), nil
+}
+
+func (prot Protein) String() string {
+	return string(prot)
+}
@@ -0,0 +1,69 @@
+package seq
+
+import "fmt"
+
+// RNA is an RNA sequence.
+type RNA []byte
+
+// ParseRNA parses the provided RNA sequence, which may contain IUPAC ambiguity
+// codes.
+func ParseRNA(s string) (RNA, error) {
+	if err := rnaAlphabet.validate("RNA", s); err != nil {
+		return nil, err
+	}
+	return RNA(s), nil
+}
+
+func (rna RNA) String() string {
+	return string(rna)
+}
+
+// Complement returns the complement of the RNA sequence.
+func (rna RNA) Complement() RNA {
+	return complement(rna, &rnaComplements, false)
+}
+
+// RevComp returns the reverse complement of the RNA sequence.
+func (rna RNA) RevComp() RNA {
+	return complement(rna, &rnaComplements, true)
+}
+
+// BackTranscribe returns the DNA sequence of the RNA sequence, where thymine is
+// used in place of uracil.
+func (rna RNA) BackTranscribe() DNA {
+	dna := make(DNA, len(rna))
+	for i, c := range rna {
+		switch c {
+		case 'U':
+			c = 'T'
+		case 'u':
+			c = 't'
+		}
+		dna[i] = c
+	}
+	return dna
+}
+
+// Translate translates the RNA sequence into a protein using the standard
+// genetic code, stopping at the first stop codon. The length of the sequence
+// must be divisible by 3, and codons may not contain ambiguity codes.
+func (rna RNA) Translate() (Protein, error) {
+	if len(rna)%3 != 0 {
+		return nil, fmt.Errorf("seq: invalid RNA length %d; not divisible by 3", len(rna))
+	}
+	return translate(rna, TranslateOptions{})
+}
+
+// TranslateWith translates the RNA sequence into a protein using the provided
+// options. An incomplete trailing codon is ignored.
+func (rna RNA) TranslateWith(opts TranslateOptions) (Protein, error) {
+	return translate(rna, opts)
+}
+
+// Frames translates the six reading frames of the RNA sequence using the
+// provided options; frames +1, +2 and +3 start at the first, second and third
+// base of the sequence, and frames -1, -2 and -3 at the same bases of its
+// reverse complement.
+func (rna RNA) Frames(opts TranslateOptions) ([6]Protein, error) {
+	return frames(rna, rna.RevComp(), opts)
+}
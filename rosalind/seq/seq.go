@@ -0,0 +1,123 @@
+// Package seq implements typed DNA, RNA and protein sequences.
+//
+// Sequences are validated against their alphabet when parsed, which includes
+// the IUPAC ambiguity codes (e.g. N for any base, R for a purine and Y for a
+// pyrimidine). Lowercase letters are valid, and the case of each letter is
+// preserved by all operations.
+//
+//    Code  Bases    Complement
+//    A     A        T (U)
+//    C     C        G
+//    G     G        C
+//    T/U   T/U      A
+//    R     A G      Y
+//    Y     C T      R
+//    S     C G      S
+//    W     A T      W
+//    K     G T      M
+//    M     A C      K
+//    B     C G T    V
+//    D     A G T    H
+//    H     A C T    D
+//    V     A C G    B
+//    N     A C G T  N
+package seq
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// An AlphabetError reports an invalid character of a sequence.
+type AlphabetError struct {
+	// Sequence type; e.g. "DNA".
+	Type string
+	// Invalid character.
+	Char rune
+	// Position of the character in the sequence, starting at 1.
+	Pos int
+}
+
+func (e *AlphabetError) Error() string {
+	return fmt.Sprintf("seq: invalid %s character %q at position %d", e.Type, e.Char, e.Pos)
+}
+
+// An alphabet specifies the valid characters of a sequence type.
+type alphabet [256]bool
+
+// newAlphabet returns an alphabet of the provided uppercase characters and
+// their lowercase counterparts.
+func newAlphabet(chars string) *alphabet {
+	a := new(alphabet)
+	for i := 0; i < len(chars); i++ {
+		c := chars[i]
+		a[c] = true
+		if 'A' <= c && c <= 'Z' {
+			a[c+'a'-'A'] = true
+		}
+	}
+	return a
+}
+
+// Alphabets of the sequence types.
+var (
+	dnaAlphabet     = newAlphabet("ACGTRYSWKMBDHVN")
+	rnaAlphabet     = newAlphabet("ACGURYSWKMBDHVN")
+	proteinAlphabet = newAlphabet("ACDEFGHIKLMNPQRSTVWYBZJXUO*")
+)
+
+// validate validates s against the alphabet of the sequence type.
+func (a *alphabet) validate(typ, s string) error {
+	for i := 0; i < len(s); i++ {
+		if !a[s[i]] {
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			return &AlphabetError{Type: typ, Char: r, Pos: i + 1}
+		}
+	}
+	return nil
+}
+
+// dnaComplements and rnaComplements map from nucleotide codes to their
+// complements in DNA and RNA respectively. Other characters map to themselves.
+var dnaComplements, rnaComplements [256]byte
+
+func init() {
+	for i := range dnaComplements {
+		dnaComplements[i] = byte(i)
+	}
+	const pairs = "ATCGRYSSWWKMBVDHNN"
+	for i := 0; i < len(pairs); i += 2 {
+		a, b := pairs[i], pairs[i+1]
+		for _, lower := range []byte{0, 'a' - 'A'} {
+			dnaComplements[a+lower] = b + lower
+			dnaComplements[b+lower] = a + lower
+		}
+	}
+	rnaComplements = dnaComplements
+	for _, lower := range []byte{0, 'a' - 'A'} {
+		rnaComplements['A'+lower] = 'U' + lower
+		rnaComplements['U'+lower] = 'A' + lower
+		rnaComplements['T'+lower] = 'T' + lower
+	}
+}
+
+// complement returns the complement of the sequence, optionally reversed.
+func complement(s []byte, complements *[256]byte, reverse bool) []byte {
+	buf := make([]byte, len(s))
+	for i, c := range s {
+		j := i
+		if reverse {
+			j = len(s) - 1 - i
+		}
+		buf[j] = complements[c]
+	}
+	return buf
+}
+
+// upper returns the uppercase form of the ASCII letter c.
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - ('a' - 'A')
+	}
+	return c
+}
@@ -0,0 +1,226 @@
+package seq
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	golden := []struct {
+		typ   string
+		s     string
+		char  rune
+		pos   int
+		valid bool
+	}{
+		// i=0
+		{typ: "DNA", s: "ACGTRYSWKMBDHVNacgtryswkmbdhvn", valid: true},
+		// i=1
+		{typ: "DNA", s: "ACGU", char: 'U', pos: 4},
+		// i=2
+		{typ: "DNA", s: "AC GT", char: ' ', pos: 3},
+		// i=3
+		{typ: "DNA", s: "ACGTé", char: 'é', pos: 5},
+		// i=4
+		{typ: "RNA", s: "ACGURYSWKMBDHVNacgu", valid: true},
+		// i=5
+		{typ: "RNA", s: "ACGT", char: 'T', pos: 4},
+		// i=6
+		{typ: "protein", s: "MAMAPRTEINSTRING*xbzjuo", valid: true},
+		// i=7
+		{typ: "protein", s: "MA1", char: '1', pos: 3},
+	}
+	for i, g := range golden {
+		var err error
+		switch g.typ {
+		case "DNA":
+			_, err = ParseDNA(g.s)
+		case "RNA":
+			_, err = ParseRNA(g.s)
+		case "protein":
+			_, err = ParseProtein(g.s)
+		}
+		if g.valid {
+			if err != nil {
+				t.Errorf("i=%d: unexpected error; %v", i, err)
+			}
+			continue
+		}
+		e, ok := err.(*AlphabetError)
+		if !ok {
+			t.Errorf("i=%d: expected *AlphabetError, got %v.", i, err)
+			continue
+		}
+		if e.Type != g.typ || e.Char != g.char || e.Pos != g.pos {
+			t.Errorf("i=%d: expected invalid %s character %q at position %d, got %v.", i, g.typ, g.char, g.pos, e)
+		}
+	}
+}
+
+func TestComplement(t *testing.T) {
+	golden := []struct {
+		dna, comp string
+	}{
+		// i=0
+		{dna: "ACGT", comp: "TGCA"},
+		// i=1
+		{dna: "RYSWKMBDHVN", comp: "YRSWMKVHDBN"},
+		// i=2
+		{dna: "acgtrykmbdhv", comp: "tgcayrmkvhdb"},
+	}
+	for i, g := range golden {
+		dna, err := ParseDNA(g.dna)
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		if got := dna.Complement().String(); got != g.comp {
+			t.Errorf("i=%d: expected DNA complement %q, got %q.", i, g.comp, got)
+		}
+		// The complement of the complement is the original sequence.
+		if got := dna.RevComp().RevComp().String(); got != g.dna {
+			t.Errorf("i=%d: expected double reverse complement %q, got %q.", i, g.dna, got)
+		}
+		// RNA complements match DNA complements, with U in place of T.
+		rna := dna.Transcribe()
+		if got, want := rna.Complement().BackTranscribe().String(), g.comp; got != want {
+			t.Errorf("i=%d: expected RNA complement %q, got %q.", i, want, got)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	golden := []struct {
+		rna  string
+		want string
+		err  bool
+	}{
+		// i=0
+		{rna: "AUGuuuUAAGGG", want: "MF"},
+		// i=1
+		{rna: "AUGU", err: true},
+		// i=2
+		{rna: "AUGNNN", err: true},
+	}
+	for i, g := range golden {
+		rna, err := ParseRNA(g.rna)
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		prot, err := rna.Translate()
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error, got nil.", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if prot.String() != g.want {
+			t.Errorf("i=%d: expected %q, got %q.", i, g.want, prot)
+		}
+	}
+}
+
+func TestCodes(t *testing.T) {
+	for _, code := range Codes() {
+		if len(code.aminos) != 64 || len(code.starts) != 64 {
+			t.Errorf("ID=%d: invalid table lengths %d and %d; expected 64.", code.ID, len(code.aminos), len(code.starts))
+		}
+	}
+	golden := []struct {
+		id    int
+		codon string
+		amino byte
+		start bool
+	}{
+		// i=0
+		{id: 1, codon: "UGA", amino: '*'},
+		// i=1
+		{id: 1, codon: "CUG", amino: 'L', start: true},
+		// i=2
+		{id: 2, codon: "UGA", amino: 'W'},
+		// i=3
+		{id: 2, codon: "AGA", amino: '*'},
+		// i=4
+		{id: 2, codon: "AUA", amino: 'M', start: true},
+		// i=5
+		{id: 3, codon: "CUU", amino: 'T'},
+		// i=6
+		{id: 11, codon: "GTG", amino: 'V', start: true},
+		// i=7
+		{id: 11, codon: "CTN", amino: 'L'},
+		// i=8
+		{id: 11, codon: "ATN", amino: 'X'},
+		// i=9
+		{id: 1, codon: "UAR", amino: '*'},
+	}
+	for i, g := range golden {
+		code, err := Code(g.id)
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		amino, ok := code.Translate([]byte(g.codon))
+		if !ok || amino != g.amino {
+			t.Errorf("i=%d: expected %q, got %q (ok=%v).", i, g.amino, amino, ok)
+		}
+		if start := code.IsStart([]byte(g.codon)); start != g.start {
+			t.Errorf("i=%d: expected start codon %v, got %v.", i, g.start, start)
+		}
+		if stop := code.IsStop([]byte(g.codon)); stop != (g.amino == '*' && g.codon != "UAR") {
+			t.Errorf("i=%d: unexpected stop codon %v.", i, stop)
+		}
+	}
+	if _, err := Code(7); err == nil {
+		t.Errorf("expected error for unknown table ID 7, got nil.")
+	}
+}
+
+func TestTranslateWith(t *testing.T) {
+	golden := []struct {
+		dna  string
+		opts TranslateOptions
+		want string
+		err  bool
+	}{
+		// i=0
+		{dna: "GTGAAATGAGGG", want: "VK"},
+		// i=1
+		{dna: "GTGAAATGAGGG", opts: TranslateOptions{Start: true}, want: "VK"},
+		// i=2
+		{dna: "GTGAAATGAGGG", opts: TranslateOptions{Code: codes[11], Start: true}, want: "MK"},
+		// i=3
+		{dna: "GTGAAATGAGGG", opts: TranslateOptions{ThroughStops: true}, want: "VK*G"},
+		// i=4
+		{dna: "GTGAAATGAGGG", opts: TranslateOptions{Code: codes[2]}, want: "VKWG"},
+		// i=5
+		{dna: "ATGNNNCTNAA", err: true},
+		// i=6
+		{dna: "ATGNNNCTNAA", opts: TranslateOptions{Ambiguous: true}, want: "MXL"},
+	}
+	for i, g := range golden {
+		dna, err := ParseDNA(g.dna)
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		prot, err := dna.TranslateWith(g.opts)
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error, got nil.", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: %v", i, err)
+			continue
+		}
+		if prot.String() != g.want {
+			t.Errorf("i=%d: expected %q, got %q.", i, g.want, prot)
+		}
+		// RNA translation matches DNA translation.
+		prot, err = dna.Transcribe().TranslateWith(g.opts)
+		if err != nil || prot.String() != g.want {
+			t.Errorf("i=%d: expected RNA translation %q, got %q (err=%v).", i, g.want, prot, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package seq
+
+import "fmt"
+
+// TranslateOptions specify how nucleotide sequences are translated.
+type TranslateOptions struct {
+	// Code is the genetic code used for translation; the standard code if nil.
+	Code *GeneticCode
+	// Start specifies whether the first codon is translated as methionine if it
+	// is a start codon of the genetic code, as alternative start codons (e.g.
+	// GTG in bacteria) code for methionine at initiation.
+	Start bool
+	// ThroughStops specifies whether translation continues through stop codons,
+	// which are emitted as '*', rather than stopping at the first stop codon.
+	ThroughStops bool
+	// Ambiguous specifies whether codons containing ambiguity codes are
+	// translated, rather than reported as an error; see GeneticCode.Translate.
+	Ambiguous bool
+}
+
+// translate translates the nucleotide sequence into a protein. An incomplete
+// trailing codon is ignored.
+func translate(s []byte, opts TranslateOptions) (Protein, error) {
+	code := opts.Code
+	if code == nil {
+		code = standardCode
+	}
+	prot := make(Protein, 0, len(s)/3)
+	for i := 0; i+3 <= len(s); i += 3 {
+		codon := s[i : i+3]
+		var amino byte
+		var ok bool
+		if opts.Ambiguous {
+			amino, ok = code.Translate(codon)
+		} else {
+			amino, ok = code.translate(codon)
+		}
+		if !ok {
+			return nil, fmt.Errorf("seq: unable to translate codon %q at position %d", codon, i+1)
+		}
+		switch {
+		case i == 0 && opts.Start && code.IsStart(codon):
+			amino = 'M'
+		case amino == '*' && !opts.ThroughStops:
+			return prot, nil
+		}
+		prot = append(prot, amino)
+	}
+	return prot, nil
+}
+
+// frames translates the six reading frames of the nucleotide sequence; the
+// three forward frames starting at the first, second and third base, followed
+// by the same frames of the reverse complement.
+func frames(s, revc []byte, opts TranslateOptions) ([6]Protein, error) {
+	var prots [6]Protein
+	for i := 0; i < 6; i++ {
+		t := s
+		if i >= 3 {
+			t = revc
+		}
+		offset := i % 3
+		if offset > len(t) {
+			offset = len(t)
+		}
+		prot, err := translate(t[offset:], opts)
+		if err != nil {
+			return prots, err
+		}
+		prots[i] = prot
+	}
+	return prots, nil
+}